@@ -6,4 +6,4 @@ toolchain go1.24.13
 
 require golang.org/x/text v0.33.0
 
-require github.com/mattn/go-sqlite3 v1.14.33 // indirect
+require github.com/mattn/go-sqlite3 v1.14.33
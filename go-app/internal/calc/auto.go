@@ -0,0 +1,71 @@
+package calc
+
+import (
+	"errors"
+	"math"
+)
+
+// Auto loan assumptions.
+const (
+	DefaultAutoLoanMonths = 60
+	// AutoPaymentShare is the most of gross monthly income a car payment
+	// should take.
+	AutoPaymentShare = 0.12
+)
+
+// AutoLoanInput describes a vehicle bought with a loan.
+type AutoLoanInput struct {
+	VehiclePrice  float64 `json:"vehicle_price"`
+	DownPayment   float64 `json:"down_payment"`
+	TradeIn       float64 `json:"trade_in"`
+	InterestRate  float64 `json:"interest_rate"`  // annual, percent
+	TermMonths    int     `json:"term_months"`    // 0 uses DefaultAutoLoanMonths
+	MonthlyIncome float64 `json:"monthly_income"` // gross; 0 skips the affordability check
+}
+
+// AutoLoanResult is the cost of an auto loan and whether the payment fits
+// within AutoPaymentShare of income.
+type AutoLoanResult struct {
+	LoanAmount     Money   `json:"loan_amount"`
+	TermMonths     int     `json:"term_months"`
+	MonthlyPayment Money   `json:"monthly_payment"`
+	TotalPayments  Money   `json:"total_payments"`
+	TotalInterest  Money   `json:"total_interest"`
+	TrueCost       Money   `json:"true_cost"`       // price plus interest
+	MaxPayment     Money   `json:"max_payment"`     // AutoPaymentShare of monthly income
+	PaymentPercent float64 `json:"payment_percent"` // monthly payment as a percent of MaxPayment
+	Affordable     bool    `json:"affordable"`
+}
+
+// CalculateAutoLoan works out the payment and total cost of an auto loan.
+func CalculateAutoLoan(in AutoLoanInput) (*AutoLoanResult, error) {
+	loanAmount := in.VehiclePrice - in.DownPayment - in.TradeIn
+	if loanAmount <= 0 {
+		return nil, errors.New("down payment and trade-in must be less than the vehicle price")
+	}
+	termMonths := in.TermMonths
+	if termMonths <= 0 {
+		termMonths = DefaultAutoLoanMonths
+	}
+
+	loan := NewMoney(loanAmount)
+	monthly := CalculateMonthlyPayment(loanAmount, in.InterestRate, termMonths)
+	total := monthly * Money(termMonths)
+	interest := total - loan
+	result := &AutoLoanResult{
+		LoanAmount:     loan,
+		TermMonths:     termMonths,
+		MonthlyPayment: monthly,
+		TotalPayments:  total,
+		TotalInterest:  interest,
+		TrueCost:       NewMoney(in.VehiclePrice) + interest,
+	}
+	if in.MonthlyIncome > 0 {
+		result.MaxPayment = NewMoney(in.MonthlyIncome * AutoPaymentShare)
+		if result.MaxPayment > 0 {
+			result.PaymentPercent = math.Round(monthly.Float()/result.MaxPayment.Float()*1000) / 10
+		}
+		result.Affordable = monthly <= result.MaxPayment
+	}
+	return result, nil
+}
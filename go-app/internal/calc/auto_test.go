@@ -0,0 +1,56 @@
+package calc
+
+import "testing"
+
+func TestCalculateAutoLoan(t *testing.T) {
+	result, err := CalculateAutoLoan(AutoLoanInput{
+		VehiclePrice:  30000,
+		DownPayment:   5000,
+		TermMonths:    50,
+		MonthlyIncome: 5000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// At 0% the $25,000 loan is 50 payments of $500 with no interest
+	if result.LoanAmount != NewMoney(25000) || result.MonthlyPayment != NewMoney(500) || result.TotalInterest != 0 {
+		t.Errorf("expected $500 a month on $25,000 with no interest, got %s on %s with %s interest", result.MonthlyPayment, result.LoanAmount, result.TotalInterest)
+	}
+	if result.TrueCost != NewMoney(30000) {
+		t.Errorf("expected a true cost of 30000, got %s", result.TrueCost)
+	}
+	if result.MaxPayment != NewMoney(600) || result.PaymentPercent != 83.3 || !result.Affordable {
+		t.Errorf("expected $500 to be 83.3%% of a $600 limit, got %.1f%% of %s", result.PaymentPercent, result.MaxPayment)
+	}
+
+	withInterest, err := CalculateAutoLoan(AutoLoanInput{VehiclePrice: 30000, InterestRate: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withInterest.TermMonths != DefaultAutoLoanMonths || withInterest.TotalInterest <= 0 {
+		t.Errorf("expected interest over the default term, got %s over %d months", withInterest.TotalInterest, withInterest.TermMonths)
+	}
+	if withInterest.MaxPayment != 0 || withInterest.Affordable {
+		t.Errorf("expected no affordability check without income, got %+v", withInterest)
+	}
+
+	if _, err := CalculateAutoLoan(AutoLoanInput{VehiclePrice: 20000, DownPayment: 15000, TradeIn: 5000}); err == nil {
+		t.Error("expected an error when nothing is borrowed")
+	}
+}
+
+func TestCalculateAutoLoan_Reconciles(t *testing.T) {
+	result, err := CalculateAutoLoan(AutoLoanInput{VehiclePrice: 32000, DownPayment: 4000, InterestRate: 6.9, TermMonths: 72})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Totals are built from the cent-rounded payment, so they add up exactly
+	if result.TotalPayments != result.MonthlyPayment*72 || result.TotalPayments-result.TotalInterest != result.LoanAmount {
+		t.Errorf("expected 72 payments of %s to repay %s plus interest, got %s and %s interest",
+			result.MonthlyPayment, result.LoanAmount, result.TotalPayments, result.TotalInterest)
+	}
+	if result.TrueCost != NewMoney(32000)+result.TotalInterest {
+		t.Errorf("expected the price plus %s of interest, got %s", result.TotalInterest, result.TrueCost)
+	}
+}
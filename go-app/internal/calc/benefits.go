@@ -0,0 +1,24 @@
+package calc
+
+// 2024 pre-tax benefit contribution limits.
+const (
+	LimitHSASelf          = 4150.0
+	LimitHSAFamily        = 8300.0
+	LimitHSACatchUp       = 1000.0
+	HSACatchUpAge         = 55
+	LimitHealthFSA        = 3200.0
+	LimitDependentCareFSA = 5000.0
+)
+
+// HSALimit returns the annual HSA contribution limit for the coverage type
+// and age.
+func HSALimit(family bool, age int) float64 {
+	limit := LimitHSASelf
+	if family {
+		limit = LimitHSAFamily
+	}
+	if age >= HSACatchUpAge {
+		limit += LimitHSACatchUp
+	}
+	return limit
+}
@@ -0,0 +1,385 @@
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// DefaultBudgetRule is the key of the 50/30/20 rule.
+const DefaultBudgetRule = "50-30-20"
+
+// budgetPercentTolerance absorbs float error when checking that percents add up.
+const budgetPercentTolerance = 0.01
+
+// BudgetSubcategoryRule is a line within a budget category, as a percent of
+// net income.
+type BudgetSubcategoryRule struct {
+	Name    string  `json:"name"`
+	Percent float64 `json:"percent"`
+}
+
+// BudgetCategoryRule is one of a rule's three categories. Its subcategories,
+// when present, must add up to the category's percent.
+type BudgetCategoryRule struct {
+	Name          string                  `json:"name"`
+	Percent       float64                 `json:"percent"`
+	Subcategories []BudgetSubcategoryRule `json:"subcategories"`
+}
+
+// BudgetRule splits net income across needs, wants and savings. Rules that
+// don't follow that split, such as 70/20/10, rename the categories.
+type BudgetRule struct {
+	Key     string             `json:"key"`
+	Name    string             `json:"name"`
+	Needs   BudgetCategoryRule `json:"needs"`
+	Wants   BudgetCategoryRule `json:"wants"`
+	Savings BudgetCategoryRule `json:"savings"`
+}
+
+var defaultSavingsSubcategories = []BudgetSubcategoryRule{
+	{"Emergency Fund", 10}, {"Investments", 5}, {"Goals", 5},
+}
+
+// BudgetRules are the named budget rules, in display order.
+var BudgetRules = []BudgetRule{
+	{
+		Key:  "50-30-20",
+		Name: "50/30/20",
+		Needs: BudgetCategoryRule{Name: "Needs", Percent: 50, Subcategories: []BudgetSubcategoryRule{
+			{"Housing", 25}, {"Utilities", 5}, {"Groceries", 10}, {"Transportation", 10},
+		}},
+		Wants: BudgetCategoryRule{Name: "Wants", Percent: 30, Subcategories: []BudgetSubcategoryRule{
+			{"Dining Out", 5}, {"Subscriptions", 5}, {"Travel/Fun", 10}, {"Personal", 10},
+		}},
+		Savings: BudgetCategoryRule{Name: "Savings", Percent: 20, Subcategories: defaultSavingsSubcategories},
+	},
+	{
+		Key:  "60-20-20",
+		Name: "60/20/20 (High Cost of Living)",
+		Needs: BudgetCategoryRule{Name: "Needs", Percent: 60, Subcategories: []BudgetSubcategoryRule{
+			{"Housing", 35}, {"Utilities", 5}, {"Groceries", 10}, {"Transportation", 10},
+		}},
+		Wants: BudgetCategoryRule{Name: "Wants", Percent: 20, Subcategories: []BudgetSubcategoryRule{
+			{"Dining Out", 5}, {"Subscriptions", 3}, {"Travel/Fun", 6}, {"Personal", 6},
+		}},
+		Savings: BudgetCategoryRule{Name: "Savings", Percent: 20, Subcategories: defaultSavingsSubcategories},
+	},
+	{
+		Key:  "70-20-10",
+		Name: "70/20/10",
+		Needs: BudgetCategoryRule{Name: "Living Expenses", Percent: 70, Subcategories: []BudgetSubcategoryRule{
+			{"Housing", 30}, {"Utilities", 5}, {"Groceries", 10}, {"Transportation", 10}, {"Lifestyle", 15},
+		}},
+		Wants: BudgetCategoryRule{Name: "Debt & Giving", Percent: 10, Subcategories: []BudgetSubcategoryRule{
+			{"Extra Debt Payments", 5}, {"Giving", 5},
+		}},
+		Savings: BudgetCategoryRule{Name: "Savings", Percent: 20, Subcategories: []BudgetSubcategoryRule{
+			{"Emergency Fund", 10}, {"Investments", 10},
+		}},
+	},
+	{
+		Key:  "80-20",
+		Name: "80/20 (Pay Yourself First)",
+		Needs: BudgetCategoryRule{Name: "Spending", Percent: 80, Subcategories: []BudgetSubcategoryRule{
+			{"Housing", 35}, {"Utilities", 5}, {"Groceries", 10}, {"Transportation", 10}, {"Lifestyle", 20},
+		}},
+		Wants:   BudgetCategoryRule{Name: "Wants"},
+		Savings: BudgetCategoryRule{Name: "Savings", Percent: 20, Subcategories: defaultSavingsSubcategories},
+	},
+}
+
+// GetBudgetRule returns the named rule with the given key, or nil.
+func GetBudgetRule(key string) *BudgetRule {
+	for i := range BudgetRules {
+		if BudgetRules[i].Key == key {
+			rule := BudgetRules[i]
+			return &rule
+		}
+	}
+	return nil
+}
+
+// CustomBudgetRule builds a rule from custom percents, scaling the 50/30/20
+// subcategories to fit.
+func CustomBudgetRule(needs, wants, savings float64) BudgetRule {
+	base := GetBudgetRule(DefaultBudgetRule)
+	scale := func(c BudgetCategoryRule, percent float64) BudgetCategoryRule {
+		scaled := BudgetCategoryRule{Name: c.Name, Percent: percent}
+		for _, sub := range c.Subcategories {
+			scaled.Subcategories = append(scaled.Subcategories, BudgetSubcategoryRule{
+				Name:    sub.Name,
+				Percent: sub.Percent * percent / c.Percent,
+			})
+		}
+		return scaled
+	}
+	return BudgetRule{
+		Key:     "custom",
+		Name:    fmt.Sprintf("Custom %g/%g/%g", needs, wants, savings),
+		Needs:   scale(base.Needs, needs),
+		Wants:   scale(base.Wants, wants),
+		Savings: scale(base.Savings, savings),
+	}
+}
+
+// Validate checks that the categories add up to 100% of income and each
+// category's subcategories add up to the category.
+func (r BudgetRule) Validate() error {
+	total := r.Needs.Percent + r.Wants.Percent + r.Savings.Percent
+	if math.Abs(total-100) > budgetPercentTolerance {
+		return fmt.Errorf("budget percentages must add up to 100%%, got %g%%", total)
+	}
+	for _, c := range []BudgetCategoryRule{r.Needs, r.Wants, r.Savings} {
+		if c.Percent < 0 {
+			return fmt.Errorf("%s can't be negative", c.Name)
+		}
+		if len(c.Subcategories) == 0 {
+			continue
+		}
+		var sum float64
+		for _, sub := range c.Subcategories {
+			if sub.Name == "" {
+				return errors.New("every subcategory needs a name")
+			}
+			if sub.Percent < 0 {
+				return fmt.Errorf("%s can't be negative", sub.Name)
+			}
+			sum += sub.Percent
+		}
+		if math.Abs(sum-c.Percent) > budgetPercentTolerance {
+			return fmt.Errorf("%s subcategories must add up to %g%%, got %g%%", c.Name, c.Percent, sum)
+		}
+	}
+	return nil
+}
+
+// allocateCategory converts a category rule into monthly amounts.
+func allocateCategory(netMonthly float64, c BudgetCategoryRule) BudgetCategory {
+	monthly := netMonthly * c.Percent / 100
+	category := BudgetCategory{
+		Name:          c.Name,
+		Percent:       int(math.Round(c.Percent)),
+		Monthly:       NewMoney(monthly),
+		Weekly:        NewMoney(monthly / 4.33),
+		Daily:         NewMoney(monthly / 30),
+		Subcategories: []Subcategory{},
+	}
+	for _, sub := range c.Subcategories {
+		category.Subcategories = append(category.Subcategories, Subcategory{
+			Name:    sub.Name,
+			Percent: int(math.Round(sub.Percent)),
+			Monthly: NewMoney(netMonthly * sub.Percent / 100),
+		})
+	}
+	return category
+}
+
+// AllocateBudget splits net monthly income according to a budget rule,
+// returning an error if the rule doesn't add up.
+func AllocateBudget(netMonthly float64, rule BudgetRule) (*BudgetAllocation, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return &BudgetAllocation{
+		NetMonthly: NewMoney(netMonthly),
+		Needs:      allocateCategory(netMonthly, rule.Needs),
+		Wants:      allocateCategory(netMonthly, rule.Wants),
+		Savings:    allocateCategory(netMonthly, rule.Savings),
+	}, nil
+}
+
+// ExpenseClass is the budget category a line item belongs to.
+type ExpenseClass string
+
+// Expense classes.
+const (
+	ClassNeed    ExpenseClass = "need"
+	ClassWant    ExpenseClass = "want"
+	ClassSavings ExpenseClass = "savings"
+)
+
+// Keywords used to classify line items by name. Savings is checked first so
+// "emergency fund" and "extra loan payment" aren't mistaken for needs. Each
+// word must match a whole word of the name, or its plural; a trailing "*"
+// matches any word starting with the stem.
+var (
+	savingsKeywords = []string{
+		"saving*", "emergency", "401k", "ira", "roth", "invest*", "brokerage",
+		"retirement", "hsa", "sinking fund", "extra debt", "extra loan", "debt payoff", "down payment",
+	}
+	needKeywords = []string{
+		"rent", "mortgage", "utilit*", "electric*", "water", "gas", "heat", "heating", "grocer*", "insurance",
+		"car payment", "auto loan", "transport*", "transit", "fuel", "phone", "internet", "medical",
+		"healthcare", "doctor", "prescription", "childcare", "daycare", "tuition", "student loan",
+		"minimum", "child support", "property tax",
+	}
+)
+
+// expenseWords splits a line item name into lowercase words, keeping
+// "401(k)" together.
+func expenseWords(name string) []string {
+	name = strings.NewReplacer("(", "", ")", "").Replace(strings.ToLower(name))
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchesKeyword reports whether keyword's words appear in order, next to
+// each other, in words.
+func matchesKeyword(words []string, keyword string) bool {
+	want := strings.Fields(keyword)
+	for i := 0; i+len(want) <= len(words); i++ {
+		matched := true
+		for j, w := range want {
+			word := words[i+j]
+			if stem, ok := strings.CutSuffix(w, "*"); ok {
+				matched = strings.HasPrefix(word, stem)
+			} else {
+				matched = word == w || word == w+"s"
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyExpense guesses whether a line item is a need, want or savings from
+// its name, defaulting to a want.
+func ClassifyExpense(name string) ExpenseClass {
+	words := expenseWords(name)
+	for _, k := range savingsKeywords {
+		if matchesKeyword(words, k) {
+			return ClassSavings
+		}
+	}
+	for _, k := range needKeywords {
+		if matchesKeyword(words, k) {
+			return ClassNeed
+		}
+	}
+	return ClassWant
+}
+
+// BudgetLine is one planned monthly expense in a zero-based budget. An empty
+// Class is filled in by ClassifyExpense.
+type BudgetLine struct {
+	Name   string       `json:"name"`
+	Amount float64      `json:"amount"`
+	Class  ExpenseClass `json:"class,omitempty"`
+}
+
+// BudgetLineResult is a classified line item.
+type BudgetLineResult struct {
+	Name           string       `json:"name"`
+	Amount         Money        `json:"amount"`
+	Class          ExpenseClass `json:"class"`
+	AutoClassified bool         `json:"auto_classified"`
+}
+
+// CategoryVariance compares what a budget rule plans for a category with the
+// line items assigned to it. Variance is planned minus actual, so a negative
+// variance means the category is over budget. Only needs and wants are
+// flagged as over-allocated; saving more than planned is fine.
+type CategoryVariance struct {
+	Name           string       `json:"name"`
+	Class          ExpenseClass `json:"class"`
+	PlannedPercent int          `json:"planned_percent"`
+	ActualPercent  float64      `json:"actual_percent"`
+	Planned        Money        `json:"planned"`
+	Actual         Money        `json:"actual"`
+	Variance       Money        `json:"variance"`
+	OverAllocated  bool         `json:"over_allocated"`
+}
+
+// ZeroBasedBudget is a variance report for a zero-based budget, where every
+// dollar of income is assigned to a line item.
+type ZeroBasedBudget struct {
+	Rule          string             `json:"rule"`
+	NetMonthly    Money              `json:"net_monthly"`
+	Lines         []BudgetLineResult `json:"lines"`
+	Categories    []CategoryVariance `json:"categories"`
+	TotalAssigned Money              `json:"total_assigned"`
+	Unassigned    Money              `json:"unassigned"` // negative when more is assigned than earned
+	Balanced      bool               `json:"balanced"`   // every dollar assigned
+	OverAllocated []string           `json:"over_allocated"`
+}
+
+// CalculateZeroBasedBudget classifies line items and compares each category's
+// total with the budget rule. When the rule has no wants category (80/20),
+// wants count toward needs.
+func CalculateZeroBasedBudget(netMonthly float64, lines []BudgetLine, rule BudgetRule) (*ZeroBasedBudget, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	net := NewMoney(netMonthly)
+	result := &ZeroBasedBudget{
+		Rule:          rule.Name,
+		NetMonthly:    net,
+		OverAllocated: []string{},
+	}
+	actual := map[ExpenseClass]Money{}
+	var assigned Money
+	for _, line := range lines {
+		class, auto := line.Class, false
+		if class == "" {
+			class, auto = ClassifyExpense(line.Name), true
+		}
+		amount := NewMoney(line.Amount)
+		result.Lines = append(result.Lines, BudgetLineResult{
+			Name:           line.Name,
+			Amount:         amount,
+			Class:          class,
+			AutoClassified: auto,
+		})
+		if class == ClassWant && rule.Wants.Percent == 0 {
+			class = ClassNeed
+		}
+		actual[class] += amount
+		assigned += amount
+	}
+
+	categories := []struct {
+		class ExpenseClass
+		rule  BudgetCategoryRule
+	}{
+		{ClassNeed, rule.Needs},
+		{ClassWant, rule.Wants},
+		{ClassSavings, rule.Savings},
+	}
+	for _, c := range categories {
+		if c.rule.Percent == 0 && actual[c.class] == 0 {
+			continue
+		}
+		planned := net.Mul(c.rule.Percent / 100)
+		v := CategoryVariance{
+			Name:           c.rule.Name,
+			Class:          c.class,
+			PlannedPercent: int(math.Round(c.rule.Percent)),
+			Planned:        planned,
+			Actual:         actual[c.class],
+			Variance:       planned - actual[c.class],
+			OverAllocated:  c.class != ClassSavings && actual[c.class] > planned,
+		}
+		if net > 0 {
+			v.ActualPercent = math.Round(float64(actual[c.class])/float64(net)*1000) / 10
+		}
+		if v.OverAllocated {
+			result.OverAllocated = append(result.OverAllocated, v.Name)
+		}
+		result.Categories = append(result.Categories, v)
+	}
+
+	result.TotalAssigned = assigned
+	result.Unassigned = net - assigned
+	result.Balanced = result.Unassigned == 0
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package calc
+
+import "testing"
+
+func TestBudgetRules_Valid(t *testing.T) {
+	for _, rule := range BudgetRules {
+		if err := rule.Validate(); err != nil {
+			t.Errorf("%s: %v", rule.Key, err)
+		}
+	}
+	if GetBudgetRule("nope") != nil {
+		t.Error("expected nil for an unknown rule")
+	}
+}
+
+func TestAllocateBudget_NamedRule(t *testing.T) {
+	result, err := AllocateBudget(5000, *GetBudgetRule("70-20-10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Needs.Name != "Living Expenses" || result.Needs.Monthly != NewMoney(3500) {
+		t.Errorf("expected 3500 of living expenses, got %s %s", result.Needs.Name, result.Needs.Monthly)
+	}
+	if result.Wants.Monthly != NewMoney(500) || result.Savings.Monthly != NewMoney(1000) {
+		t.Errorf("expected 500 debt & giving and 1000 savings, got %s and %s", result.Wants.Monthly, result.Savings.Monthly)
+	}
+	if result.Needs.Subcategories[0].Name != "Housing" || result.Needs.Subcategories[0].Monthly != NewMoney(1500) {
+		t.Errorf("expected 1500 for housing, got %+v", result.Needs.Subcategories[0])
+	}
+}
+
+func TestAllocateBudget_Custom(t *testing.T) {
+	rule := CustomBudgetRule(55, 25, 20)
+	result, err := AllocateBudget(4000, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Needs.Percent != 55 || result.Needs.Monthly != NewMoney(2200) {
+		t.Errorf("expected needs 55%% / 2200, got %d%% / %s", result.Needs.Percent, result.Needs.Monthly)
+	}
+	var sum Money
+	for _, sub := range result.Needs.Subcategories {
+		sum += sub.Monthly
+	}
+	if sum != NewMoney(2200) {
+		t.Errorf("scaled needs subcategories should add up to 2200, got %s", sum)
+	}
+
+	if _, err := AllocateBudget(4000, CustomBudgetRule(60, 30, 20)); err == nil {
+		t.Error("expected an error for a rule adding up to 110%")
+	}
+}
+
+func TestAllocateBudget_UserSubcategories(t *testing.T) {
+	rule := *GetBudgetRule(DefaultBudgetRule)
+	rule.Savings.Subcategories = []BudgetSubcategoryRule{{"Roth IRA", 12}, {"House Fund", 8}}
+	result, err := AllocateBudget(5000, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Savings.Subcategories) != 2 || result.Savings.Subcategories[0].Monthly != NewMoney(600) {
+		t.Errorf("expected the user's subcategories, got %+v", result.Savings.Subcategories)
+	}
+
+	rule.Savings.Subcategories = []BudgetSubcategoryRule{{"Roth IRA", 12}}
+	if _, err := AllocateBudget(5000, rule); err == nil {
+		t.Error("expected an error when subcategories don't add up to the category")
+	}
+}
+
+func TestClassifyExpense(t *testing.T) {
+	tests := []struct {
+		name string
+		want ExpenseClass
+	}{
+		{"Rent", ClassNeed},
+		{"Groceries", ClassNeed},
+		{"Car Insurance", ClassNeed},
+		{"Emergency Fund", ClassSavings},
+		{"Roth IRA", ClassSavings},
+		{"Extra Loan Payment", ClassSavings},
+		{"Dining Out", ClassWant},
+		{"Netflix", ClassWant},
+		{"401(k)", ClassSavings},
+		{"Investments", ClassSavings},
+		{"Gas & Electric", ClassNeed},
+		{"Utilities", ClassNeed},
+		{"Student Loans", ClassNeed},
+		{"Health Insurance", ClassNeed},
+
+		// Keywords inside other words don't count
+		{"Vegas trip", ClassWant},
+		{"Parents' gift", ClassWant},
+		{"Emirates flight", ClassWant},
+		{"Health club", ClassWant},
+		{"Waterpark tickets", ClassWant},
+	}
+	for _, tt := range tests {
+		if got := ClassifyExpense(tt.name); got != tt.want {
+			t.Errorf("ClassifyExpense(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateZeroBasedBudget(t *testing.T) {
+	lines := []BudgetLine{
+		{Name: "Rent", Amount: 2200},
+		{Name: "Groceries", Amount: 500},
+		{Name: "Dining Out", Amount: 400},
+		{Name: "Gym", Amount: 100, Class: ClassNeed},
+		{Name: "401k", Amount: 600},
+	}
+	result, err := CalculateZeroBasedBudget(5000, lines, *GetBudgetRule(DefaultBudgetRule))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.TotalAssigned != NewMoney(3800) || result.Unassigned != NewMoney(1200) || result.Balanced {
+		t.Errorf("expected 3800 assigned and 1200 unassigned, got %s and %s", result.TotalAssigned, result.Unassigned)
+	}
+	if !result.Lines[0].AutoClassified || result.Lines[3].AutoClassified || result.Lines[3].Class != ClassNeed {
+		t.Errorf("expected explicit classes to be kept, got %+v", result.Lines)
+	}
+
+	needs := result.Categories[0]
+	if needs.Planned != NewMoney(2500) || needs.Actual != NewMoney(2800) || needs.Variance != NewMoney(-300) || !needs.OverAllocated {
+		t.Errorf("expected needs 300 over a 2500 plan, got %+v", needs)
+	}
+	if result.Categories[1].OverAllocated || result.Categories[1].Variance != NewMoney(1100) {
+		t.Errorf("expected wants 1100 under plan, got %+v", result.Categories[1])
+	}
+	if len(result.OverAllocated) != 1 || result.OverAllocated[0] != "Needs" {
+		t.Errorf("expected only needs flagged, got %v", result.OverAllocated)
+	}
+}
+
+func TestCalculateZeroBasedBudget_NoWantsCategory(t *testing.T) {
+	lines := []BudgetLine{{Name: "Rent", Amount: 3000}, {Name: "Concerts", Amount: 500}, {Name: "Savings", Amount: 1500}}
+	result, err := CalculateZeroBasedBudget(5000, lines, *GetBudgetRule("80-20"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Categories) != 2 || result.Categories[0].Actual != NewMoney(3500) {
+		t.Errorf("expected wants folded into spending, got %+v", result.Categories)
+	}
+	if !result.Balanced || len(result.OverAllocated) != 0 {
+		t.Errorf("expected a balanced budget with nothing flagged, got %+v", result)
+	}
+}
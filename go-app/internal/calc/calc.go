@@ -4,90 +4,141 @@ package calc
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"time"
 )
 
 // IncomeData represents projected income calculations from YTD data.
 type IncomeData struct {
-	GrossAnnual    int `json:"gross_annual"`
-	GrossMonthly   int `json:"gross_monthly"`
-	GrossWeekly    int `json:"gross_weekly"`
-	GrossDaily     int `json:"gross_daily"`
-	DaysWorked     int `json:"days_worked"`
-	MaxAutoPayment int `json:"max_auto_payment"`
-	MaxRent        int `json:"max_rent"`
+	GrossAnnual    Money `json:"gross_annual"`
+	GrossMonthly   Money `json:"gross_monthly"`
+	GrossWeekly    Money `json:"gross_weekly"`
+	GrossDaily     Money `json:"gross_daily"`
+	DaysWorked     int   `json:"days_worked"`
+	MaxAutoPayment Money `json:"max_auto_payment"`
+	MaxRent        Money `json:"max_rent"`
+
+	Steps []Step `json:"steps,omitempty"` // set by ExplainIncome
 }
 
 // PITIBreakdown represents the Principal, Interest, Taxes, and Insurance breakdown.
 type PITIBreakdown struct {
-	PrincipalInterest int `json:"principal_interest"`
-	PropertyTax       int `json:"property_tax"`
-	Insurance         int `json:"insurance"`
-	PMI               int `json:"pmi"`
-	TotalMonthly      int `json:"total_monthly"`
+	PrincipalInterest Money `json:"principal_interest"`
+	PropertyTax       Money `json:"property_tax"`
+	Insurance         Money `json:"insurance"`
+	PMI               Money `json:"pmi"`
+	TotalMonthly      Money `json:"total_monthly"`
 }
 
 // MortgageResult represents the full mortgage calculation result.
 type MortgageResult struct {
 	HomePrice          float64       `json:"home_price"`
-	DownPayment        int           `json:"down_payment"`
+	DownPayment        Money         `json:"down_payment"`
 	DownPaymentPercent float64       `json:"down_payment_percent"`
-	LoanAmount         int           `json:"loan_amount"`
+	LoanAmount         Money         `json:"loan_amount"`
 	InterestRate       float64       `json:"interest_rate"`
 	TermYears          int           `json:"term_years"`
 	PITI               PITIBreakdown `json:"piti"`
-	TotalPayments      int           `json:"total_payments"`
-	TotalInterest      int           `json:"total_interest"`
+	TotalPayments      Money         `json:"total_payments"` // every principal & interest payment
+	TotalInterest      Money         `json:"total_interest"`
+	Steps              []Step        `json:"steps,omitempty"` // set by ExplainMortgage
 }
 
 // TaxBreakdown represents the federal, state, and FICA tax calculations.
+// Amounts are exact to the cent, and totals are sums of their parts.
 type TaxBreakdown struct {
-	GrossAnnual      int     `json:"gross_annual"`
-	FederalTax       int     `json:"federal_tax"`
-	StateTax         int     `json:"state_tax"`
-	FICATax          int     `json:"fica_tax"`
-	SocialSecurity   int     `json:"social_security"`
-	Medicare         int     `json:"medicare"`
-	Retirement401k   int     `json:"retirement_401k"`
-	HealthInsurance  int     `json:"health_insurance"`
-	TotalDeductions  int     `json:"total_deductions"`
-	NetAnnual        int     `json:"net_annual"`
-	NetMonthly       int     `json:"net_monthly"`
+	GrossAnnual      Money   `json:"gross_annual"`
+	AGI              Money   `json:"agi"`
+	FederalTax       Money   `json:"federal_tax"`
+	StateTax         Money   `json:"state_tax"`
+	FICATax          Money   `json:"fica_tax"`
+	SocialSecurity   Money   `json:"social_security"`
+	Medicare         Money   `json:"medicare"`
+	Retirement401k   Money   `json:"retirement_401k"`
+	HealthInsurance  Money   `json:"health_insurance"`
+	TotalDeductions  Money   `json:"total_deductions"`
+	NetAnnual        Money   `json:"net_annual"`
+	NetMonthly       Money   `json:"net_monthly"`
 	EffectiveTaxRate float64 `json:"effective_tax_rate"`
+
+	// Pre-tax benefit contributions, capped at their annual limits
+	HSA              Money `json:"hsa"`
+	HealthFSA        Money `json:"health_fsa"`
+	DependentCareFSA Money `json:"dependent_care_fsa"`
+	// BenefitsTaxSaved is the income and FICA tax avoided by the contributions
+	// above, and BenefitsPaycheckChange the resulting change in monthly
+	// take-home pay (tax saved less contributions).
+	BenefitsTaxSaved       Money `json:"benefits_tax_saved"`
+	BenefitsPaycheckChange Money `json:"benefits_paycheck_change"`
+
+	// Investment income: realized gains plus qualified dividends, the 0/15/20%
+	// tax on long-term gains and dividends, and the net investment income tax.
+	// Short-term gains are taxed as ordinary income within FederalTax.
+	InvestmentIncome Money `json:"investment_income"`
+	CapitalGainsTax  Money `json:"capital_gains_tax"`
+	NIIT             Money `json:"niit"`
+
+	// Other income outside paycheck wages: net self-employment earnings plus
+	// passive income, and the self-employment tax owed on the former.
+	OtherIncome       Money `json:"other_income"`
+	SelfEmploymentTax Money `json:"self_employment_tax"`
+
+	Steps []Step `json:"steps,omitempty"` // set by ExplainTaxes
+}
+
+// TaxInput holds the paycheck inputs for CalculateTaxBreakdown.
+type TaxInput struct {
+	GrossAnnual           float64      `json:"gross_annual"`
+	Retirement401kPercent float64      `json:"retirement_401k_percent"`
+	HealthInsuranceAnnual float64      `json:"health_insurance_annual"`
+	StateTaxRate          float64      `json:"state_tax_rate"`
+	StateCode             string       `json:"state_code"`    // graduated states use their brackets instead of StateTaxRate
+	FilingStatus          FilingStatus `json:"filing_status"` // defaults to single
+
+	HSA               float64 `json:"hsa"`
+	HSAFamily         bool    `json:"hsa_family"`          // family rather than self-only HDHP coverage
+	HSAOutsidePayroll bool    `json:"hsa_outside_payroll"` // contributed directly rather than through a cafeteria plan
+	HealthFSA         float64 `json:"health_fsa"`
+	DependentCareFSA  float64 `json:"dependent_care_fsa"`
+	Age               int     `json:"age"` // for the HSA catch-up contribution
+
+	ShortTermGains        float64 `json:"short_term_gains"` // negative for a loss
+	LongTermGains         float64 `json:"long_term_gains"`
+	QualifiedDividends    float64 `json:"qualified_dividends"`
+	StateLTCGExclusionPct float64 `json:"state_ltcg_exclusion_pct"` // share of long-term gains the state exempts
+
+	SelfEmploymentIncome float64 `json:"self_employment_income"` // net 1099 earnings after business expenses
+	PassiveIncome        float64 `json:"passive_income"`         // interest, royalties and net rental income
 }
 
 // Subcategory represents a budget subcategory allocation.
 type Subcategory struct {
 	Name    string `json:"name"`
 	Percent int    `json:"percent"`
-	Monthly int    `json:"monthly"`
+	Monthly Money  `json:"monthly"`
 }
 
 // BudgetCategory represents a main budget category (needs, wants, or savings).
 type BudgetCategory struct {
 	Name          string        `json:"name"`
 	Percent       int           `json:"percent"`
-	Monthly       int           `json:"monthly"`
-	Weekly        int           `json:"weekly"`
-	Daily         int           `json:"daily"`
+	Monthly       Money         `json:"monthly"`
+	Weekly        Money         `json:"weekly"`
+	Daily         Money         `json:"daily"`
 	Subcategories []Subcategory `json:"subcategories"`
 }
 
-// BudgetAllocation represents the 50/30/20 budget allocation.
+// BudgetAllocation represents a needs/wants/savings budget allocation.
 type BudgetAllocation struct {
-	NetMonthly int            `json:"net_monthly"`
+	NetMonthly Money          `json:"net_monthly"`
 	Needs      BudgetCategory `json:"needs"`
 	Wants      BudgetCategory `json:"wants"`
 	Savings    BudgetCategory `json:"savings"`
 }
 
-// 2024 Federal Tax Brackets (Single filer)
-var taxBrackets = []struct {
-	Min  float64
-	Max  float64
-	Rate float64
-}{
+// 2024 Federal Tax Brackets (Single filer); see filingTable for other statuses
+var taxBrackets = []bracket{
 	{0, 11600, 0.10},
 	{11600, 47150, 0.12},
 	{47150, 100525, 0.22},
@@ -102,12 +153,31 @@ const (
 	StandardDeduction = 14600.0
 	// SSWageBase is the 2024 Social Security wage base limit.
 	SSWageBase = 168600.0
+	// SEEarningsFactor is the share of net self-employment income subject to
+	// SE tax, standing in for the employer half of FICA.
+	SEEarningsFactor = 0.9235
 )
 
 // CalculateIncome projects annual income from year-to-date income data.
 // It calculates daily rate based on the period from startDate to checkDate,
 // then extrapolates to annual, monthly, and weekly amounts.
 func CalculateIncome(ytdIncome float64, startDate, checkDate time.Time) (*IncomeData, error) {
+	return calculateIncome(ytdIncome, startDate, checkDate, nil)
+}
+
+// ExplainIncome projects income like CalculateIncome, recording each step
+// in the result's Steps.
+func ExplainIncome(ytdIncome float64, startDate, checkDate time.Time) (*IncomeData, error) {
+	tr := &trace{}
+	d, err := calculateIncome(ytdIncome, startDate, checkDate, tr)
+	if err != nil {
+		return nil, err
+	}
+	d.Steps = tr.steps
+	return d, nil
+}
+
+func calculateIncome(ytdIncome float64, startDate, checkDate time.Time, tr *trace) (*IncomeData, error) {
 	yearStart := time.Date(checkDate.Year(), 1, 1, 0, 0, 0, 0, checkDate.Location())
 
 	// Use effective start date (either startDate or Jan 1 of check year, whichever is later)
@@ -128,14 +198,21 @@ func CalculateIncome(ytdIncome float64, startDate, checkDate time.Time) (*Income
 	monthly := annual / 12
 	weekly := annual / 52
 
+	tr.add("Year-to-date income", ytdIncome, "From your paystub")
+	tr.add("Daily rate", daily, "%s ÷ %d days worked, %s to %s", usd(ytdIncome), days,
+		effectiveStart.Format("Jan 2, 2006"), checkDate.Format("Jan 2, 2006"))
+	tr.add("Annual income", annual, "Daily rate × 365 days")
+	tr.add("Monthly income", monthly, "Annual income ÷ 12")
+	tr.add("Weekly income", weekly, "Annual income ÷ 52")
+
 	return &IncomeData{
-		GrossAnnual:    int(math.Round(annual)),
-		GrossMonthly:   int(math.Round(monthly)),
-		GrossWeekly:    int(math.Round(weekly)),
-		GrossDaily:     int(math.Round(daily)),
+		GrossAnnual:    NewMoney(annual),
+		GrossMonthly:   NewMoney(monthly),
+		GrossWeekly:    NewMoney(weekly),
+		GrossDaily:     NewMoney(daily),
 		DaysWorked:     days,
-		MaxAutoPayment: int(math.Round(monthly * 0.12)),
-		MaxRent:        int(math.Round(monthly * 0.30)),
+		MaxAutoPayment: NewMoney(monthly * 0.12),
+		MaxRent:        NewMoney(monthly * 0.30),
 	}, nil
 }
 
@@ -157,19 +234,23 @@ func CalculateLoanAmount(monthlyPayment, annualRate float64, termMonths int) int
 
 // CalculateMonthlyPayment calculates the monthly payment for a loan using
 // the standard amortization formula.
-func CalculateMonthlyPayment(principal, annualRate float64, termMonths int) int {
+func CalculateMonthlyPayment(principal, annualRate float64, termMonths int) Money {
+	return NewMoney(amortizedPayment(principal, annualRate, termMonths))
+}
+
+// amortizedPayment returns the unrounded level payment that retires principal
+// over termMonths.
+func amortizedPayment(principal, annualRate float64, termMonths int) float64 {
 	monthlyRate := annualRate / 100 / 12
 
 	// Handle zero interest rate case
 	if monthlyRate == 0 {
-		return int(math.Round(principal / float64(termMonths)))
+		return principal / float64(termMonths)
 	}
 
 	// Monthly payment formula: PMT = P * [r(1+r)^n] / [(1+r)^n - 1]
 	factor := math.Pow(1+monthlyRate, float64(termMonths))
-	payment := principal * (monthlyRate * factor) / (factor - 1)
-
-	return int(math.Round(payment))
+	return principal * (monthlyRate * factor) / (factor - 1)
 }
 
 // CalculateMortgage computes a full PITI (Principal, Interest, Taxes, Insurance)
@@ -182,6 +263,26 @@ func CalculateMortgage(
 	propertyTaxRate float64,
 	annualInsurance float64,
 ) *MortgageResult {
+	return calculateMortgage(homePrice, downPaymentPercent, interestRate, termYears, propertyTaxRate, annualInsurance, nil)
+}
+
+// ExplainMortgage computes the mortgage like CalculateMortgage, recording
+// each step in the result's Steps.
+func ExplainMortgage(
+	homePrice float64,
+	downPaymentPercent float64,
+	interestRate float64,
+	termYears int,
+	propertyTaxRate float64,
+	annualInsurance float64,
+) *MortgageResult {
+	tr := &trace{}
+	m := calculateMortgage(homePrice, downPaymentPercent, interestRate, termYears, propertyTaxRate, annualInsurance, tr)
+	m.Steps = tr.steps
+	return m
+}
+
+func calculateMortgage(homePrice, downPaymentPercent, interestRate float64, termYears int, propertyTaxRate, annualInsurance float64, tr *trace) *MortgageResult {
 	downPayment := homePrice * (downPaymentPercent / 100)
 	loanAmount := homePrice - downPayment
 	termMonths := termYears * 12
@@ -205,25 +306,43 @@ func CalculateMortgage(
 		pmi = (loanAmount * 0.005) / 12
 	}
 
-	totalMonthly := principalInterest + propertyTax + insurance + pmi
-	totalPayments := principalInterest * float64(termMonths)
+	// Round each part to the cent, then build the totals from the parts
+	down, loan := NewMoney(downPayment), NewMoney(loanAmount)
+	piti := PITIBreakdown{
+		PrincipalInterest: NewMoney(principalInterest),
+		PropertyTax:       NewMoney(propertyTax),
+		Insurance:         NewMoney(insurance),
+		PMI:               NewMoney(pmi),
+	}
+	piti.TotalMonthly = piti.PrincipalInterest + piti.PropertyTax + piti.Insurance + piti.PMI
+	payments := piti.PrincipalInterest * Money(termMonths)
+
+	tr.add("Down payment", downPayment, "%s of %s", pct(downPaymentPercent/100), usd(homePrice))
+	tr.add("Loan amount", loanAmount, "%s less the down payment", usd(homePrice))
+	if monthlyRate == 0 {
+		tr.add("Principal & interest", principalInterest, "%s ÷ %d monthly payments, no interest", usd(loanAmount), termMonths)
+	} else {
+		tr.add("Principal & interest", principalInterest, "%s amortized over %d monthly payments at %s a year (%s a month)",
+			usd(loanAmount), termMonths, pct(interestRate/100), pct(monthlyRate))
+	}
+	tr.add("Property tax", propertyTax, "%s of %s a year ÷ 12", pct(propertyTaxRate/100), usd(homePrice))
+	tr.add("Insurance", insurance, "%s a year ÷ 12", usd(annualInsurance))
+	if pmi > 0 {
+		tr.add("PMI", pmi, "0.5%% of %s a year ÷ 12, since the down payment is under 20%%", usd(loanAmount))
+	}
+	tr.add("Monthly payment", piti.TotalMonthly.Float(), "Principal & interest + property tax + insurance + PMI")
+	tr.add("Total interest", (payments - loan).Float(), "%d payments of principal & interest less the %s borrowed", termMonths, usd(loanAmount))
 
 	return &MortgageResult{
 		HomePrice:          homePrice,
-		DownPayment:        int(math.Round(downPayment)),
+		DownPayment:        down,
 		DownPaymentPercent: downPaymentPercent,
-		LoanAmount:         int(math.Round(loanAmount)),
+		LoanAmount:         loan,
 		InterestRate:       interestRate,
 		TermYears:          termYears,
-		PITI: PITIBreakdown{
-			PrincipalInterest: int(math.Round(principalInterest)),
-			PropertyTax:       int(math.Round(propertyTax)),
-			Insurance:         int(math.Round(insurance)),
-			PMI:               int(math.Round(pmi)),
-			TotalMonthly:      int(math.Round(totalMonthly)),
-		},
-		TotalPayments: int(math.Round(totalPayments)),
-		TotalInterest: int(math.Round(totalPayments - loanAmount)),
+		PITI:               piti,
+		TotalPayments:      payments,
+		TotalInterest:      payments - loan,
 	}
 }
 
@@ -235,103 +354,220 @@ func CalculateTaxes(
 	healthInsuranceAnnual float64,
 	stateTaxRate float64,
 ) *TaxBreakdown {
-	// Calculate pre-tax deductions
-	retirement := grossAnnual * (retirement401kPercent / 100)
-	agi := grossAnnual - retirement - healthInsuranceAnnual
+	return CalculateTaxBreakdown(TaxInput{
+		GrossAnnual:           grossAnnual,
+		Retirement401kPercent: retirement401kPercent,
+		HealthInsuranceAnnual: healthInsuranceAnnual,
+		StateTaxRate:          stateTaxRate,
+	})
+}
 
-	// Federal tax calculation with standard deduction
-	taxableIncome := math.Max(0, agi-StandardDeduction)
-	var federalTax float64
-	remaining := taxableIncome
+// CalculateTaxBreakdown computes taxes like CalculateTaxes, adding HSA and FSA
+// contributions. Contributions are capped at their annual limits and reduce
+// income tax; those made through a cafeteria plan also avoid FICA.
+func CalculateTaxBreakdown(in TaxInput) *TaxBreakdown {
+	return calculateTaxBreakdown(in, nil)
+}
 
-	for _, bracket := range taxBrackets {
-		if remaining <= 0 {
-			break
-		}
-		taxableInBracket := math.Min(remaining, bracket.Max-bracket.Min)
-		federalTax += taxableInBracket * bracket.Rate
-		remaining -= taxableInBracket
+// ExplainTaxes computes the breakdown like CalculateTaxBreakdown, recording
+// each step in the result's Steps: AGI, the standard deduction, each
+// bracket's slice, FICA and the state tax.
+func ExplainTaxes(in TaxInput) *TaxBreakdown {
+	tr := &trace{}
+	t := calculateTaxBreakdown(in, tr)
+	t.Steps = tr.steps
+	return t
+}
+
+func calculateTaxBreakdown(in TaxInput, tr *trace) *TaxBreakdown {
+	hsa := NewMoney(math.Min(math.Max(0, in.HSA), HSALimit(in.HSAFamily, in.Age)))
+	healthFSA := NewMoney(math.Min(math.Max(0, in.HealthFSA), LimitHealthFSA))
+	dependentCare := NewMoney(math.Min(math.Max(0, in.DependentCareFSA), LimitDependentCareFSA))
+
+	// FSAs only exist inside a cafeteria plan; an HSA may be funded either way
+	ficaExempt := healthFSA + dependentCare
+	if !in.HSAOutsidePayroll {
+		ficaExempt += hsa
 	}
 
-	// State tax calculation (flat rate on AGI)
-	stateTax := agi * (stateTaxRate / 100)
+	t := calculateTaxes(in, (hsa + healthFSA + dependentCare).Float(), ficaExempt.Float(), tr)
+	t.HSA = hsa
+	t.HealthFSA = healthFSA
+	t.DependentCareFSA = dependentCare
+
+	if hsa+healthFSA+dependentCare > 0 {
+		base := calculateTaxes(in, 0, 0, nil)
+		baseTaxes := base.FederalTax + base.StateTax + base.FICATax
+		t.BenefitsTaxSaved = baseTaxes - (t.FederalTax + t.StateTax + t.FICATax)
+		t.BenefitsPaycheckChange = (t.NetAnnual - base.NetAnnual).Div(12)
+	}
+	return t
+}
+
+// calculateTaxes computes the breakdown with benefits pre-tax dollars excluded
+// from income tax, ficaExempt of them also excluded from FICA.
+func calculateTaxes(in TaxInput, benefits, ficaExempt float64, tr *trace) *TaxBreakdown {
+	grossAnnual := in.GrossAnnual
+
+	// Calculate pre-tax deductions
+	retirement := grossAnnual * (in.Retirement401kPercent / 100)
+	agi := grossAnnual - retirement - in.HealthInsuranceAnnual - benefits
 
 	// FICA calculations
-	ssTaxable := math.Min(grossAnnual, SSWageBase)
+	ficaWages := math.Max(0, grossAnnual-ficaExempt)
+	ssTaxable := math.Min(ficaWages, SSWageBase)
 	socialSecurity := ssTaxable * 0.062
-	medicare := grossAnnual * 0.0145
-	fica := socialSecurity + medicare
+	medicare := ficaWages * 0.0145
+
+	// Self-employment tax pays both halves of FICA, sharing the Social
+	// Security wage base with W-2 wages; half of it is deductible
+	seEarnings := math.Max(0, in.SelfEmploymentIncome) * SEEarningsFactor
+	seSocialSecurity := math.Min(seEarnings, math.Max(0, SSWageBase-ficaWages)) * 0.124
+	seTax := seSocialSecurity + seEarnings*0.029
+	otherIncome := in.SelfEmploymentIncome + in.PassiveIncome
+	agi += otherIncome - seTax/2
+
+	// Investment income: net short-term gains are ordinary income; long-term
+	// gains and qualified dividends are taxed at capital gains rates
+	ordinaryGains, longTermGains := netCapitalGains(in.ShortTermGains, in.LongTermGains)
+	preferential := longTermGains + math.Max(0, in.QualifiedDividends)
+	agi += ordinaryGains + preferential
+
+	// Federal tax calculation with standard deduction, preferential income
+	// stacked on top of ordinary income
+	taxableIncome := math.Max(0, agi-in.FilingStatus.StandardDeduction())
+	preferentialTaxable := math.Min(preferential, taxableIncome)
+	ordinaryTaxable := taxableIncome - preferentialTaxable
+	federalTax := federalIncomeTax(ordinaryTaxable, in.FilingStatus)
+	capitalGainsTax := preferentialTax(ordinaryTaxable, preferentialTaxable, in.FilingStatus)
+	niit := netInvestmentIncomeTax(ordinaryGains+preferential+in.PassiveIncome, agi, in.FilingStatus)
+
+	// State tax calculation (brackets or a flat rate on AGI, less any
+	// long-term gains exclusion)
+	stateBase := math.Max(0, agi-longTermGains*in.StateLTCGExclusionPct/100)
+	stateTax := stateIncomeTax(stateBase, in.StateCode, in.StateTaxRate, in.FilingStatus)
+
+	// Round each part to the cent, then build the totals from the parts
+	fed, cg, nii, state := NewMoney(federalTax), NewMoney(capitalGainsTax), NewMoney(niit), NewMoney(stateTax)
+	ss, med, se := NewMoney(socialSecurity), NewMoney(medicare), NewMoney(seTax)
+	ret, health := NewMoney(retirement), NewMoney(in.HealthInsuranceAnnual)
+	gross, other := NewMoney(grossAnnual), NewMoney(otherIncome)
+	investmentIncome := NewMoney(in.ShortTermGains + in.LongTermGains + math.Max(0, in.QualifiedDividends))
 
 	// Total deductions and net income
-	totalDeductions := federalTax + stateTax + fica + retirement + healthInsuranceAnnual
-	netAnnual := grossAnnual - totalDeductions
+	taxOnly := fed + cg + nii + state + ss + med + se
+	totalDeductions := taxOnly + ret + health + NewMoney(benefits)
+	netAnnual := gross + investmentIncome + other - totalDeductions
 
 	// Effective tax rate (taxes only, not retirement/health)
-	taxOnly := federalTax + stateTax + fica
-	effectiveTaxRate := math.Round((taxOnly/grossAnnual)*1000) / 10
+	totalIncome := gross + max(0, investmentIncome+other)
+	effectiveTaxRate := math.Round((taxOnly.Float()/totalIncome.Float())*1000) / 10
+
+	if tr != nil {
+		tr.add("Gross wages", grossAnnual, "")
+		if retirement > 0 {
+			tr.add("401(k) contribution", -retirement, "%s of gross wages", pct(in.Retirement401kPercent/100))
+		}
+		if in.HealthInsuranceAnnual > 0 {
+			tr.add("Health insurance premiums", -in.HealthInsuranceAnnual, "Pre-tax payroll deduction")
+		}
+		if benefits > 0 {
+			tr.add("HSA and FSA contributions", -benefits, "Pre-tax, capped at the annual limits")
+		}
+		if otherIncome != 0 {
+			tr.add("Self-employment and passive income", otherIncome, "")
+		}
+		if seTax > 0 {
+			tr.add("Half of self-employment tax", -seTax/2, "The employer-equivalent half is deductible")
+		}
+		if ordinaryGains+preferential != 0 {
+			tr.add("Capital gains and dividends", ordinaryGains+preferential, "")
+		}
+		tr.add("Adjusted gross income (AGI)", agi, "Income less pre-tax deductions")
+		tr.add("Standard deduction", -in.FilingStatus.StandardDeduction(), "%s filer", in.FilingStatus.Label())
+		tr.add("Taxable income", taxableIncome, "AGI less the standard deduction, not below zero")
+		traceBrackets(tr, ordinaryTaxable, in.FilingStatus)
+		tr.add("Federal income tax", federalTax, "Sum of the bracket amounts")
+		if capitalGainsTax > 0 {
+			tr.add("Capital gains tax", capitalGainsTax, "%s of long-term gains and qualified dividends at 0%%, 15%% or 20%%", usd(preferentialTaxable))
+		}
+		if niit > 0 {
+			tr.add("Net investment income tax", niit, "%s of investment income over the threshold", pct(NIITRate))
+		}
+		tr.add("Social Security", socialSecurity, "6.2%% of %s in wages, up to the %s wage base", usd(ssTaxable), usd(SSWageBase))
+		tr.add("Medicare", medicare, "1.45%% of %s in wages", usd(ficaWages))
+		if seTax > 0 {
+			tr.add("Self-employment tax", seTax, "Both halves of Social Security and Medicare on %s of net earnings", usd(seEarnings))
+		}
+		if HasStateBrackets(in.StateCode) {
+			tr.add("State income tax", stateTax, "%s brackets on %s less a %s deduction",
+				in.StateCode, usd(stateBase), usd(stateDeduction(in.StateCode, in.FilingStatus)))
+		} else {
+			tr.add("State income tax", stateTax, "%s of %s", pct(in.StateTaxRate/100), usd(stateBase))
+		}
+		tr.add("Take-home pay", netAnnual.Float(), "Income less taxes and pre-tax deductions")
+	}
 
 	return &TaxBreakdown{
-		GrossAnnual:      int(math.Round(grossAnnual)),
-		FederalTax:       int(math.Round(federalTax)),
-		StateTax:         int(math.Round(stateTax)),
-		FICATax:          int(math.Round(fica)),
-		SocialSecurity:   int(math.Round(socialSecurity)),
-		Medicare:         int(math.Round(medicare)),
-		Retirement401k:   int(math.Round(retirement)),
-		HealthInsurance:  int(math.Round(healthInsuranceAnnual)),
-		TotalDeductions:  int(math.Round(totalDeductions)),
-		NetAnnual:        int(math.Round(netAnnual)),
-		NetMonthly:       int(math.Round(netAnnual / 12)),
-		EffectiveTaxRate: effectiveTaxRate,
+		GrossAnnual:       gross,
+		AGI:               NewMoney(agi),
+		FederalTax:        fed,
+		StateTax:          state,
+		FICATax:           ss + med,
+		SocialSecurity:    ss,
+		Medicare:          med,
+		Retirement401k:    ret,
+		HealthInsurance:   health,
+		TotalDeductions:   totalDeductions,
+		NetAnnual:         netAnnual,
+		NetMonthly:        netAnnual.Div(12),
+		EffectiveTaxRate:  effectiveTaxRate,
+		InvestmentIncome:  investmentIncome,
+		CapitalGainsTax:   cg,
+		NIIT:              nii,
+		OtherIncome:       other,
+		SelfEmploymentTax: se,
+	}
+}
+
+// federalIncomeTax applies the federal brackets for a filing status to
+// taxable income (after the standard deduction).
+func federalIncomeTax(taxableIncome float64, status FilingStatus) float64 {
+	var tax float64
+	remaining := taxableIncome
+
+	for _, bracket := range status.rules().brackets {
+		if remaining <= 0 {
+			break
+		}
+		taxableInBracket := math.Min(remaining, bracket.Max-bracket.Min)
+		tax += taxableInBracket * bracket.Rate
+		remaining -= taxableInBracket
+	}
+	return tax
+}
+
+// traceBrackets records the tax on each federal bracket's slice of taxable
+// income, as federalIncomeTax adds it up.
+func traceBrackets(tr *trace, taxableIncome float64, status FilingStatus) {
+	remaining := taxableIncome
+	for _, bracket := range status.rules().brackets {
+		if remaining <= 0 {
+			break
+		}
+		taxableInBracket := math.Min(remaining, bracket.Max-bracket.Min)
+		span := fmt.Sprintf("from %s to %s", usd(bracket.Min), usd(bracket.Max))
+		if bracket.Max == math.MaxFloat64 {
+			span = "over " + usd(bracket.Min)
+		}
+		tr.add(pct(bracket.Rate)+" bracket", taxableInBracket*bracket.Rate, "%s of income %s × %s", usd(taxableInBracket), span, pct(bracket.Rate))
+		remaining -= taxableInBracket
 	}
 }
 
 // CalculateBudgetAllocation creates a 50/30/20 budget allocation based on
-// net monthly income.
+// net monthly income. Use AllocateBudget for other rules.
 func CalculateBudgetAllocation(netMonthly float64) *BudgetAllocation {
-	needsMonthly := netMonthly * 0.5
-	wantsMonthly := netMonthly * 0.3
-	savingsMonthly := netMonthly * 0.2
-
-	return &BudgetAllocation{
-		NetMonthly: int(math.Round(netMonthly)),
-		Needs: BudgetCategory{
-			Name:    "Needs",
-			Percent: 50,
-			Monthly: int(math.Round(needsMonthly)),
-			Weekly:  int(math.Round(needsMonthly / 4.33)),
-			Daily:   int(math.Round(needsMonthly / 30)),
-			Subcategories: []Subcategory{
-				{Name: "Housing", Percent: 25, Monthly: int(math.Round(netMonthly * 0.25))},
-				{Name: "Utilities", Percent: 5, Monthly: int(math.Round(netMonthly * 0.05))},
-				{Name: "Groceries", Percent: 10, Monthly: int(math.Round(netMonthly * 0.10))},
-				{Name: "Transportation", Percent: 10, Monthly: int(math.Round(netMonthly * 0.10))},
-			},
-		},
-		Wants: BudgetCategory{
-			Name:    "Wants",
-			Percent: 30,
-			Monthly: int(math.Round(wantsMonthly)),
-			Weekly:  int(math.Round(wantsMonthly / 4.33)),
-			Daily:   int(math.Round(wantsMonthly / 30)),
-			Subcategories: []Subcategory{
-				{Name: "Dining Out", Percent: 5, Monthly: int(math.Round(netMonthly * 0.05))},
-				{Name: "Subscriptions", Percent: 5, Monthly: int(math.Round(netMonthly * 0.05))},
-				{Name: "Travel/Fun", Percent: 10, Monthly: int(math.Round(netMonthly * 0.10))},
-				{Name: "Personal", Percent: 10, Monthly: int(math.Round(netMonthly * 0.10))},
-			},
-		},
-		Savings: BudgetCategory{
-			Name:    "Savings",
-			Percent: 20,
-			Monthly: int(math.Round(savingsMonthly)),
-			Weekly:  int(math.Round(savingsMonthly / 4.33)),
-			Daily:   int(math.Round(savingsMonthly / 30)),
-			Subcategories: []Subcategory{
-				{Name: "Emergency Fund", Percent: 10, Monthly: int(math.Round(netMonthly * 0.10))},
-				{Name: "Investments", Percent: 5, Monthly: int(math.Round(netMonthly * 0.05))},
-				{Name: "Goals", Percent: 5, Monthly: int(math.Round(netMonthly * 0.05))},
-			},
-		},
-	}
+	b, _ := AllocateBudget(netMonthly, *GetBudgetRule(DefaultBudgetRule))
+	return b
 }
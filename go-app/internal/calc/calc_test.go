@@ -21,9 +21,9 @@ func TestCalculateIncome(t *testing.T) {
 	}
 
 	// Expected annual: (50000 / 182) * 365 = ~100,274
-	expectedAnnual := int(math.Round((50000.0 / 182.0) * 365.0))
+	expectedAnnual := NewMoney((50000.0 / 182.0) * 365.0)
 	if result.GrossAnnual != expectedAnnual {
-		t.Errorf("expected gross annual %d, got %d", expectedAnnual, result.GrossAnnual)
+		t.Errorf("expected gross annual %s, got %s", expectedAnnual, result.GrossAnnual)
 	}
 }
 
@@ -102,8 +102,8 @@ func TestCalculateMonthlyPayment(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			got := CalculateMonthlyPayment(tt.principal, tt.annualRate, tt.termMonths)
 			// Allow $5 tolerance for rounding
-			if math.Abs(float64(got-tt.wantApprox)) > 5 {
-				t.Errorf("CalculateMonthlyPayment() = %d, want ~%d", got, tt.wantApprox)
+			if math.Abs(got.Float()-float64(tt.wantApprox)) > 5 {
+				t.Errorf("CalculateMonthlyPayment() = %s, want ~%d", got, tt.wantApprox)
 			}
 		})
 	}
@@ -120,27 +120,32 @@ func TestCalculateMortgage(t *testing.T) {
 	)
 
 	// Verify down payment
-	if result.DownPayment != 80000 {
-		t.Errorf("expected down payment 80000, got %d", result.DownPayment)
+	if result.DownPayment != NewMoney(80000) {
+		t.Errorf("expected down payment 80000, got %s", result.DownPayment)
 	}
 
 	// Verify loan amount
-	if result.LoanAmount != 320000 {
-		t.Errorf("expected loan amount 320000, got %d", result.LoanAmount)
+	if result.LoanAmount != NewMoney(320000) {
+		t.Errorf("expected loan amount 320000, got %s", result.LoanAmount)
 	}
 
 	// PMI should be 0 since down payment is 20%
 	if result.PITI.PMI != 0 {
-		t.Errorf("expected PMI 0 for 20%% down, got %d", result.PITI.PMI)
+		t.Errorf("expected PMI 0 for 20%% down, got %s", result.PITI.PMI)
 	}
 
 	// Verify total monthly is sum of components
 	expectedTotal := result.PITI.PrincipalInterest + result.PITI.PropertyTax +
 		result.PITI.Insurance + result.PITI.PMI
 	if result.PITI.TotalMonthly != expectedTotal {
-		t.Errorf("total monthly %d doesn't match sum of components %d",
+		t.Errorf("total monthly %s doesn't match sum of components %s",
 			result.PITI.TotalMonthly, expectedTotal)
 	}
+
+	// 360 payments of 2,022.62 less the 320,000 borrowed
+	if result.TotalPayments != result.PITI.PrincipalInterest*360 || result.TotalInterest != NewMoney(408143.20) {
+		t.Errorf("expected 360 payments and 408143.20 of interest, got %s and %s", result.TotalPayments, result.TotalInterest)
+	}
 }
 
 func TestCalculateMortgage_WithPMI(t *testing.T) {
@@ -168,38 +173,38 @@ func TestCalculateTaxes(t *testing.T) {
 	)
 
 	// Verify gross annual
-	if result.GrossAnnual != 100000 {
-		t.Errorf("expected gross annual 100000, got %d", result.GrossAnnual)
+	if result.GrossAnnual != NewMoney(100000) {
+		t.Errorf("expected gross annual 100000, got %v", result.GrossAnnual)
 	}
 
 	// Verify 401k deduction
-	expected401k := 6000 // 6% of 100000
+	expected401k := NewMoney(6000) // 6% of 100000
 	if result.Retirement401k != expected401k {
-		t.Errorf("expected 401k %d, got %d", expected401k, result.Retirement401k)
+		t.Errorf("expected 401k %v, got %v", expected401k, result.Retirement401k)
 	}
 
 	// Verify health insurance
-	if result.HealthInsurance != 3600 {
-		t.Errorf("expected health insurance 3600, got %d", result.HealthInsurance)
+	if result.HealthInsurance != NewMoney(3600) {
+		t.Errorf("expected health insurance 3600, got %v", result.HealthInsurance)
 	}
 
 	// Verify FICA components add up
 	expectedFICA := result.SocialSecurity + result.Medicare
 	if result.FICATax != expectedFICA {
-		t.Errorf("FICA %d doesn't match SS + Medicare %d", result.FICATax, expectedFICA)
+		t.Errorf("FICA %v doesn't match SS + Medicare %v", result.FICATax, expectedFICA)
 	}
 
 	// Verify net annual is consistent
 	expectedNet := result.GrossAnnual - result.TotalDeductions
 	if result.NetAnnual != expectedNet {
-		t.Errorf("net annual %d doesn't match gross - deductions %d",
+		t.Errorf("net annual %v doesn't match gross - deductions %v",
 			result.NetAnnual, expectedNet)
 	}
 
-	// Verify net monthly is within rounding tolerance of net annual / 12
-	expectedNetMonthly := int(math.Round(float64(result.NetAnnual) / 12))
+	// Verify net monthly is net annual / 12, to the cent
+	expectedNetMonthly := result.NetAnnual.Div(12)
 	if result.NetMonthly != expectedNetMonthly {
-		t.Errorf("net monthly %d doesn't match net annual / 12 %d",
+		t.Errorf("net monthly %v doesn't match net annual / 12 %v",
 			result.NetMonthly, expectedNetMonthly)
 	}
 }
@@ -214,9 +219,68 @@ func TestCalculateTaxes_HighIncome(t *testing.T) {
 	)
 
 	// Social security should be capped at SS wage base
-	expectedSS := int(math.Round(168600.0 * 0.062))
+	expectedSS := NewMoney(168600.0 * 0.062)
 	if result.SocialSecurity != expectedSS {
-		t.Errorf("expected SS capped at %d, got %d", expectedSS, result.SocialSecurity)
+		t.Errorf("expected SS capped at %v, got %v", expectedSS, result.SocialSecurity)
+	}
+}
+
+func TestCalculateTaxBreakdown_Benefits(t *testing.T) {
+	result := CalculateTaxBreakdown(TaxInput{
+		GrossAnnual:      80000,
+		StateTaxRate:     5,
+		HSA:              5000, // above the self-only limit
+		HealthFSA:        3200,
+		DependentCareFSA: 5000,
+	})
+
+	if result.HSA != NewMoney(4150) {
+		t.Errorf("expected HSA capped at 4150, got %v", result.HSA)
+	}
+	if result.AGI != NewMoney(80000-4150-3200-5000) {
+		t.Errorf("expected AGI %d, got %v", 80000-4150-3200-5000, result.AGI)
+	}
+
+	// Cafeteria plan contributions skip FICA: 7.65% of (80,000 - 12,350)
+	if result.FICATax.Dollars() != 5175 {
+		t.Errorf("expected FICA 5175, got %v", result.FICATax)
+	}
+
+	// 22% federal + 5% state + 7.65% FICA on 12,350 = 4,279
+	if diff := result.BenefitsTaxSaved.Dollars() - 4279; diff < -2 || diff > 2 {
+		t.Errorf("expected about 4279 tax saved, got %v", result.BenefitsTaxSaved)
+	}
+	if diff := result.BenefitsPaycheckChange.Dollars() - (4279-12350)/12; diff < -1 || diff > 1 {
+		t.Errorf("expected paycheck change about %d/mo, got %v", (4279-12350)/12, result.BenefitsPaycheckChange)
+	}
+	if expected := result.GrossAnnual - result.TotalDeductions; result.NetAnnual != expected {
+		t.Errorf("net annual %v doesn't match gross - deductions %v", result.NetAnnual, expected)
+	}
+}
+
+func TestCalculateTaxBreakdown_HSAOutsidePayroll(t *testing.T) {
+	base := CalculateTaxes(80000, 0, 0, 5)
+	result := CalculateTaxBreakdown(TaxInput{
+		GrossAnnual:       80000,
+		StateTaxRate:      5,
+		HSA:               9500,
+		HSAFamily:         true,
+		Age:               56,
+		HSAOutsidePayroll: true,
+	})
+
+	if result.HSA != NewMoney(9300) {
+		t.Errorf("expected family limit plus catch-up of 9300, got %v", result.HSA)
+	}
+	if result.FICATax != base.FICATax {
+		t.Errorf("direct HSA contributions shouldn't reduce FICA, got %v vs %v", result.FICATax, base.FICATax)
+	}
+	// 22% federal + 5% state on 9,300
+	if diff := result.BenefitsTaxSaved.Dollars() - 2511; diff < -2 || diff > 2 {
+		t.Errorf("expected about 2511 tax saved, got %v", result.BenefitsTaxSaved)
+	}
+	if base.BenefitsTaxSaved != 0 || base.HSA != 0 {
+		t.Error("CalculateTaxes should report no benefits")
 	}
 }
 
@@ -224,8 +288,8 @@ func TestCalculateBudgetAllocation(t *testing.T) {
 	result := CalculateBudgetAllocation(5000)
 
 	// Verify net monthly
-	if result.NetMonthly != 5000 {
-		t.Errorf("expected net monthly 5000, got %d", result.NetMonthly)
+	if result.NetMonthly != NewMoney(5000) {
+		t.Errorf("expected net monthly 5000, got %s", result.NetMonthly)
 	}
 
 	// Verify 50/30/20 split
@@ -240,14 +304,14 @@ func TestCalculateBudgetAllocation(t *testing.T) {
 	}
 
 	// Verify monthly allocations
-	if result.Needs.Monthly != 2500 {
-		t.Errorf("expected needs monthly 2500, got %d", result.Needs.Monthly)
+	if result.Needs.Monthly != NewMoney(2500) {
+		t.Errorf("expected needs monthly 2500, got %s", result.Needs.Monthly)
 	}
-	if result.Wants.Monthly != 1500 {
-		t.Errorf("expected wants monthly 1500, got %d", result.Wants.Monthly)
+	if result.Wants.Monthly != NewMoney(1500) {
+		t.Errorf("expected wants monthly 1500, got %s", result.Wants.Monthly)
 	}
-	if result.Savings.Monthly != 1000 {
-		t.Errorf("expected savings monthly 1000, got %d", result.Savings.Monthly)
+	if result.Savings.Monthly != NewMoney(1000) {
+		t.Errorf("expected savings monthly 1000, got %s", result.Savings.Monthly)
 	}
 
 	// Verify subcategories are present
@@ -272,7 +336,7 @@ func TestLoanCalculations_Roundtrip(t *testing.T) {
 	payment := CalculateMonthlyPayment(principal, rate, term)
 
 	// Calculate loan amount from that payment
-	calculatedPrincipal := CalculateLoanAmount(float64(payment), rate, term)
+	calculatedPrincipal := CalculateLoanAmount(payment.Float(), rate, term)
 
 	// Should be within $100 due to rounding
 	if math.Abs(float64(calculatedPrincipal)-principal) > 100 {
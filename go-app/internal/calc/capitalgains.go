@@ -0,0 +1,141 @@
+package calc
+
+import "math"
+
+// 2024 investment income tax constants (single filer).
+const (
+	NIITRate           = 0.038
+	NIITThreshold      = 200000.0
+	CapitalLossLimit   = 3000.0 // net capital loss deductible against ordinary income
+	LongTermHoldMonths = 12     // held more than this many months is long-term
+)
+
+// capitalGainsBrackets are the 0/15/20% brackets for long-term gains and
+// qualified dividends, applied to taxable income stacked on ordinary income.
+var capitalGainsBrackets = []bracket{
+	{0, 47025, 0},
+	{47025, 518900, 0.15},
+	{518900, math.MaxFloat64, 0.20},
+}
+
+// netCapitalGains nets short- and long-term gains against each other. It
+// returns the amount taxed as ordinary income (negative for a deductible
+// loss, limited to CapitalLossLimit) and the net long-term gain.
+func netCapitalGains(shortTerm, longTerm float64) (ordinary, preferential float64) {
+	switch {
+	case shortTerm >= 0 && longTerm >= 0:
+		return shortTerm, longTerm
+	case shortTerm+longTerm < 0:
+		return math.Max(shortTerm+longTerm, -CapitalLossLimit), 0
+	case shortTerm < 0:
+		return 0, longTerm + shortTerm
+	default:
+		return shortTerm + longTerm, 0
+	}
+}
+
+// preferentialTax applies the capital gains brackets to preferential income
+// stacked on top of ordinary taxable income.
+func preferentialTax(ordinaryTaxable, preferentialTaxable float64, status FilingStatus) float64 {
+	var tax float64
+	lo, hi := ordinaryTaxable, ordinaryTaxable+preferentialTaxable
+	for _, bracket := range status.rules().capitalGains {
+		overlap := math.Min(hi, bracket.Max) - math.Max(lo, bracket.Min)
+		if overlap > 0 {
+			tax += overlap * bracket.Rate
+		}
+	}
+	return tax
+}
+
+// netInvestmentIncomeTax returns the 3.8% NIIT on the lesser of investment
+// income and MAGI above the threshold.
+func netInvestmentIncomeTax(investmentIncome, magi float64, status FilingStatus) float64 {
+	excess := math.Max(0, magi-status.rules().niitThreshold)
+	return math.Min(math.Max(0, investmentIncome), excess) * NIITRate
+}
+
+// SaleInput describes a sale of shares for CalculateSaleTax. Other holds the
+// seller's other income for the year, which sets the brackets the gain
+// stacks on.
+type SaleInput struct {
+	Shares     float64  `json:"shares"`
+	SalePrice  float64  `json:"sale_price"` // per share
+	CostBasis  float64  `json:"cost_basis"` // per share
+	MonthsHeld int      `json:"months_held"`
+	Other      TaxInput `json:"other"`
+}
+
+// SaleTax is the tax due on a sale of shares.
+type SaleTax struct {
+	Proceeds      Money   `json:"proceeds"`
+	Basis         Money   `json:"basis"`
+	Gain          Money   `json:"gain"`
+	LongTerm      bool    `json:"long_term"`
+	FederalTax    Money   `json:"federal_tax"` // ordinary or capital gains rate, excluding NIIT
+	NIIT          Money   `json:"niit"`
+	StateTax      Money   `json:"state_tax"`
+	TotalTax      Money   `json:"total_tax"`
+	EffectiveRate float64 `json:"effective_rate"` // total tax as a percent of the gain
+	NetProceeds   Money   `json:"net_proceeds"`
+
+	// For short-term sales, what waiting until the gain is long-term would
+	// save.
+	MonthsUntilLongTerm int   `json:"months_until_long_term,omitempty"`
+	LongTermTotalTax    Money `json:"long_term_total_tax,omitempty"`
+	WaitingSaves        Money `json:"waiting_saves,omitempty"`
+}
+
+// saleTaxes returns the federal (excluding NIIT), NIIT and state tax added by
+// a gain on top of the seller's other income.
+func saleTaxes(other TaxInput, gain float64, longTerm bool) (federal, niit, state Money) {
+	base := CalculateTaxBreakdown(other)
+	with := other
+	if longTerm {
+		with.LongTermGains += gain
+	} else {
+		with.ShortTermGains += gain
+	}
+	t := CalculateTaxBreakdown(with)
+	federal = t.FederalTax + t.CapitalGainsTax - base.FederalTax - base.CapitalGainsTax
+	niit = t.NIIT - base.NIIT
+	state = t.StateTax - base.StateTax
+	return federal, niit, state
+}
+
+// CalculateSaleTax answers "how much tax if I sell these shares": the gain
+// is taxed at ordinary rates when held LongTermHoldMonths or less and at
+// capital gains rates otherwise, plus NIIT and state tax.
+func CalculateSaleTax(in SaleInput) *SaleTax {
+	proceeds := NewMoney(in.Shares * in.SalePrice)
+	basis := NewMoney(in.Shares * in.CostBasis)
+	gain := proceeds - basis
+	longTerm := in.MonthsHeld > LongTermHoldMonths
+
+	federal, niit, state := saleTaxes(in.Other, gain.Float(), longTerm)
+	total := federal + niit + state
+
+	result := &SaleTax{
+		Proceeds:    proceeds,
+		Basis:       basis,
+		Gain:        gain,
+		LongTerm:    longTerm,
+		FederalTax:  federal,
+		NIIT:        niit,
+		StateTax:    state,
+		TotalTax:    total,
+		NetProceeds: proceeds - total,
+	}
+	if gain > 0 {
+		result.EffectiveRate = math.Round(total.Float()/gain.Float()*1000) / 10
+	}
+
+	if !longTerm && gain > 0 {
+		ltFederal, ltNIIT, ltState := saleTaxes(in.Other, gain.Float(), true)
+		ltTotal := ltFederal + ltNIIT + ltState
+		result.MonthsUntilLongTerm = LongTermHoldMonths + 1 - in.MonthsHeld
+		result.LongTermTotalTax = ltTotal
+		result.WaitingSaves = total - ltTotal
+	}
+	return result
+}
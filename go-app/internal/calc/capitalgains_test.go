@@ -0,0 +1,92 @@
+package calc
+
+import "testing"
+
+func TestCalculateTaxBreakdown_LongTermGains(t *testing.T) {
+	result := CalculateTaxBreakdown(TaxInput{GrossAnnual: 50000, LongTermGains: 20000})
+
+	// Ordinary taxable income is 35,400; the gain fills 11,625 of the 0%
+	// bracket and the remaining 8,375 is taxed at 15%
+	if result.CapitalGainsTax.Dollars() != 1256 {
+		t.Errorf("expected capital gains tax 1256, got %v", result.CapitalGainsTax)
+	}
+	if base := CalculateTaxes(50000, 0, 0, 0); result.FederalTax != base.FederalTax {
+		t.Errorf("long-term gains shouldn't change ordinary tax, got %v vs %v", result.FederalTax, base.FederalTax)
+	}
+	if result.FICATax != CalculateTaxes(50000, 0, 0, 0).FICATax {
+		t.Error("investment income shouldn't be subject to FICA")
+	}
+	if result.InvestmentIncome != NewMoney(20000) || result.AGI != NewMoney(70000) {
+		t.Errorf("expected investment income 20000 and AGI 70000, got %v and %v", result.InvestmentIncome, result.AGI)
+	}
+	if expected := result.GrossAnnual + result.InvestmentIncome - result.TotalDeductions; result.NetAnnual != expected {
+		t.Errorf("net annual %v doesn't match income - deductions %v", result.NetAnnual, expected)
+	}
+}
+
+func TestCalculateTaxBreakdown_NIIT(t *testing.T) {
+	result := CalculateTaxBreakdown(TaxInput{GrossAnnual: 250000, LongTermGains: 40000, QualifiedDividends: 10000})
+
+	// 3.8% of the lesser of 50,000 investment income and 100,000 MAGI over 200,000
+	if result.NIIT != NewMoney(1900) {
+		t.Errorf("expected NIIT 1900, got %v", result.NIIT)
+	}
+	if low := CalculateTaxBreakdown(TaxInput{GrossAnnual: 100000, LongTermGains: 40000}); low.NIIT != 0 {
+		t.Errorf("expected no NIIT below the threshold, got %v", low.NIIT)
+	}
+}
+
+func TestCalculateTaxBreakdown_CapitalLoss(t *testing.T) {
+	result := CalculateTaxBreakdown(TaxInput{GrossAnnual: 60000, ShortTermGains: -10000, LongTermGains: 2000})
+
+	if result.AGI != NewMoney(57000) {
+		t.Errorf("expected net loss deduction limited to 3000, got AGI %v", result.AGI)
+	}
+	if result.CapitalGainsTax != 0 || result.NIIT != 0 {
+		t.Error("a net loss shouldn't owe capital gains tax or NIIT")
+	}
+}
+
+func TestCalculateTaxBreakdown_StateExclusion(t *testing.T) {
+	full := CalculateTaxBreakdown(TaxInput{GrossAnnual: 60000, StateTaxRate: 5, LongTermGains: 10000})
+	excluded := CalculateTaxBreakdown(TaxInput{GrossAnnual: 60000, StateTaxRate: 5, LongTermGains: 10000, StateLTCGExclusionPct: 50})
+
+	if full.StateTax-excluded.StateTax != NewMoney(250) {
+		t.Errorf("expected a 50%% exclusion to save 250 of state tax, got %v", full.StateTax-excluded.StateTax)
+	}
+}
+
+func TestCalculateSaleTax(t *testing.T) {
+	result := CalculateSaleTax(SaleInput{
+		Shares:     100,
+		SalePrice:  150,
+		CostBasis:  50,
+		MonthsHeld: 6,
+		Other:      TaxInput{GrossAnnual: 100000, StateTaxRate: 5},
+	})
+
+	if result.Proceeds != NewMoney(15000) || result.Basis != NewMoney(5000) || result.Gain != NewMoney(10000) {
+		t.Errorf("expected 15000 proceeds, 5000 basis, 10000 gain, got %s/%s/%s", result.Proceeds, result.Basis, result.Gain)
+	}
+	if result.LongTerm {
+		t.Error("6 months should be short-term")
+	}
+	// 22% ordinary rate plus 5% state
+	if result.FederalTax != NewMoney(2200) || result.StateTax != NewMoney(500) || result.TotalTax != NewMoney(2700) {
+		t.Errorf("expected 2200 federal + 500 state, got %s + %s = %s", result.FederalTax, result.StateTax, result.TotalTax)
+	}
+	if result.NetProceeds != NewMoney(12300) {
+		t.Errorf("expected net proceeds 12300, got %s", result.NetProceeds)
+	}
+	// Long-term: 15% plus 5% state
+	if result.MonthsUntilLongTerm != 7 || result.LongTermTotalTax != NewMoney(2000) || result.WaitingSaves != NewMoney(700) {
+		t.Errorf("expected waiting 7 months to save 700, got %d months, %s tax, %s saved",
+			result.MonthsUntilLongTerm, result.LongTermTotalTax, result.WaitingSaves)
+	}
+
+	longTerm := CalculateSaleTax(SaleInput{Shares: 100, SalePrice: 150, CostBasis: 50, MonthsHeld: 13,
+		Other: TaxInput{GrossAnnual: 100000, StateTaxRate: 5}})
+	if !longTerm.LongTerm || longTerm.TotalTax != NewMoney(2000) || longTerm.WaitingSaves != 0 {
+		t.Errorf("expected a 2000 long-term tax, got %s", longTerm.TotalTax)
+	}
+}
@@ -0,0 +1,65 @@
+package calc
+
+import "math"
+
+// CompoundInput describes a lump sum plus monthly contributions growing at a
+// nominal annual return, compounded monthly.
+type CompoundInput struct {
+	Principal           float64 `json:"principal"`
+	MonthlyContribution float64 `json:"monthly_contribution"`
+	ReturnRate          float64 `json:"return_rate"` // nominal annual return, percent
+	Years               int     `json:"years"`
+	InflationRate       float64 `json:"inflation_rate"`
+}
+
+// CompoundYear is the balance at the end of a year.
+type CompoundYear struct {
+	Year        int     `json:"year"`
+	Contributed Dollars `json:"contributed"`
+	Balance     Dollars `json:"balance"`
+}
+
+// CompoundResult is the growth of an investment in nominal and today's
+// dollars. Contributions are discounted from the month they're made, so the
+// real interest earned is growth beyond what the deposits were worth.
+type CompoundResult struct {
+	FutureValue        Dollars        `json:"future_value"`
+	TotalInvested      Dollars        `json:"total_invested"`
+	InterestEarned     Dollars        `json:"interest_earned"`
+	GrowthMultiple     float64        `json:"growth_multiple"`
+	RealGrowthMultiple float64        `json:"real_growth_multiple"`
+	InflationRate      float64        `json:"inflation_rate"`
+	Yearly             []CompoundYear `json:"yearly"`
+}
+
+// CalculateCompound projects compound growth month by month.
+func CalculateCompound(in CompoundInput) *CompoundResult {
+	monthlyRate := in.ReturnRate / 100 / 12
+	balance := in.Principal
+	invested := dollarFlow{}
+	invested.add(in.Principal, in.InflationRate, 0)
+
+	result := &CompoundResult{InflationRate: in.InflationRate}
+	for m := 1; m <= in.Years*12; m++ {
+		balance = balance*(1+monthlyRate) + in.MonthlyContribution
+		invested.add(in.MonthlyContribution, in.InflationRate, float64(m)/12)
+		if m%12 == 0 {
+			result.Yearly = append(result.Yearly, CompoundYear{
+				Year:        m / 12,
+				Contributed: invested.dollars(),
+				Balance:     NewDollars(balance, in.InflationRate, float64(m)/12),
+			})
+		}
+	}
+
+	future := NewDollars(balance, in.InflationRate, float64(in.Years))
+	realFuture := RealValue(balance, in.InflationRate, float64(in.Years))
+	result.FutureValue = future
+	result.TotalInvested = invested.dollars()
+	result.InterestEarned = future.Sub(result.TotalInvested)
+	if invested.nominal > 0 {
+		result.GrowthMultiple = math.Round(balance/invested.nominal*10) / 10
+		result.RealGrowthMultiple = math.Round(realFuture/invested.real*10) / 10
+	}
+	return result
+}
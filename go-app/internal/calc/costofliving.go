@@ -0,0 +1,127 @@
+package calc
+
+import (
+	"errors"
+	"math"
+)
+
+// Location describes a place to live for a cost-of-living comparison.
+// CostOfLiving is an index where 100 is the national average.
+type Location struct {
+	Name         string  `json:"name"`
+	CostOfLiving float64 `json:"cost_of_living"`
+	StateTaxRate float64 `json:"state_tax_rate"`
+	StateCode    string  `json:"state_code"` // for graduated state brackets
+	LocalTaxRate float64 `json:"local_tax_rate"`
+}
+
+// LocationTaxes is the full tax burden on a salary in one location.
+type LocationTaxes struct {
+	Name             string  `json:"name"`
+	CostOfLiving     float64 `json:"cost_of_living"`
+	Gross            Money   `json:"gross"`
+	FederalTax       Money   `json:"federal_tax"`
+	StateTax         Money   `json:"state_tax"`
+	LocalTax         Money   `json:"local_tax"`
+	FICATax          Money   `json:"fica_tax"`
+	TotalTax         Money   `json:"total_tax"`
+	NetAnnual        Money   `json:"net_annual"`
+	NetMonthly       Money   `json:"net_monthly"`
+	EffectiveTaxRate float64 `json:"effective_tax_rate"`
+}
+
+// CostOfLivingComparison answers "$X in A equals $Y in B" after tax.
+type CostOfLivingComparison struct {
+	From LocationTaxes `json:"from"`
+	To   LocationTaxes `json:"to"`
+
+	// EquivalentSalary is the gross salary in To whose take-home pay buys
+	// the same basket of goods as From's take-home pay.
+	EquivalentSalary Money `json:"equivalent_salary"`
+	// PreTaxEquivalent scales the salary by the index ratio alone, ignoring
+	// the difference in tax burden.
+	PreTaxEquivalent Money `json:"pre_tax_equivalent"`
+	// TaxEffect is how much of the gap comes from taxes rather than prices.
+	TaxEffect      Money   `json:"tax_effect"`
+	SalaryChange   Money   `json:"salary_change"`
+	PercentChange  float64 `json:"percent_change"`
+	CostDifference float64 `json:"cost_difference"` // % by which To is pricier than From
+}
+
+// locationTaxes computes the tax burden on in.GrossAnnual in loc, whose state
+// rate and code replace in.StateTaxRate and in.StateCode. Local income taxes are flat wage taxes levied
+// on the same base as state tax.
+func locationTaxes(in TaxInput, loc Location) LocationTaxes {
+	in.StateTaxRate, in.StateCode = loc.StateTaxRate, loc.StateCode
+	t := CalculateTaxBreakdown(in)
+	local := max(0, t.AGI).Mul(loc.LocalTaxRate / 100)
+	net := t.NetAnnual - local
+
+	total := t.FederalTax + t.StateTax + t.FICATax + local
+	effRate := 0.0
+	if t.GrossAnnual > 0 {
+		effRate = math.Round(total.Float()/t.GrossAnnual.Float()*1000) / 10
+	}
+	return LocationTaxes{
+		Name:             loc.Name,
+		CostOfLiving:     loc.CostOfLiving,
+		Gross:            t.GrossAnnual,
+		FederalTax:       t.FederalTax,
+		StateTax:         t.StateTax,
+		LocalTax:         local,
+		FICATax:          t.FICATax,
+		TotalTax:         total,
+		NetAnnual:        net,
+		NetMonthly:       net.Div(12),
+		EffectiveTaxRate: effRate,
+	}
+}
+
+// netIn returns take-home pay for gross in loc under the filing status.
+func netIn(gross float64, status FilingStatus, loc Location) float64 {
+	in := TaxInput{GrossAnnual: gross, FilingStatus: status, StateTaxRate: loc.StateTaxRate, StateCode: loc.StateCode}
+	t := calculateTaxes(in, 0, 0, nil)
+	return gross - (t.FederalTax + t.StateTax + t.FICATax).Float() - math.Max(0, t.AGI.Float())*(loc.LocalTaxRate/100)
+}
+
+// CompareCostOfLiving finds the salary in to that matches the purchasing
+// power of salary in from. Take-home pay in from is scaled by the ratio of
+// the cost-of-living indexes, then the gross salary in to that nets that
+// amount is solved for, so differences in federal, state and local tax
+// are all reflected. Both sides are taxed under the same filing status.
+func CompareCostOfLiving(salary float64, status FilingStatus, from, to Location) (*CostOfLivingComparison, error) {
+	if salary <= 0 {
+		return nil, errors.New("salary must be positive")
+	}
+	if from.CostOfLiving <= 0 || to.CostOfLiving <= 0 {
+		return nil, errors.New("cost of living index must be positive")
+	}
+
+	ratio := to.CostOfLiving / from.CostOfLiving
+	targetNet := netIn(salary, status, from) * ratio
+
+	// Take-home pay rises monotonically with gross, so bisect on gross
+	lo, hi := 0.0, salary*ratio*2+100000
+	for i := 0; i < 100 && hi-lo > 0.5; i++ {
+		mid := (lo + hi) / 2
+		if netIn(mid, status, to) < targetNet {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	// The solve is good to half a dollar, so quote whole dollars
+	equivalent := NewMoney(math.Round(hi))
+	preTax := NewMoney(math.Round(salary * ratio))
+
+	return &CostOfLivingComparison{
+		From:             locationTaxes(TaxInput{GrossAnnual: salary, FilingStatus: status}, from),
+		To:               locationTaxes(TaxInput{GrossAnnual: equivalent.Float(), FilingStatus: status}, to),
+		EquivalentSalary: equivalent,
+		PreTaxEquivalent: preTax,
+		TaxEffect:        equivalent - preTax,
+		SalaryChange:     equivalent - NewMoney(salary),
+		PercentChange:    math.Round((equivalent.Float()/salary-1)*1000) / 10,
+		CostDifference:   math.Round((ratio-1)*1000) / 10,
+	}, nil
+}
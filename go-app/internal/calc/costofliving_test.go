@@ -0,0 +1,90 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompareCostOfLivingSameLocation(t *testing.T) {
+	loc := Location{Name: "Anywhere", CostOfLiving: 100, StateTaxRate: 5}
+	result, err := CompareCostOfLiving(80000, FilingSingle, loc, loc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs((result.EquivalentSalary - NewMoney(80000)).Float()) > 1 {
+		t.Errorf("expected the same salary in the same place, got %s", result.EquivalentSalary)
+	}
+	if result.CostDifference != 0 || result.From.NetAnnual != result.To.NetAnnual {
+		t.Errorf("expected identical sides, got %+v", result)
+	}
+}
+
+func TestCompareCostOfLivingTaxes(t *testing.T) {
+	// Same prices, so the only gap is the tax burden
+	noTax := Location{Name: "Austin", CostOfLiving: 100}
+	taxed := Location{Name: "Philadelphia", CostOfLiving: 100, StateTaxRate: 3.07, LocalTaxRate: 3.75}
+	result, err := CompareCostOfLiving(100000, FilingSingle, noTax, taxed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.PreTaxEquivalent != NewMoney(100000) {
+		t.Errorf("expected no pre-tax adjustment, got %s", result.PreTaxEquivalent)
+	}
+	if result.EquivalentSalary <= NewMoney(100000) || result.TaxEffect != result.EquivalentSalary-NewMoney(100000) {
+		t.Errorf("expected a higher salary to cover state and local tax, got %+v", result)
+	}
+	if math.Abs((result.To.NetAnnual - result.From.NetAnnual).Float()) > 2 {
+		t.Errorf("expected equal take-home pay, got %v and %v", result.From.NetAnnual, result.To.NetAnnual)
+	}
+	if result.To.LocalTax == 0 || result.From.StateTax != 0 {
+		t.Errorf("unexpected tax split: from %+v to %+v", result.From, result.To)
+	}
+}
+
+func TestCompareCostOfLivingPrices(t *testing.T) {
+	cheap := Location{Name: "Memphis", CostOfLiving: 85}
+	pricey := Location{Name: "Seattle", CostOfLiving: 153}
+	result, err := CompareCostOfLiving(60000, FilingSingle, cheap, pricey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.PreTaxEquivalent != NewMoney(108000) || result.CostDifference != 80 {
+		t.Errorf("expected a 108000 pre-tax equivalent and 80%% cost difference, got %+v", result)
+	}
+	// Progressive federal tax means more than the index ratio is needed
+	if result.EquivalentSalary <= result.PreTaxEquivalent {
+		t.Errorf("expected progressive tax to push the salary above %s, got %s", result.PreTaxEquivalent, result.EquivalentSalary)
+	}
+	want := result.From.NetAnnual.Float() * 153 / 85
+	if math.Abs(result.To.NetAnnual.Float()-want) > 2 {
+		t.Errorf("expected take-home of %.0f, got %v", want, result.To.NetAnnual)
+	}
+
+	if _, err := CompareCostOfLiving(0, FilingSingle, cheap, pricey); err == nil {
+		t.Error("expected an error for a zero salary")
+	}
+	if _, err := CompareCostOfLiving(60000, FilingSingle, Location{}, pricey); err == nil {
+		t.Error("expected an error for a missing index")
+	}
+}
+
+func TestCompareCostOfLivingFilingStatus(t *testing.T) {
+	// A joint filer keeps more of the same salary in California, so needs less
+	// to match it in Texas
+	ca := Location{Name: "California", CostOfLiving: 100, StateTaxRate: 13.3, StateCode: "CA"}
+	tx := Location{Name: "Texas", CostOfLiving: 100}
+	single, err := CompareCostOfLiving(150000, FilingSingle, ca, tx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	joint, err := CompareCostOfLiving(150000, FilingMarriedJoint, ca, tx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if joint.From.NetAnnual <= single.From.NetAnnual || joint.From.StateTax >= single.From.StateTax {
+		t.Errorf("expected joint filers to owe less in California, got %+v and %+v", single.From, joint.From)
+	}
+	if math.Abs((joint.To.NetAnnual - joint.From.NetAnnual).Float()) > 2 {
+		t.Errorf("expected equal take-home pay filing jointly, got %v and %v", joint.From.NetAnnual, joint.To.NetAnnual)
+	}
+}
@@ -0,0 +1,235 @@
+package calc
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultProjectionYears is how far ahead inflation is projected by default.
+const DefaultProjectionYears = 10
+
+// trailingInflationYears is the lookback for the default projection rate.
+const trailingInflationYears = 10
+
+//go:embed cpi_u.csv
+var cpiCSV string
+
+// cpiSeries is the embedded CPI-U data. Annual averages are keyed by year,
+// monthly values by year and month.
+var cpiSeries = mustParseCPI(cpiCSV)
+
+// CPIDate is a point in the CPI series. A zero Month means the annual average.
+type CPIDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month,omitempty"`
+}
+
+// String formats a CPI date as "2024" or "Mar 2024".
+func (d CPIDate) String() string {
+	if d.Month == 0 {
+		return strconv.Itoa(d.Year)
+	}
+	return fmt.Sprintf("%s %d", time.Month(d.Month).String()[:3], d.Year)
+}
+
+// midpoint returns the date as a fractional year, placing annual averages
+// at mid-year so that month-to-year spans are measured fairly.
+func (d CPIDate) midpoint() float64 {
+	if d.Month == 0 {
+		return float64(d.Year) + 0.5
+	}
+	return float64(d.Year) + (float64(d.Month)-0.5)/12
+}
+
+type cpiData struct {
+	annual      map[int]float64
+	monthly     map[CPIDate]float64
+	firstYear   int
+	lastAnnual  int
+	firstMonth  CPIDate
+	latestMonth CPIDate
+}
+
+func mustParseCPI(raw string) *cpiData {
+	r := csv.NewReader(strings.NewReader(raw))
+	r.Comment = '#'
+	rows, err := r.ReadAll()
+	if err != nil {
+		panic("calc: parsing CPI data: " + err.Error())
+	}
+	data := &cpiData{annual: map[int]float64{}, monthly: map[CPIDate]float64{}}
+	for i, row := range rows {
+		if i == 0 {
+			continue // header
+		}
+		year, err1 := strconv.Atoi(row[0])
+		value, err2 := strconv.ParseFloat(row[2], 64)
+		if err1 != nil || err2 != nil {
+			panic(fmt.Sprintf("calc: bad CPI row %d: %v", i+1, row))
+		}
+		if row[1] == "" {
+			data.annual[year] = value
+			if data.firstYear == 0 || year < data.firstYear {
+				data.firstYear = year
+			}
+			data.lastAnnual = max(data.lastAnnual, year)
+			continue
+		}
+		month, err := strconv.Atoi(row[1])
+		if err != nil || month < 1 || month > 12 {
+			panic(fmt.Sprintf("calc: bad CPI month on row %d: %v", i+1, row))
+		}
+		d := CPIDate{Year: year, Month: month}
+		data.monthly[d] = value
+		if data.firstMonth.Year == 0 || d.midpoint() < data.firstMonth.midpoint() {
+			data.firstMonth = d
+		}
+		if d.midpoint() > data.latestMonth.midpoint() {
+			data.latestMonth = d
+		}
+	}
+	return data
+}
+
+// CPI returns the CPI-U index for a date, and false when the series doesn't
+// cover it.
+func CPI(d CPIDate) (float64, bool) {
+	if d.Month == 0 {
+		v, ok := cpiSeries.annual[d.Year]
+		return v, ok
+	}
+	v, ok := cpiSeries.monthly[d]
+	return v, ok
+}
+
+// CPIRange returns the first year in the series and the latest monthly
+// data point.
+func CPIRange() (firstYear int, latest CPIDate) {
+	return cpiSeries.firstYear, cpiSeries.latestMonth
+}
+
+// CPIYears returns every year with an annual average, oldest first.
+func CPIYears() []int {
+	years := make([]int, 0, len(cpiSeries.annual))
+	for y := cpiSeries.firstYear; y <= cpiSeries.lastAnnual; y++ {
+		if _, ok := cpiSeries.annual[y]; ok {
+			years = append(years, y)
+		}
+	}
+	return years
+}
+
+// TrailingInflationRate returns annualized inflation over the last ten full
+// years of data, as a percent.
+func TrailingInflationRate() float64 {
+	last := cpiSeries.lastAnnual
+	start, ok := cpiSeries.annual[last-trailingInflationYears]
+	if !ok {
+		return 0
+	}
+	return (math.Pow(cpiSeries.annual[last]/start, 1.0/trailingInflationYears) - 1) * 100
+}
+
+// InflationInput asks what Amount at From is worth at To. The forward
+// projection starts at To and uses ProjectionRate (percent), defaulting to
+// the trailing ten-year rate.
+type InflationInput struct {
+	Amount          float64 `json:"amount"`
+	From            CPIDate `json:"from"`
+	To              CPIDate `json:"to"`
+	ProjectionYears int     `json:"projection_years"`
+	ProjectionRate  float64 `json:"projection_rate"`
+}
+
+// InflationProjection is one year of the forward projection: what the
+// adjusted value will cost then, and what it will buy in To dollars.
+type InflationProjection struct {
+	Year            int   `json:"year"`
+	Cost            Money `json:"cost"`
+	PurchasingPower Money `json:"purchasing_power"`
+}
+
+// InflationResult is the CPI-adjusted value of an amount between two dates.
+type InflationResult struct {
+	Amount         Money                 `json:"amount"`
+	Value          Money                 `json:"value"` // Amount in To dollars
+	From           CPIDate               `json:"from"`
+	To             CPIDate               `json:"to"`
+	FromCPI        float64               `json:"from_cpi"`
+	ToCPI          float64               `json:"to_cpi"`
+	Years          float64               `json:"years"`
+	CumulativePct  float64               `json:"cumulative_pct"`
+	AnnualizedPct  float64               `json:"annualized_pct"`
+	ProjectionRate float64               `json:"projection_rate"`
+	Projection     []InflationProjection `json:"projection"`
+}
+
+// AdjustForInflation converts an amount between two dates with the CPI-U
+// series and projects the result forward.
+func AdjustForInflation(in InflationInput) (*InflationResult, error) {
+	if in.Amount <= 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+	fromCPI, ok := CPI(in.From)
+	if !ok {
+		return nil, cpiRangeError(in.From)
+	}
+	toCPI, ok := CPI(in.To)
+	if !ok {
+		return nil, cpiRangeError(in.To)
+	}
+	projectionYears := in.ProjectionYears
+	if projectionYears <= 0 {
+		projectionYears = DefaultProjectionYears
+	}
+	rate := in.ProjectionRate
+	if rate <= 0 {
+		rate = TrailingInflationRate()
+	}
+
+	ratio := toCPI / fromCPI
+	value := in.Amount * ratio
+	years := math.Abs(in.To.midpoint() - in.From.midpoint())
+	result := &InflationResult{
+		Amount:         NewMoney(in.Amount),
+		Value:          NewMoney(value),
+		From:           in.From,
+		To:             in.To,
+		FromCPI:        fromCPI,
+		ToCPI:          toCPI,
+		Years:          math.Round(years*10) / 10,
+		CumulativePct:  math.Round((ratio-1)*1000) / 10,
+		ProjectionRate: math.Round(rate*10) / 10,
+	}
+	if years > 0 {
+		// Annualized in the direction of time, so going back reads the same
+		// as going forward
+		forward := ratio
+		if in.To.midpoint() < in.From.midpoint() {
+			forward = 1 / ratio
+		}
+		result.AnnualizedPct = math.Round((math.Pow(forward, 1/years)-1)*1000) / 10
+	}
+	for y := 1; y <= projectionYears; y++ {
+		growth := math.Pow(1+rate/100, float64(y))
+		result.Projection = append(result.Projection, InflationProjection{
+			Year:            in.To.Year + y,
+			Cost:            NewMoney(value * growth),
+			PurchasingPower: NewMoney(value / growth),
+		})
+	}
+	return result, nil
+}
+
+func cpiRangeError(d CPIDate) error {
+	if d.Month == 0 {
+		return fmt.Errorf("no CPI data for %s; annual data covers %d to %d", d, cpiSeries.firstYear, cpiSeries.lastAnnual)
+	}
+	return fmt.Errorf("no CPI data for %s; monthly data covers %s to %s", d, cpiSeries.firstMonth, cpiSeries.latestMonth)
+}
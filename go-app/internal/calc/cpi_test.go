@@ -0,0 +1,67 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCPISeries(t *testing.T) {
+	first, latest := CPIRange()
+	if first != 1913 || latest.Year < 2025 || latest.Month == 0 {
+		t.Errorf("unexpected CPI range %d to %s", first, latest)
+	}
+	years := CPIYears()
+	if len(years) != years[len(years)-1]-years[0]+1 {
+		t.Errorf("expected an unbroken run of annual averages, got %d years", len(years))
+	}
+	if v, ok := CPI(CPIDate{Year: 2000}); !ok || v != 172.2 {
+		t.Errorf("expected 2000 CPI 172.2, got %.1f", v)
+	}
+	if _, ok := CPI(CPIDate{Year: 1990, Month: 5}); ok {
+		t.Error("expected no monthly data for 1990")
+	}
+	if rate := TrailingInflationRate(); rate < 2 || rate > 4 {
+		t.Errorf("expected a trailing rate between 2%% and 4%%, got %.2f", rate)
+	}
+}
+
+func TestAdjustForInflation(t *testing.T) {
+	result, err := AdjustForInflation(InflationInput{Amount: 100, From: CPIDate{Year: 2000}, To: CPIDate{Year: 2024}, ProjectionRate: 3, ProjectionYears: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 100 * 313.689 / 172.2
+	if result.Value != NewMoney(182.17) || result.CumulativePct != 82.2 {
+		t.Errorf("expected $182.17 and 82.2%% cumulative, got %s and %.1f", result.Value, result.CumulativePct)
+	}
+	want := (math.Pow(313.689/172.2, 1.0/24) - 1) * 100
+	if math.Abs(result.AnnualizedPct-want) > 0.05 {
+		t.Errorf("expected %.1f%% annualized, got %.1f", want, result.AnnualizedPct)
+	}
+	if len(result.Projection) != 2 || result.Projection[1].Year != 2026 || result.Projection[1].Cost != NewMoney(193.26) {
+		t.Errorf("unexpected projection %+v", result.Projection)
+	}
+
+	back, _ := AdjustForInflation(InflationInput{Amount: 100, From: CPIDate{Year: 2024}, To: CPIDate{Year: 2000}})
+	if back.Value != NewMoney(54.90) || back.AnnualizedPct != result.AnnualizedPct {
+		t.Errorf("expected $54.90 going back with the same annualized rate, got %s and %.1f", back.Value, back.AnnualizedPct)
+	}
+}
+
+func TestAdjustForInflation_Monthly(t *testing.T) {
+	result, err := AdjustForInflation(InflationInput{Amount: 1000, From: CPIDate{Year: 2021, Month: 1}, To: CPIDate{Year: 2023, Month: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Years != 2 || result.Value != NewMoney(1143.69) {
+		t.Errorf("expected $1,143.69 over 2 years, got %s over %.1f", result.Value, result.Years)
+	}
+
+	if _, err := AdjustForInflation(InflationInput{Amount: 1000, From: CPIDate{Year: 1900}, To: CPIDate{Year: 2020}}); err == nil {
+		t.Error("expected an error before the series starts")
+	}
+	if _, err := AdjustForInflation(InflationInput{Amount: 1000, From: CPIDate{Year: 2019, Month: 6}, To: CPIDate{Year: 2020}}); err == nil {
+		t.Error("expected an error for a month without data")
+	}
+}
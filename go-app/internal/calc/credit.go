@@ -0,0 +1,249 @@
+package calc
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// MaxCreditCardMonths caps revolving-credit simulations at 50 years. Minimum
+// payment schedules that have not paid off by then are reported as unpaid.
+const MaxCreditCardMonths = 600
+
+// MinimumPaymentFormula describes how a card issuer computes the minimum due:
+// a percentage of the statement balance, optionally plus that cycle's interest,
+// with a dollar floor.
+type MinimumPaymentFormula struct {
+	PercentOfBalance float64 `json:"percent_of_balance"`
+	PlusInterest     bool    `json:"plus_interest"`
+	Floor            float64 `json:"floor"`
+}
+
+// DefaultMinimumPayment is the common "1% of balance plus interest, $25 minimum" formula.
+var DefaultMinimumPayment = MinimumPaymentFormula{PercentOfBalance: 1, PlusInterest: true, Floor: 25}
+
+// CreditCardPayoff represents the outcome of paying down a revolving balance.
+type CreditCardPayoff struct {
+	StartingBalance Money     `json:"starting_balance"`
+	APR             float64   `json:"apr"`
+	FirstPayment    Money     `json:"first_payment"`
+	Months          int       `json:"months"`
+	PayoffDate      time.Time `json:"payoff_date"`
+	TotalPaid       Money     `json:"total_paid"`
+	TotalInterest   Money     `json:"total_interest"`
+	TotalFees       Money     `json:"total_fees"`
+	PaidOff         bool      `json:"paid_off"`
+}
+
+// CreditCardComparison compares paying the issuer minimum against a fixed payment.
+type CreditCardComparison struct {
+	Minimum       *CreditCardPayoff `json:"minimum"`
+	Fixed         *CreditCardPayoff `json:"fixed"`
+	InterestSaved Money             `json:"interest_saved"`
+	MonthsSaved   int               `json:"months_saved"`
+}
+
+// BalanceTransferResult compares keeping a balance on the current card with
+// moving it to a promotional-APR card that charges a transfer fee. Savings and
+// TransferWins are only set when both cards are paid off, since a schedule cut
+// off at MaxCreditCardMonths has no meaningful total.
+type BalanceTransferResult struct {
+	Stay         *CreditCardPayoff `json:"stay"`
+	Transfer     *CreditCardPayoff `json:"transfer"`
+	TransferFee  Money             `json:"transfer_fee"`
+	Savings      Money             `json:"savings"`
+	TransferWins bool              `json:"transfer_wins"`
+	PromoMonths  int               `json:"promo_months"`
+	PromoPayment Money             `json:"promo_payment"` // payment needed to clear the balance before the promo ends
+}
+
+// CreditCardMinimumPayment returns the minimum due for a statement balance and
+// the interest charged in that cycle. It never exceeds the amount owed.
+func CreditCardMinimumPayment(balance, interest float64, f MinimumPaymentFormula) float64 {
+	payment := balance * f.PercentOfBalance / 100
+	if f.PlusInterest {
+		payment += interest
+	}
+	payment = math.Max(payment, f.Floor)
+	return math.Min(payment, balance)
+}
+
+// cycleInterest returns interest for one billing cycle using the daily periodic
+// rate (APR / 365) applied to the balance for each day in the calendar month.
+func cycleInterest(balance, apr float64, cycleStart time.Time) float64 {
+	days := cycleStart.AddDate(0, 1, 0).Sub(cycleStart).Hours() / 24
+	return balance * (apr / 100 / 365) * days
+}
+
+// simulateRevolving runs a month-by-month payoff. payment receives the
+// post-interest balance and the cycle's interest and returns the amount paid;
+// aprFor returns the APR in effect for a given month (0-based).
+func simulateRevolving(
+	balance float64,
+	aprFor func(month int) float64,
+	payment func(balance, interest float64) float64,
+	start time.Time,
+) *CreditCardPayoff {
+	result := &CreditCardPayoff{
+		StartingBalance: NewMoney(balance),
+		APR:             aprFor(0),
+	}
+
+	var totalPaid, totalInterest float64
+	cycle := start
+	month := 0
+	for balance > 0.005 && month < MaxCreditCardMonths {
+		interest := cycleInterest(balance, aprFor(month), cycle)
+		balance += interest
+		paid := math.Min(payment(balance, interest), balance)
+		if paid <= 0 {
+			break
+		}
+		if month == 0 {
+			result.FirstPayment = NewMoney(paid)
+		}
+		balance -= paid
+		totalPaid += paid
+		totalInterest += interest
+		month++
+		cycle = cycle.AddDate(0, 1, 0)
+	}
+
+	result.Months = month
+	result.PayoffDate = start.AddDate(0, month, 0)
+	result.TotalPaid = NewMoney(totalPaid)
+	result.TotalInterest = NewMoney(totalInterest)
+	result.PaidOff = balance <= 0.005
+	return result
+}
+
+func constantAPR(apr float64) func(int) float64 {
+	return func(int) float64 { return apr }
+}
+
+// SimulateMinimumPayments projects how long a balance takes to pay off when
+// only the issuer minimum is paid each month.
+func SimulateMinimumPayments(balance, apr float64, f MinimumPaymentFormula, start time.Time) *CreditCardPayoff {
+	return simulateRevolving(balance, constantAPR(apr), func(b, interest float64) float64 {
+		return CreditCardMinimumPayment(b, interest, f)
+	}, start)
+}
+
+// SimulateFixedPayment projects payoff with the same payment every month.
+// It returns an error if the payment does not cover the first cycle's interest.
+func SimulateFixedPayment(balance, apr, payment float64, start time.Time) (*CreditCardPayoff, error) {
+	if payment <= cycleInterest(balance, apr, start) {
+		return nil, errors.New("payment must be greater than the monthly interest charge")
+	}
+	return simulateRevolving(balance, constantAPR(apr), func(float64, float64) float64 {
+		return payment
+	}, start), nil
+}
+
+// PaymentForPayoffDate returns the smallest whole-dollar fixed monthly payment
+// that clears the balance by the target date. Payments fall on the monthly
+// anniversaries of start, so the last one is on or before target.
+func PaymentForPayoffDate(balance, apr float64, start, target time.Time) (Money, error) {
+	months := 0
+	for !start.AddDate(0, months+1, 0).After(target) {
+		months++
+	}
+	if months == 0 {
+		return 0, errors.New("payoff date must be at least one month away")
+	}
+	if balance <= 0 {
+		return 0, nil
+	}
+
+	paysOff := func(payment int) bool {
+		p := simulateRevolving(balance, constantAPR(apr), func(float64, float64) float64 {
+			return float64(payment)
+		}, start)
+		return p.PaidOff && !p.PayoffDate.After(target)
+	}
+
+	// Start the upper bound at the amortized payment plus a cycle of daily
+	// interest, and grow it in case long billing cycles need more
+	hi := int(math.Ceil(amortizedPayment(balance, apr, months)+cycleInterest(balance, apr, start))) + 1
+	for !paysOff(hi) {
+		hi *= 2
+	}
+	lo := 0
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if paysOff(mid) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return NewMoney(float64(lo)), nil
+}
+
+// CompareCreditCardPayoff compares minimum payments with a fixed monthly payment.
+func CompareCreditCardPayoff(balance, apr float64, f MinimumPaymentFormula, fixedPayment float64, start time.Time) (*CreditCardComparison, error) {
+	fixed, err := SimulateFixedPayment(balance, apr, fixedPayment, start)
+	if err != nil {
+		return nil, err
+	}
+	minimum := SimulateMinimumPayments(balance, apr, f, start)
+	return &CreditCardComparison{
+		Minimum:       minimum,
+		Fixed:         fixed,
+		InterestSaved: minimum.TotalInterest - fixed.TotalInterest,
+		MonthsSaved:   minimum.Months - fixed.Months,
+	}, nil
+}
+
+// CalculateBalanceTransfer compares paying a fixed amount on the current card
+// with transferring the balance to a card charging promoAPR for promoMonths,
+// then goToAPR. The transfer fee (a percentage) is added to the new balance.
+func CalculateBalanceTransfer(
+	balance float64,
+	currentAPR float64,
+	promoAPR float64,
+	promoMonths int,
+	goToAPR float64,
+	feePercent float64,
+	payment float64,
+	start time.Time,
+) (*BalanceTransferResult, error) {
+	stay, err := SimulateFixedPayment(balance, currentAPR, payment, start)
+	if err != nil {
+		return nil, err
+	}
+
+	fee := balance * feePercent / 100
+	transfer := simulateRevolving(balance+fee, func(month int) float64 {
+		if month < promoMonths {
+			return promoAPR
+		}
+		return goToAPR
+	}, func(float64, float64) float64 {
+		return payment
+	}, start)
+	transfer.StartingBalance = NewMoney(balance)
+	transfer.TotalFees = NewMoney(fee)
+
+	var promoPayment Money
+	if promoMonths > 0 {
+		promoPayment, err = PaymentForPayoffDate(balance+fee, promoAPR, start, start.AddDate(0, promoMonths, 0))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &BalanceTransferResult{
+		Stay:         stay,
+		Transfer:     transfer,
+		TransferFee:  NewMoney(fee),
+		PromoMonths:  promoMonths,
+		PromoPayment: promoPayment,
+	}
+	if stay.PaidOff && transfer.PaidOff {
+		// Fees are already part of TotalPaid on the transfer card
+		result.Savings = stay.TotalPaid - transfer.TotalPaid
+		result.TransferWins = result.Savings > 0
+	}
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package calc
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var creditStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestCreditCardMinimumPayment(t *testing.T) {
+	tests := []struct {
+		name     string
+		balance  float64
+		interest float64
+		want     float64
+	}{
+		{name: "percent plus interest", balance: 5000, interest: 90, want: 140},
+		{name: "floor applies", balance: 1000, interest: 5, want: 25},
+		{name: "never exceeds balance", balance: 20, interest: 0.5, want: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreditCardMinimumPayment(tt.balance, tt.interest, DefaultMinimumPayment)
+			if got != tt.want {
+				t.Errorf("CreditCardMinimumPayment() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateMinimumPayments(t *testing.T) {
+	result := SimulateMinimumPayments(5000, 22, DefaultMinimumPayment, creditStart)
+
+	if !result.PaidOff {
+		t.Fatal("expected balance to be paid off at the minimum")
+	}
+	// 1% + interest on $5,000 at 22% takes well over a decade
+	if result.Months < 150 {
+		t.Errorf("expected minimum payoff to take 150+ months, got %d", result.Months)
+	}
+	if result.TotalPaid-result.TotalInterest != NewMoney(5000) {
+		t.Errorf("principal repaid %s doesn't match starting balance", result.TotalPaid-result.TotalInterest)
+	}
+}
+
+func TestSimulateFixedPayment_TooSmall(t *testing.T) {
+	// $5,000 at 24% accrues ~$100/month in interest
+	_, err := SimulateFixedPayment(5000, 24, 50, creditStart)
+	if err == nil {
+		t.Error("expected error when payment doesn't cover interest")
+	}
+}
+
+func TestPaymentForPayoffDate(t *testing.T) {
+	payment, err := PaymentForPayoffDate(5000, 20, creditStart, creditStart.AddDate(0, 24, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Roughly matches the amortized payment on a 24-month loan
+	approx := CalculateMonthlyPayment(5000, 20, 24)
+	if math.Abs((payment - approx).Float()) > 3 {
+		t.Errorf("payment %s not within $3 of amortized payment %s", payment, approx)
+	}
+
+	fixed, _ := SimulateFixedPayment(5000, 20, payment.Float(), creditStart)
+	if !fixed.PaidOff || fixed.Months > 24 {
+		t.Errorf("payment %s should clear the balance within 24 months, took %d", payment, fixed.Months)
+	}
+	slower, _ := SimulateFixedPayment(5000, 20, payment.Float()-1, creditStart)
+	if slower.Months <= 24 {
+		t.Errorf("payment %s should be the smallest that hits the target", payment)
+	}
+
+	// A target mid-month only leaves room for the payments before it
+	early, _ := PaymentForPayoffDate(5000, 20, creditStart, creditStart.AddDate(0, 24, -1))
+	if early <= payment {
+		t.Errorf("expected a target a day short of 24 months to need more than %s, got %s", payment, early)
+	}
+
+	if _, err := PaymentForPayoffDate(5000, 20, creditStart, creditStart.AddDate(0, 0, 20)); err == nil {
+		t.Error("expected an error for a payoff date less than a month away")
+	}
+}
+
+func TestPaymentForPayoffDateLongCycle(t *testing.T) {
+	// January has 31 days: $10,000 at 30% accrues $254.79 before the one
+	// payment, more than a simple-interest month of $250
+	payment, err := PaymentForPayoffDate(10000, 30, creditStart, creditStart.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payment != NewMoney(10255) {
+		t.Errorf("expected a single payment of 10255, got %s", payment)
+	}
+}
+
+func TestCompareCreditCardPayoff(t *testing.T) {
+	result, err := CompareCreditCardPayoff(5000, 22, DefaultMinimumPayment, 250, creditStart)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.InterestSaved <= 0 || result.MonthsSaved <= 0 {
+		t.Errorf("expected fixed payment to save interest and time, got %s interest / %d months",
+			result.InterestSaved, result.MonthsSaved)
+	}
+}
+
+func TestCalculateBalanceTransfer(t *testing.T) {
+	result, err := CalculateBalanceTransfer(6000, 24, 0, 18, 24, 3, 400, creditStart)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.TransferFee != NewMoney(180) {
+		t.Errorf("expected 3%% fee of 180, got %s", result.TransferFee)
+	}
+	if !result.TransferWins {
+		t.Error("expected 0% for 18 months to beat 24% APR")
+	}
+	// $6,180 at $400/month clears inside the promo with no interest
+	if result.Transfer.TotalInterest != 0 {
+		t.Errorf("expected no interest during promo, got %s", result.Transfer.TotalInterest)
+	}
+	if result.PromoPayment != NewMoney(344) {
+		t.Errorf("expected promo payment 344 ($6,180 / 18), got %s", result.PromoPayment)
+	}
+}
+
+func TestCalculateBalanceTransfer_NeverPaidOff(t *testing.T) {
+	// $150 clears the 20% card, but not $5,280 left after the promo at 36%
+	result, err := CalculateBalanceTransfer(6000, 20, 0, 6, 36, 3, 150, creditStart)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Stay.PaidOff || result.Transfer.PaidOff {
+		t.Fatalf("expected only the current card to pay off, got stay %v transfer %v", result.Stay.PaidOff, result.Transfer.PaidOff)
+	}
+	if result.Savings != 0 || result.TransferWins {
+		t.Errorf("expected no savings from a transfer that never pays off, got %s (wins %v)", result.Savings, result.TransferWins)
+	}
+}
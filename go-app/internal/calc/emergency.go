@@ -0,0 +1,122 @@
+package calc
+
+import (
+	"errors"
+	"math"
+)
+
+// IncomeStability describes how predictable a household's income is.
+type IncomeStability string
+
+// Income stability levels.
+const (
+	IncomeSalaried   IncomeStability = "w2"
+	IncomeCommission IncomeStability = "commission"
+	IncomeSelfEmploy IncomeStability = "1099"
+)
+
+// Emergency fund sizing assumptions.
+const (
+	MaxDependentMonths   = 3   // extra months of coverage for dependents, at most
+	MinEmergencyMonths   = 3   // floor after the second-earner reduction
+	MaxEmergencyMonths   = 12  // ceiling on the target
+	DefaultEmergencyAPY  = 4.0 // high-yield savings rate, percent
+	MaxEmergencyTimeline = 600 // months simulated before giving up
+)
+
+// baseMonths returns the months of essential expenses to hold for an income
+// type. Less predictable income needs a bigger cushion.
+func (s IncomeStability) baseMonths() int {
+	switch s {
+	case IncomeCommission:
+		return 5
+	case IncomeSelfEmploy:
+		return 6
+	default:
+		return 3
+	}
+}
+
+// EmergencyFundInput describes a household for sizing an emergency fund.
+type EmergencyFundInput struct {
+	EssentialMonthly float64         `json:"essential_monthly"` // rent, food, utilities, insurance, minimum payments
+	Stability        IncomeStability `json:"stability"`
+	Dependents       int             `json:"dependents"`
+	SecondEarner     bool            `json:"second_earner"`
+	CurrentSavings   float64         `json:"current_savings"`
+	MonthlySavings   float64         `json:"monthly_savings"`
+	APY              float64         `json:"apy"` // percent; 0 uses DefaultEmergencyAPY
+}
+
+// EmergencyFundResult is the recommended emergency fund and how long it takes
+// to build.
+type EmergencyFundResult struct {
+	TargetMonths   int     `json:"target_months"`
+	Target         Money   `json:"target"`
+	Remaining      Money   `json:"remaining"`
+	PercentFunded  float64 `json:"percent_funded"`
+	MonthsToTarget int     `json:"months_to_target"`
+	MonthsToOne    int     `json:"months_to_one"` // months until one month of expenses is saved
+	Reachable      bool    `json:"reachable"`
+	InterestEarned Money   `json:"interest_earned"` // by the time the target is reached
+	TotalDeposits  Money   `json:"total_deposits"`
+}
+
+// EmergencyFundMonths returns how many months of essential expenses to hold:
+// a base for the income type, one more per dependent (up to
+// MaxDependentMonths), and one fewer with a second earner.
+func EmergencyFundMonths(stability IncomeStability, dependents int, secondEarner bool) int {
+	months := stability.baseMonths()
+	months += min(max(dependents, 0), MaxDependentMonths)
+	if secondEarner {
+		months = max(months-1, MinEmergencyMonths)
+	}
+	return min(months, MaxEmergencyMonths)
+}
+
+// CalculateEmergencyFund sizes an emergency fund and projects how long it
+// takes to reach at the given savings rate, with interest compounding
+// monthly in a high-yield savings account.
+func CalculateEmergencyFund(in EmergencyFundInput) (*EmergencyFundResult, error) {
+	if in.EssentialMonthly <= 0 {
+		return nil, errors.New("essential monthly expenses must be greater than zero")
+	}
+	apy := in.APY
+	if apy <= 0 {
+		apy = DefaultEmergencyAPY
+	}
+	monthlyRate := math.Pow(1+apy/100, 1.0/12) - 1
+
+	months := EmergencyFundMonths(in.Stability, in.Dependents, in.SecondEarner)
+	target := in.EssentialMonthly * float64(months)
+	result := &EmergencyFundResult{
+		TargetMonths:  months,
+		Target:        NewMoney(target),
+		Remaining:     NewMoney(math.Max(0, target-in.CurrentSavings)),
+		PercentFunded: math.Round(math.Min(1, in.CurrentSavings/target)*1000) / 10,
+	}
+
+	balance := in.CurrentSavings
+	var interest, deposits float64
+	result.MonthsToOne = -1
+	if balance >= in.EssentialMonthly {
+		result.MonthsToOne = 0
+	}
+	month := 0
+	for ; balance < target && month < MaxEmergencyTimeline; month++ {
+		earned := balance * monthlyRate
+		interest += earned
+		deposits += in.MonthlySavings
+		balance += earned + in.MonthlySavings
+		if result.MonthsToOne < 0 && balance >= in.EssentialMonthly {
+			result.MonthsToOne = month + 1
+		}
+	}
+	result.Reachable = balance >= target
+	if result.Reachable {
+		result.MonthsToTarget = month
+	}
+	result.InterestEarned = NewMoney(interest)
+	result.TotalDeposits = NewMoney(deposits)
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package calc
+
+import "testing"
+
+func TestEmergencyFundMonths(t *testing.T) {
+	tests := []struct {
+		stability    IncomeStability
+		dependents   int
+		secondEarner bool
+		want         int
+	}{
+		{IncomeSalaried, 0, false, 3},
+		{IncomeSalaried, 0, true, 3},
+		{IncomeSalaried, 2, true, 4},
+		{IncomeCommission, 1, false, 6},
+		{IncomeSelfEmploy, 5, false, 9},
+		{IncomeSelfEmploy, 5, true, 8},
+	}
+	for _, tt := range tests {
+		if got := EmergencyFundMonths(tt.stability, tt.dependents, tt.secondEarner); got != tt.want {
+			t.Errorf("EmergencyFundMonths(%s, %d, %v) = %d, want %d", tt.stability, tt.dependents, tt.secondEarner, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateEmergencyFund(t *testing.T) {
+	result, err := CalculateEmergencyFund(EmergencyFundInput{
+		EssentialMonthly: 3000,
+		Stability:        IncomeSalaried,
+		MonthlySavings:   500,
+		APY:              4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Target != NewMoney(9000) || result.Remaining != NewMoney(9000) {
+		t.Errorf("expected a 9000 target, got %s with %s remaining", result.Target, result.Remaining)
+	}
+	// Without interest 18 months; interest should shave off no more than one
+	if !result.Reachable || result.MonthsToTarget < 17 || result.MonthsToTarget > 18 {
+		t.Errorf("expected about 18 months to target, got %d", result.MonthsToTarget)
+	}
+	if result.MonthsToOne != 6 {
+		t.Errorf("expected one month of expenses after 6 months, got %d", result.MonthsToOne)
+	}
+	if result.InterestEarned <= 0 || result.TotalDeposits+result.InterestEarned < result.Target {
+		t.Errorf("expected deposits plus interest to cover the target, got %s + %s", result.TotalDeposits, result.InterestEarned)
+	}
+}
+
+func TestCalculateEmergencyFund_Funded(t *testing.T) {
+	result, err := CalculateEmergencyFund(EmergencyFundInput{EssentialMonthly: 2000, Stability: IncomeSelfEmploy, CurrentSavings: 15000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Reachable || result.MonthsToTarget != 0 || result.PercentFunded != 100 || result.Remaining != 0 {
+		t.Errorf("expected a fully funded 12000 target, got %+v", result)
+	}
+
+	result, _ = CalculateEmergencyFund(EmergencyFundInput{EssentialMonthly: 2000})
+	if result.Reachable {
+		t.Error("expected an unreachable target with no savings")
+	}
+
+	if _, err := CalculateEmergencyFund(EmergencyFundInput{}); err == nil {
+		t.Error("expected an error without essential expenses")
+	}
+}
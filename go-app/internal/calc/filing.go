@@ -0,0 +1,115 @@
+package calc
+
+import "math"
+
+// FilingStatus selects the federal brackets, standard deduction and
+// investment income thresholds. The zero value files as single.
+type FilingStatus string
+
+const (
+	FilingSingle        FilingStatus = "single"
+	FilingMarriedJoint  FilingStatus = "married_joint"
+	FilingHeadHousehold FilingStatus = "head_of_household"
+)
+
+// FilingStatuses lists the supported statuses in display order.
+var FilingStatuses = []FilingStatus{FilingSingle, FilingMarriedJoint, FilingHeadHousehold}
+
+type bracket struct {
+	Min  float64
+	Max  float64
+	Rate float64
+}
+
+// filingRules holds the 2024 federal figures that vary by filing status.
+type filingRules struct {
+	label             string
+	standardDeduction float64
+	brackets          []bracket
+	capitalGains      []bracket
+	niitThreshold     float64
+}
+
+var filingTable = map[FilingStatus]filingRules{
+	FilingSingle: {
+		label:             "Single",
+		standardDeduction: StandardDeduction,
+		brackets:          taxBrackets,
+		capitalGains:      capitalGainsBrackets,
+		niitThreshold:     NIITThreshold,
+	},
+	FilingMarriedJoint: {
+		label:             "Married Filing Jointly",
+		standardDeduction: 29200,
+		brackets: []bracket{
+			{0, 23200, 0.10},
+			{23200, 94300, 0.12},
+			{94300, 201050, 0.22},
+			{201050, 383900, 0.24},
+			{383900, 487450, 0.32},
+			{487450, 731200, 0.35},
+			{731200, math.MaxFloat64, 0.37},
+		},
+		capitalGains: []bracket{
+			{0, 94050, 0},
+			{94050, 583750, 0.15},
+			{583750, math.MaxFloat64, 0.20},
+		},
+		niitThreshold: 250000,
+	},
+	FilingHeadHousehold: {
+		label:             "Head of Household",
+		standardDeduction: 21900,
+		brackets: []bracket{
+			{0, 16550, 0.10},
+			{16550, 63100, 0.12},
+			{63100, 100500, 0.22},
+			{100500, 191950, 0.24},
+			{191950, 243700, 0.32},
+			{243700, 609350, 0.35},
+			{609350, math.MaxFloat64, 0.37},
+		},
+		capitalGains: []bracket{
+			{0, 63000, 0},
+			{63000, 551350, 0.15},
+			{551350, math.MaxFloat64, 0.20},
+		},
+		niitThreshold: 200000,
+	},
+}
+
+// Valid reports whether f is a supported filing status.
+func (f FilingStatus) Valid() bool {
+	_, ok := filingTable[f]
+	return ok
+}
+
+// rules returns the figures for f, falling back to single.
+func (f FilingStatus) rules() filingRules {
+	if r, ok := filingTable[f]; ok {
+		return r
+	}
+	return filingTable[FilingSingle]
+}
+
+// Label returns the display name for f.
+func (f FilingStatus) Label() string {
+	return f.rules().label
+}
+
+// StandardDeduction returns the 2024 standard deduction for f.
+func (f FilingStatus) StandardDeduction() float64 {
+	return f.rules().standardDeduction
+}
+
+// MarginalRate returns the federal bracket rate, as a fraction, on the next
+// dollar of taxable income.
+func (f FilingStatus) MarginalRate(taxableIncome float64) float64 {
+	brackets := f.rules().brackets
+	for _, b := range brackets {
+		if taxableIncome < b.Max {
+			return b.Rate
+		}
+	}
+	return brackets[len(brackets)-1].Rate
+}
@@ -0,0 +1,179 @@
+package calc
+
+import "math"
+
+// FIRE variant assumptions.
+const (
+	LeanFIREPercent        = 75.0  // Lean FIRE spends 75% of the current budget
+	FatFIREPercent         = 150.0 // Fat FIRE spends 150% of the current budget
+	BaristaIncomePercent   = 50.0  // part-time work covers half of spending by default
+	TraditionalRetireAge   = 65
+	maxYearsToFI           = 100
+	sensitivityStepPercent = 10
+)
+
+// FIREInput holds the assumptions for a financial independence projection.
+// Spending and savings are in today's dollars and grow at the real return.
+type FIREInput struct {
+	CurrentAge     int     `json:"current_age"`
+	NetWorth       float64 `json:"net_worth"`        // invested assets today
+	AnnualSpending float64 `json:"annual_spending"`  // defaults to take-home minus savings
+	SavingsRatePct float64 `json:"savings_rate_pct"` // percent of take-home; defaults to take-home minus spending
+	ReturnRate     float64 `json:"return_rate"`      // nominal annual return, percent
+	InflationRate  float64 `json:"inflation_rate"`
+	WithdrawalRate float64 `json:"withdrawal_rate"` // defaults to the 4% rule
+	BaristaIncome  float64 `json:"barista_income"`  // part-time income in semi-retirement
+}
+
+// FIREVariant is the FI target and timeline for one flavor of FIRE.
+type FIREVariant struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Target      Money  `json:"target"`
+	YearsToFI   int    `json:"years_to_fi"`
+	FIAge       int    `json:"fi_age"`
+	Reachable   bool   `json:"reachable"`
+}
+
+// FIRESensitivity is one row of the savings-rate sensitivity table. Spending
+// is whatever take-home pay isn't saved, so a higher savings rate both adds to
+// savings and lowers the FI number.
+type FIRESensitivity struct {
+	SavingsRatePct float64 `json:"savings_rate_pct"`
+	AnnualSavings  Money   `json:"annual_savings"`
+	AnnualSpending Money   `json:"annual_spending"`
+	FINumber       Money   `json:"fi_number"`
+	YearsToFI      int     `json:"years_to_fi"`
+	FIAge          int     `json:"fi_age"`
+	Reachable      bool    `json:"reachable"`
+	Current        bool    `json:"current"`
+}
+
+// FIREResult represents a financial independence projection.
+type FIREResult struct {
+	TakeHomeAnnual  Money             `json:"take_home_annual"`
+	AnnualSpending  Money             `json:"annual_spending"`
+	AnnualSavings   Money             `json:"annual_savings"`
+	SavingsRatePct  float64           `json:"savings_rate_pct"`
+	RealReturn      float64           `json:"real_return"`
+	WithdrawalRate  float64           `json:"withdrawal_rate"`
+	FINumber        Money             `json:"fi_number"`
+	ProgressPercent float64           `json:"progress_percent"`
+	YearsToFI       int               `json:"years_to_fi"`
+	FIAge           int               `json:"fi_age"`
+	Reachable       bool              `json:"reachable"`
+	CoastNumber     Money             `json:"coast_number"` // invested today to coast to FI by 65
+	Variants        []FIREVariant     `json:"variants"`
+	Sensitivity     []FIRESensitivity `json:"sensitivity"`
+}
+
+// yearsToTarget returns how many years of saving it takes for balance to
+// reach target, or false if it isn't reached within maxYearsToFI.
+func yearsToTarget(balance, savings, target, realReturn float64) (int, bool) {
+	for y := 0; y <= maxYearsToFI; y++ {
+		if balance >= target {
+			return y, true
+		}
+		balance = balance*(1+realReturn) + savings
+	}
+	return 0, false
+}
+
+// coastYears returns the years of saving needed before contributions can stop
+// and growth alone reaches target by TraditionalRetireAge.
+func coastYears(in FIREInput, savings, target, realReturn float64) (int, bool) {
+	balance := in.NetWorth
+	for age := in.CurrentAge; age <= TraditionalRetireAge; age++ {
+		if balance*math.Pow(1+realReturn, float64(TraditionalRetireAge-age)) >= target {
+			return age - in.CurrentAge, true
+		}
+		balance = balance*(1+realReturn) + savings
+	}
+	return 0, false
+}
+
+// CalculateFIRE projects when savings will cover spending indefinitely at the
+// given withdrawal rate, using take-home pay from tax.
+func CalculateFIRE(in FIREInput, tax *TaxBreakdown) *FIREResult {
+	withdrawalRate := in.WithdrawalRate
+	if withdrawalRate <= 0 {
+		withdrawalRate = DefaultWithdrawalRate
+	}
+	takeHome := tax.NetAnnual.Float()
+
+	savings := takeHome * in.SavingsRatePct / 100
+	spending := in.AnnualSpending
+	switch {
+	case in.SavingsRatePct <= 0:
+		savings = math.Max(0, takeHome-spending)
+	case spending <= 0:
+		spending = takeHome - savings
+	}
+	var savingsRate float64
+	if takeHome > 0 {
+		savingsRate = math.Round(savings/takeHome*1000) / 10
+	}
+
+	realReturn := (1+in.ReturnRate/100)/(1+in.InflationRate/100) - 1
+	fiNumber := spending / (withdrawalRate / 100)
+
+	result := &FIREResult{
+		TakeHomeAnnual: tax.NetAnnual,
+		AnnualSpending: NewMoney(spending),
+		AnnualSavings:  NewMoney(savings),
+		SavingsRatePct: savingsRate,
+		RealReturn:     math.Round(realReturn*1000) / 10,
+		WithdrawalRate: withdrawalRate,
+		FINumber:       NewMoney(fiNumber),
+		CoastNumber:    NewMoney(fiNumber / math.Pow(1+realReturn, float64(max(0, TraditionalRetireAge-in.CurrentAge)))),
+	}
+	if fiNumber > 0 {
+		result.ProgressPercent = math.Min(100, math.Round(in.NetWorth/fiNumber*1000)/10)
+	}
+	result.YearsToFI, result.Reachable = yearsToTarget(in.NetWorth, savings, fiNumber, realReturn)
+	result.FIAge = in.CurrentAge + result.YearsToFI
+
+	baristaIncome := in.BaristaIncome
+	if baristaIncome <= 0 {
+		baristaIncome = spending * BaristaIncomePercent / 100
+	}
+	variant := func(key, name, desc string, target float64) FIREVariant {
+		v := FIREVariant{Key: key, Name: name, Description: desc, Target: NewMoney(target)}
+		v.YearsToFI, v.Reachable = yearsToTarget(in.NetWorth, savings, target, realReturn)
+		v.FIAge = in.CurrentAge + v.YearsToFI
+		return v
+	}
+	result.Variants = []FIREVariant{
+		variant("lean", "Lean FIRE", "Retire on a bare-bones budget", fiNumber*LeanFIREPercent/100),
+		variant("regular", "FIRE", "Retire on your current budget", fiNumber),
+		variant("fat", "Fat FIRE", "Retire with room for luxuries", fiNumber*FatFIREPercent/100),
+		variant("barista", "Barista FIRE", "Part-time work covers part of your spending", math.Max(0, spending-baristaIncome)/(withdrawalRate/100)),
+	}
+	coast := FIREVariant{
+		Key:         "coast",
+		Name:        "Coast FIRE",
+		Description: "Stop saving and let growth carry you to 65",
+		Target:      result.CoastNumber,
+	}
+	coast.YearsToFI, coast.Reachable = coastYears(in, savings, fiNumber, realReturn)
+	coast.FIAge = in.CurrentAge + coast.YearsToFI
+	result.Variants = append(result.Variants, coast)
+
+	for rate := float64(sensitivityStepPercent); rate < 100; rate += sensitivityStepPercent {
+		s := takeHome * rate / 100
+		spend := takeHome - s
+		target := spend / (withdrawalRate / 100)
+		row := FIRESensitivity{
+			SavingsRatePct: rate,
+			AnnualSavings:  NewMoney(s),
+			AnnualSpending: NewMoney(spend),
+			FINumber:       NewMoney(target),
+			Current:        math.Round(savingsRate/sensitivityStepPercent)*sensitivityStepPercent == rate,
+		}
+		row.YearsToFI, row.Reachable = yearsToTarget(in.NetWorth, s, target, realReturn)
+		row.FIAge = in.CurrentAge + row.YearsToFI
+		result.Sensitivity = append(result.Sensitivity, row)
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package calc
+
+import "testing"
+
+func TestCalculateFIRE(t *testing.T) {
+	tax := CalculateTaxes(100000, 0, 0, 5)
+	result := CalculateFIRE(FIREInput{
+		CurrentAge:     30,
+		SavingsRatePct: 50,
+		ReturnRate:     5,
+	}, tax)
+
+	if result.TakeHomeAnnual != tax.NetAnnual {
+		t.Errorf("expected take-home %s from CalculateTaxes, got %s", tax.NetAnnual, result.TakeHomeAnnual)
+	}
+	if result.AnnualSpending != result.AnnualSavings {
+		t.Errorf("50%% savings rate should split take-home evenly, got %s spent vs %s saved",
+			result.AnnualSpending, result.AnnualSavings)
+	}
+	// 25x spending at 4%; 1.05^n >= 2.25 takes 17 years from zero
+	if diff := result.FINumber - result.AnnualSpending*25; diff < NewMoney(-25) || diff > NewMoney(25) {
+		t.Errorf("expected FI number of 25x spending, got %s", result.FINumber)
+	}
+	if !result.Reachable || result.YearsToFI != 17 || result.FIAge != 47 {
+		t.Errorf("expected FI in 17 years at 47, got %d years at %d", result.YearsToFI, result.FIAge)
+	}
+
+	variants := map[string]FIREVariant{}
+	for _, v := range result.Variants {
+		variants[v.Key] = v
+	}
+	if variants["lean"].YearsToFI >= result.YearsToFI || variants["fat"].YearsToFI <= result.YearsToFI {
+		t.Error("lean FIRE should come sooner and fat FIRE later than regular FIRE")
+	}
+	if diff := variants["barista"].Target - result.FINumber/2; diff < NewMoney(-1) || diff > NewMoney(1) {
+		t.Errorf("expected barista target of half the FI number, got %s", variants["barista"].Target)
+	}
+	if coast := variants["coast"]; !coast.Reachable || coast.YearsToFI >= result.YearsToFI {
+		t.Errorf("coast FIRE should be reached before full FI, got %d years", coast.YearsToFI)
+	}
+
+	var current int
+	for i, row := range result.Sensitivity {
+		if row.Current {
+			current++
+		}
+		if i > 0 && row.YearsToFI > result.Sensitivity[i-1].YearsToFI {
+			t.Errorf("higher savings rate %.0f%% should not take longer", row.SavingsRatePct)
+		}
+	}
+	if current != 1 {
+		t.Errorf("expected exactly one current row, got %d", current)
+	}
+}
+
+func TestCalculateFIRE_SpendingOnly(t *testing.T) {
+	tax := CalculateTaxes(80000, 0, 0, 0)
+	result := CalculateFIRE(FIREInput{
+		CurrentAge:     40,
+		NetWorth:       2000000,
+		AnnualSpending: 40000,
+		ReturnRate:     7,
+		InflationRate:  3,
+	}, tax)
+
+	if result.AnnualSavings != tax.NetAnnual-NewMoney(40000) {
+		t.Errorf("expected savings of take-home minus spending, got %s", result.AnnualSavings)
+	}
+	if result.YearsToFI != 0 || result.ProgressPercent != 100 {
+		t.Errorf("net worth above the FI number should already be FI, got %d years, %.1f%%",
+			result.YearsToFI, result.ProgressPercent)
+	}
+}
@@ -0,0 +1,61 @@
+package calc
+
+import "time"
+
+// MileageRate is the 2024 IRS standard mileage rate, dollars per mile.
+const MileageRate = 0.67
+
+// GigInput is year-to-date income from gig work and the costs of earning it.
+type GigInput struct {
+	YTDIncome     float64   `json:"ytd_income"`
+	MilesDriven   float64   `json:"miles_driven"`
+	OtherExpenses float64   `json:"other_expenses"`
+	StartDate     time.Time `json:"start_date"`
+	CheckDate     time.Time `json:"check_date"`
+}
+
+// GigResult projects gig income to a full year and what is left after
+// expenses and self-employment tax. Expenses and tax are annual.
+type GigResult struct {
+	GrossAnnual       Money `json:"gross_annual"`
+	GrossMonthly      Money `json:"gross_monthly"`
+	DaysWorked        int   `json:"days_worked"`
+	MileageDeduction  Money `json:"mileage_deduction"`
+	TotalExpenses     Money `json:"total_expenses"`
+	SelfEmploymentTax Money `json:"self_employment_tax"`
+	NetAfterExpenses  Money `json:"net_after_expenses"`
+	NetAfterTax       Money `json:"net_after_tax"`
+	NetMonthly        Money `json:"net_monthly"`
+	EffectiveHourly   Money `json:"effective_hourly"` // over a year of 40-hour weeks
+}
+
+// CalculateGig projects gig income with CalculateIncome, then deducts
+// mileage at MileageRate, other expenses and self-employment tax. The tax
+// comes from CalculateTaxBreakdown on net earnings, so it applies the 92.35%
+// earnings factor and the Social Security wage base.
+func CalculateGig(in GigInput) (*GigResult, error) {
+	income, err := CalculateIncome(in.YTDIncome, in.StartDate, in.CheckDate)
+	if err != nil {
+		return nil, err
+	}
+
+	gross := income.GrossAnnual
+	mileage := NewMoney(in.MilesDriven * MileageRate)
+	expenses := mileage + NewMoney(in.OtherExpenses)
+	afterExpenses := gross - expenses
+	seTax := CalculateTaxBreakdown(TaxInput{SelfEmploymentIncome: afterExpenses.Float()}).SelfEmploymentTax
+	afterTax := afterExpenses - seTax
+
+	return &GigResult{
+		GrossAnnual:       gross,
+		GrossMonthly:      income.GrossMonthly,
+		DaysWorked:        income.DaysWorked,
+		MileageDeduction:  mileage,
+		TotalExpenses:     expenses,
+		SelfEmploymentTax: seTax,
+		NetAfterExpenses:  afterExpenses,
+		NetAfterTax:       afterTax,
+		NetMonthly:        afterTax.Div(12),
+		EffectiveHourly:   afterTax.Div(StandardHoursPerWeek * WeeksPerYear),
+	}, nil
+}
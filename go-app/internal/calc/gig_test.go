@@ -0,0 +1,41 @@
+package calc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateGig(t *testing.T) {
+	result, err := CalculateGig(GigInput{
+		YTDIncome:     10000,
+		MilesDriven:   1000,
+		OtherExpenses: 330,
+		StartDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		CheckDate:     time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// $10,000 over 91 days projects to $40,109.89 a year
+	if result.DaysWorked != 91 || result.GrossAnnual != NewMoney(40109.89) {
+		t.Errorf("expected 40109.89 over 91 days, got %s over %d", result.GrossAnnual, result.DaysWorked)
+	}
+	if result.MileageDeduction != NewMoney(670) || result.TotalExpenses != NewMoney(1000) {
+		t.Errorf("expected 670 mileage and 1000 expenses, got %s and %s", result.MileageDeduction, result.TotalExpenses)
+	}
+	// 15.3% of 92.35% of the 39,109.89 left after expenses
+	if result.SelfEmploymentTax != NewMoney(5526.05) {
+		t.Errorf("expected 5526.05 self-employment tax, got %s", result.SelfEmploymentTax)
+	}
+	if result.NetAfterExpenses != NewMoney(39109.89) || result.NetAfterTax != NewMoney(33583.84) {
+		t.Errorf("expected 39109.89 after expenses and 33583.84 after tax, got %s and %s", result.NetAfterExpenses, result.NetAfterTax)
+	}
+	if result.NetMonthly != NewMoney(2798.65) || result.EffectiveHourly != NewMoney(16.15) {
+		t.Errorf("expected 2798.65 a month and 16.15 an hour, got %s and %s", result.NetMonthly, result.EffectiveHourly)
+	}
+
+	if _, err := CalculateGig(GigInput{YTDIncome: 1000, StartDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), CheckDate: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}); err == nil {
+		t.Error("expected an error when the as-of date is before the start date")
+	}
+}
@@ -0,0 +1,218 @@
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Savings goal assumptions.
+const (
+	MaxGoalMonths       = 600 // months simulated before a goal is reported unreachable
+	LongDatedGoalMonths = 12  // goals at least this far out are inflation-adjusted
+)
+
+// SavingsGoal is something a household is saving toward. Lower Priority
+// numbers are funded first; zero means lowest priority. Goals with the same
+// priority are funded in target-date order.
+type SavingsGoal struct {
+	Name       string    `json:"name"`
+	Target     float64   `json:"target"` // in today's dollars
+	Saved      float64   `json:"saved"`
+	TargetDate time.Time `json:"target_date"`
+	Priority   int       `json:"priority"`
+}
+
+// GoalsInput holds the goals to plan and the monthly savings budget, usually
+// the Savings category of CalculateBudgetAllocation. Balances earn ReturnRate
+// (APY, percent); targets at least LongDatedGoalMonths away grow with
+// InflationRate (percent).
+type GoalsInput struct {
+	Goals         []SavingsGoal `json:"goals"`
+	MonthlyBudget float64       `json:"monthly_budget"`
+	ReturnRate    float64       `json:"return_rate"`
+	InflationRate float64       `json:"inflation_rate"`
+	Start         time.Time     `json:"start"`
+}
+
+// GoalResult is the plan for one goal. Allocated is its share of the monthly
+// budget up front; money freed when other goals finish is redirected, so a
+// goal may finish earlier than Allocated alone suggests.
+type GoalResult struct {
+	Name             string    `json:"name"`
+	Priority         int       `json:"priority"`
+	Target           Money     `json:"target"`
+	InflatedTarget   Money     `json:"inflated_target"` // target in dollars at the target date
+	Saved            Money     `json:"saved"`
+	TargetDate       time.Time `json:"target_date"`
+	MonthsRemaining  int       `json:"months_remaining"`
+	RequiredMonthly  Money     `json:"required_monthly"`
+	Allocated        Money     `json:"allocated"`
+	Shortfall        Money     `json:"shortfall"` // monthly amount needed beyond Allocated
+	CompletionMonths int       `json:"completion_months"`
+	CompletionDate   time.Time `json:"completion_date"`
+	Reachable        bool      `json:"reachable"`
+	AtRisk           bool      `json:"at_risk"` // projected to finish after the target date
+}
+
+// GoalsPlan splits a monthly savings budget across goals.
+type GoalsPlan struct {
+	MonthlyBudget Money        `json:"monthly_budget"`
+	TotalRequired Money        `json:"total_required"`
+	Unallocated   Money        `json:"unallocated"` // budget left after every goal's required amount
+	Goals         []GoalResult `json:"goals"`       // in funding order
+	AtRisk        []string     `json:"at_risk"`
+}
+
+// goalMonths returns whole calendar months from start to date.
+func goalMonths(start, date time.Time) int {
+	return (date.Year()-start.Year())*12 + int(date.Month()) - int(start.Month())
+}
+
+// requiredMonthly returns the level monthly deposit that grows saved to target
+// over n months at a monthly rate. Overdue goals need the whole gap now.
+func requiredMonthly(target, saved, rate float64, n int) float64 {
+	if n <= 0 {
+		return math.Max(0, target-saved)
+	}
+	growth := math.Pow(1+rate, float64(n))
+	gap := target - saved*growth
+	if gap <= 0 {
+		return 0
+	}
+	if rate == 0 {
+		return gap / float64(n)
+	}
+	return gap * rate / (growth - 1)
+}
+
+// PlanGoals allocates a monthly savings budget across goals in priority
+// order, giving each goal what it needs to finish on time until the budget
+// runs out. It then simulates month by month, sending leftover budget and
+// money freed by finished goals to the highest-priority unfinished goal, to
+// project completion dates and flag goals that will miss their target date.
+func PlanGoals(in GoalsInput) (*GoalsPlan, error) {
+	if len(in.Goals) == 0 {
+		return nil, errors.New("add at least one savings goal")
+	}
+	if in.MonthlyBudget < 0 {
+		return nil, errors.New("monthly savings budget can't be negative")
+	}
+	for _, g := range in.Goals {
+		if g.Name == "" {
+			return nil, errors.New("every goal needs a name")
+		}
+		if g.Target <= 0 {
+			return nil, fmt.Errorf("%s needs a target amount", g.Name)
+		}
+	}
+	start := in.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+	monthlyRate := math.Pow(1+in.ReturnRate/100, 1.0/12) - 1
+
+	goals := make([]SavingsGoal, len(in.Goals))
+	copy(goals, in.Goals)
+	rank := func(p int) int {
+		if p <= 0 {
+			return math.MaxInt
+		}
+		return p
+	}
+	sort.SliceStable(goals, func(a, b int) bool {
+		if rank(goals[a].Priority) != rank(goals[b].Priority) {
+			return rank(goals[a].Priority) < rank(goals[b].Priority)
+		}
+		return goals[a].TargetDate.Before(goals[b].TargetDate)
+	})
+
+	// Amounts are planned in cents so each goal's allocation and shortfall
+	// add up to what it requires, and the allocations to the budget
+	plan := &GoalsPlan{MonthlyBudget: NewMoney(in.MonthlyBudget), AtRisk: []string{}}
+	targets := make([]float64, len(goals))
+	allocated := make([]float64, len(goals))
+	remaining := plan.MonthlyBudget
+	for i, g := range goals {
+		months := goalMonths(start, g.TargetDate)
+		targets[i] = g.Target
+		if months >= LongDatedGoalMonths {
+			targets[i] = g.Target * math.Pow(1+in.InflationRate/100, float64(months)/12)
+		}
+		required := NewMoney(requiredMonthly(targets[i], g.Saved, monthlyRate, months))
+		alloc := min(required, remaining)
+		allocated[i] = alloc.Float()
+		remaining -= alloc
+		plan.TotalRequired += required
+
+		plan.Goals = append(plan.Goals, GoalResult{
+			Name:            g.Name,
+			Priority:        g.Priority,
+			Target:          NewMoney(g.Target),
+			InflatedTarget:  NewMoney(targets[i]),
+			Saved:           NewMoney(g.Saved),
+			TargetDate:      g.TargetDate,
+			MonthsRemaining: max(months, 0),
+			RequiredMonthly: required,
+			Allocated:       alloc,
+			Shortfall:       required - alloc,
+		})
+	}
+	plan.Unallocated = remaining
+
+	// Simulate: planned deposits first, then anything left over goes to the
+	// highest-priority unfinished goal
+	balances := make([]float64, len(goals))
+	done := make([]bool, len(goals))
+	open := 0
+	for i, g := range goals {
+		balances[i] = g.Saved
+		if balances[i] >= targets[i] {
+			done[i] = true
+			plan.Goals[i].Reachable = true
+			plan.Goals[i].CompletionDate = start
+			continue
+		}
+		open++
+	}
+	for month := 1; month <= MaxGoalMonths && open > 0; month++ {
+		pool := in.MonthlyBudget
+		for i := range goals {
+			if done[i] {
+				continue
+			}
+			balances[i] *= 1 + monthlyRate
+			deposit := math.Min(allocated[i], math.Max(0, targets[i]-balances[i]))
+			balances[i] += deposit
+			pool -= deposit
+		}
+		for i := range goals {
+			if done[i] || pool <= 0 {
+				continue
+			}
+			deposit := math.Min(pool, math.Max(0, targets[i]-balances[i]))
+			balances[i] += deposit
+			pool -= deposit
+		}
+		for i := range goals {
+			if !done[i] && balances[i] >= targets[i]-0.005 {
+				done[i] = true
+				open--
+				plan.Goals[i].Reachable = true
+				plan.Goals[i].CompletionMonths = month
+				plan.Goals[i].CompletionDate = start.AddDate(0, month, 0)
+			}
+		}
+	}
+
+	for i := range plan.Goals {
+		g := &plan.Goals[i]
+		g.AtRisk = !g.Reachable || g.CompletionMonths > g.MonthsRemaining
+		if g.AtRisk {
+			plan.AtRisk = append(plan.AtRisk, g.Name)
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,106 @@
+package calc
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var goalsStart = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestRequiredMonthly(t *testing.T) {
+	if got := requiredMonthly(12000, 0, 0, 12); got != 1000 {
+		t.Errorf("expected 1000/month without interest, got %.2f", got)
+	}
+	withInterest := requiredMonthly(12000, 0, 0.004, 12)
+	if withInterest >= 1000 || withInterest < 970 {
+		t.Errorf("expected interest to lower the deposit slightly, got %.2f", withInterest)
+	}
+	if got := requiredMonthly(5000, 2000, 0.004, 0); got != 3000 {
+		t.Errorf("expected an overdue goal to need the whole gap, got %.2f", got)
+	}
+}
+
+func TestPlanGoals_Allocation(t *testing.T) {
+	budget := CalculateBudgetAllocation(5000).Savings.Monthly // 1000
+	plan, err := PlanGoals(GoalsInput{
+		Goals: []SavingsGoal{
+			{Name: "Vacation", Target: 3000, TargetDate: goalsStart.AddDate(0, 6, 0), Priority: 2},
+			{Name: "Car", Target: 6000, TargetDate: goalsStart.AddDate(0, 10, 0), Priority: 1},
+		},
+		MonthlyBudget: budget.Float(),
+		Start:         goalsStart,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	car, vacation := plan.Goals[0], plan.Goals[1]
+	if car.Name != "Car" || car.RequiredMonthly != NewMoney(600) || car.Allocated != NewMoney(600) {
+		t.Errorf("expected the car funded first at 600/month, got %+v", car)
+	}
+	if vacation.RequiredMonthly != NewMoney(500) || vacation.Allocated != NewMoney(400) || vacation.Shortfall != NewMoney(100) {
+		t.Errorf("expected vacation 100 short of 500/month, got %+v", vacation)
+	}
+	// The vacation's budget rolls to the car once it's paid for, so the car finishes early
+	if car.AtRisk || !vacation.AtRisk || car.CompletionMonths != 9 {
+		t.Errorf("expected only the vacation at risk, got car %+v vacation %+v", car, vacation)
+	}
+	// 400/month finishes the vacation in 8 months
+	if vacation.CompletionMonths != 8 || !vacation.CompletionDate.Equal(goalsStart.AddDate(0, 8, 0)) {
+		t.Errorf("expected vacation done in 8 months, got %d", vacation.CompletionMonths)
+	}
+	if len(plan.AtRisk) != 1 || plan.AtRisk[0] != "Vacation" {
+		t.Errorf("expected vacation flagged, got %v", plan.AtRisk)
+	}
+	if plan.TotalRequired != NewMoney(1100) || plan.Unallocated != 0 {
+		t.Errorf("expected 1100 required and the whole budget allocated, got %s and %s", plan.TotalRequired, plan.Unallocated)
+	}
+}
+
+func TestPlanGoals_FreedBudgetRollsOver(t *testing.T) {
+	plan, err := PlanGoals(GoalsInput{
+		Goals: []SavingsGoal{
+			{Name: "Emergency", Target: 2000, TargetDate: goalsStart.AddDate(0, 2, 0), Priority: 1},
+			{Name: "Wedding", Target: 20000, TargetDate: goalsStart.AddDate(0, 11, 0), Priority: 2},
+		},
+		MonthlyBudget: 1500,
+		Start:         goalsStart,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Emergency takes 1000/month for 2 months, then the wedding gets all 1500:
+	// 2*500 + 9*1500 = 14500 by month 11, and 20000 by month 15
+	wedding := plan.Goals[1]
+	if wedding.CompletionMonths != 15 || !wedding.AtRisk {
+		t.Errorf("expected the wedding done in 15 months and at risk, got %+v", wedding)
+	}
+}
+
+func TestPlanGoals_Inflation(t *testing.T) {
+	plan, err := PlanGoals(GoalsInput{
+		Goals: []SavingsGoal{
+			{Name: "Down Payment", Target: 60000, TargetDate: goalsStart.AddDate(5, 0, 0)},
+			{Name: "Vacation", Target: 2000, TargetDate: goalsStart.AddDate(0, 6, 0)},
+		},
+		MonthlyBudget: 2000,
+		InflationRate: 3,
+		Start:         goalsStart,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Same priority, so the sooner goal is funded first
+	if plan.Goals[0].Name != "Vacation" || plan.Goals[0].InflatedTarget != NewMoney(2000) {
+		t.Errorf("expected the short-dated vacation first and not inflated, got %+v", plan.Goals[0])
+	}
+	want := 60000 * math.Pow(1.03, 5)
+	if math.Abs(plan.Goals[1].InflatedTarget.Float()-want) > 1 {
+		t.Errorf("expected the down payment inflated to %.0f, got %s", want, plan.Goals[1].InflatedTarget)
+	}
+
+	if _, err := PlanGoals(GoalsInput{}); err == nil {
+		t.Error("expected an error with no goals")
+	}
+}
@@ -0,0 +1,162 @@
+package calc
+
+import (
+	"errors"
+	"math"
+)
+
+// Standard full-time schedule and federal overtime rules.
+const (
+	StandardHoursPerWeek = 40.0
+	StandardDaysPerWeek  = 5.0
+	WeeksPerYear         = 52.0
+	OvertimeThreshold    = 40.0 // weekly hours before overtime applies
+	OvertimeMultiplier   = 1.5
+	FullTimeHours        = 30.0 // ACA full-time threshold
+)
+
+// WorkSchedule describes how much someone works in a year. Paid time off and
+// paid holidays are paid but not worked; unpaid time off is neither.
+type WorkSchedule struct {
+	HoursPerWeek      float64 `json:"hours_per_week"` // defaults to 40
+	DaysPerWeek       float64 `json:"days_per_week"`  // defaults to 5
+	PaidTimeOffDays   float64 `json:"paid_time_off_days"`
+	PaidHolidays      float64 `json:"paid_holidays"`
+	UnpaidTimeOffDays float64 `json:"unpaid_time_off_days"`
+}
+
+// FullTimeSchedule is 40 hours a week, 52 paid weeks a year (2,080 hours).
+var FullTimeSchedule = WorkSchedule{HoursPerWeek: StandardHoursPerWeek, DaysPerWeek: StandardDaysPerWeek}
+
+// PayPeriods breaks an annual amount down by pay period. Daily is per paid
+// day; Hourly is per hour actually worked.
+type PayPeriods struct {
+	Annual      Money `json:"annual"`
+	Monthly     Money `json:"monthly"`
+	Semimonthly Money `json:"semimonthly"`
+	Biweekly    Money `json:"biweekly"`
+	Weekly      Money `json:"weekly"`
+	Daily       Money `json:"daily"`
+	Hourly      Money `json:"hourly"`
+}
+
+// PayConversion is the result of converting between hourly and annual pay.
+type PayConversion struct {
+	Schedule   WorkSchedule `json:"schedule"`
+	HourlyRate float64      `json:"hourly_rate"` // straight-time rate
+	// EffectiveHourly is gross pay per hour actually worked, which is higher
+	// than HourlyRate when time off is paid and overtime is earned.
+	EffectiveHourly float64 `json:"effective_hourly"`
+	PaidWeeks       float64 `json:"paid_weeks"`
+	PaidHours       float64 `json:"paid_hours"`
+	WorkedHours     float64 `json:"worked_hours"`
+	OvertimeHours   float64 `json:"overtime_hours"` // per week
+	PartTime        bool    `json:"part_time"`
+	FTE             float64 `json:"fte"` // share of a 40-hour week
+
+	Gross            PayPeriods `json:"gross"`
+	Net              PayPeriods `json:"net"`
+	FederalTax       Money      `json:"federal_tax"`
+	StateTax         Money      `json:"state_tax"`
+	FICATax          Money      `json:"fica_tax"`
+	TotalTax         Money      `json:"total_tax"`
+	EffectiveTaxRate float64    `json:"effective_tax_rate"`
+}
+
+// withDefaults fills in a 40-hour, 5-day week and validates the schedule.
+func (s WorkSchedule) withDefaults() (WorkSchedule, error) {
+	if s.HoursPerWeek <= 0 {
+		s.HoursPerWeek = StandardHoursPerWeek
+	}
+	if s.DaysPerWeek <= 0 {
+		s.DaysPerWeek = StandardDaysPerWeek
+	}
+	if s.HoursPerWeek > 168 || s.DaysPerWeek > 7 {
+		return s, errors.New("schedule exceeds the hours in a week")
+	}
+	if s.PaidTimeOffDays < 0 || s.PaidHolidays < 0 || s.UnpaidTimeOffDays < 0 {
+		return s, errors.New("days off cannot be negative")
+	}
+	if s.PaidTimeOffDays+s.PaidHolidays+s.UnpaidTimeOffDays >= WeeksPerYear*s.DaysPerWeek {
+		return s, errors.New("days off exceed the working days in a year")
+	}
+	return s, nil
+}
+
+// straightTimeHours is the week's hours with overtime weighted at time and a half.
+func (s WorkSchedule) straightTimeHours() float64 {
+	overtime := math.Max(0, s.HoursPerWeek-OvertimeThreshold)
+	return s.HoursPerWeek - overtime + overtime*OvertimeMultiplier
+}
+
+// HourlyToSalary converts an hourly rate into annual pay for the schedule.
+// Hours past 40 a week earn overtime, and unpaid days off reduce pay. Net
+// pay is computed from tax, whose GrossAnnual is replaced by the result.
+func HourlyToSalary(rate float64, s WorkSchedule, tax TaxInput) (*PayConversion, error) {
+	if rate <= 0 {
+		return nil, errors.New("hourly rate must be positive")
+	}
+	s, err := s.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	paidWeeks := WeeksPerYear - s.UnpaidTimeOffDays/s.DaysPerWeek
+	return convertPay(rate, rate*s.straightTimeHours()*paidWeeks, s, tax), nil
+}
+
+// SalaryToHourly converts annual pay into the straight-time hourly rate that
+// earns it on the schedule, and the effective rate per hour actually worked.
+// Net pay is computed from tax, whose GrossAnnual is replaced by salary.
+func SalaryToHourly(salary float64, s WorkSchedule, tax TaxInput) (*PayConversion, error) {
+	if salary <= 0 {
+		return nil, errors.New("salary must be positive")
+	}
+	s, err := s.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	paidWeeks := WeeksPerYear - s.UnpaidTimeOffDays/s.DaysPerWeek
+	return convertPay(salary/paidWeeks/s.straightTimeHours(), salary, s, tax), nil
+}
+
+// convertPay breaks annual gross pay down by period, before and after tax.
+func convertPay(rate, annual float64, s WorkSchedule, tax TaxInput) *PayConversion {
+	hoursPerDay := s.HoursPerWeek / s.DaysPerWeek
+	paidWeeks := WeeksPerYear - s.UnpaidTimeOffDays/s.DaysPerWeek
+	paidDays := paidWeeks * s.DaysPerWeek
+	paidHours := paidDays * hoursPerDay
+	workedHours := paidHours - (s.PaidTimeOffDays+s.PaidHolidays)*hoursPerDay
+
+	tax.GrossAnnual = annual
+	t := CalculateTaxBreakdown(tax)
+	periods := func(amount Money) PayPeriods {
+		return PayPeriods{
+			Annual:      amount,
+			Monthly:     amount.Div(12),
+			Semimonthly: amount.Div(24),
+			Biweekly:    amount.Div(26),
+			Weekly:      amount.Div(WeeksPerYear),
+			Daily:       amount.Mul(1 / paidDays),
+			Hourly:      amount.Mul(1 / workedHours),
+		}
+	}
+
+	return &PayConversion{
+		Schedule:         s,
+		HourlyRate:       math.Round(rate*100) / 100,
+		EffectiveHourly:  math.Round(annual/workedHours*100) / 100,
+		PaidWeeks:        math.Round(paidWeeks*10) / 10,
+		PaidHours:        math.Round(paidHours),
+		WorkedHours:      math.Round(workedHours),
+		OvertimeHours:    math.Max(0, s.HoursPerWeek-OvertimeThreshold),
+		PartTime:         s.HoursPerWeek < FullTimeHours,
+		FTE:              math.Round(s.HoursPerWeek/StandardHoursPerWeek*100) / 100,
+		Gross:            periods(t.GrossAnnual),
+		Net:              periods(t.NetAnnual),
+		FederalTax:       t.FederalTax,
+		StateTax:         t.StateTax,
+		FICATax:          t.FICATax,
+		TotalTax:         t.FederalTax + t.StateTax + t.FICATax,
+		EffectiveTaxRate: t.EffectiveTaxRate,
+	}
+}
@@ -0,0 +1,79 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHourlyToSalaryFullTime(t *testing.T) {
+	p, err := HourlyToSalary(25, WorkSchedule{}, TaxInput{StateTaxRate: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Gross.Annual != NewMoney(52000) || p.PaidHours != 2080 || p.WorkedHours != 2080 {
+		t.Errorf("expected 52000 over 2080 hours, got %s over %.0f paid, %.0f worked", p.Gross.Annual, p.PaidHours, p.WorkedHours)
+	}
+	if p.Gross.Biweekly != NewMoney(2000) || p.Gross.Weekly != NewMoney(1000) || p.Gross.Daily != NewMoney(200) || p.Gross.Hourly != NewMoney(25) {
+		t.Errorf("unexpected gross periods %+v", p.Gross)
+	}
+	tax := CalculateTaxes(52000, 0, 0, 5)
+	if p.Net.Annual != tax.NetAnnual || p.TotalTax != tax.GrossAnnual-tax.NetAnnual {
+		t.Errorf("expected net %v, got %s (tax %s)", tax.NetAnnual, p.Net.Annual, p.TotalTax)
+	}
+	// With no pre-tax deductions, gross is exactly take-home plus tax
+	if p.Net.Annual+p.TotalTax != p.Gross.Annual || p.Net.Monthly != tax.NetAnnual.Div(12) {
+		t.Errorf("expected net periods split from %v, got %+v", tax.NetAnnual, p.Net)
+	}
+	if p.PartTime || p.FTE != 1 {
+		t.Errorf("expected a full-time schedule, got part-time=%v fte=%.2f", p.PartTime, p.FTE)
+	}
+}
+
+func TestHourlyToSalarySchedules(t *testing.T) {
+	// 20 hours a week, two weeks unpaid
+	p, _ := HourlyToSalary(20, WorkSchedule{HoursPerWeek: 20, UnpaidTimeOffDays: 10}, TaxInput{})
+	if p.Gross.Annual != NewMoney(20000) || !p.PartTime || p.FTE != 0.5 || p.PaidWeeks != 50 {
+		t.Errorf("expected 20000 part-time over 50 weeks, got %+v", p)
+	}
+
+	// 50 hours a week earns 10 hours of time and a half
+	p, _ = HourlyToSalary(20, WorkSchedule{HoursPerWeek: 50}, TaxInput{})
+	if p.Gross.Annual != NewMoney(20*55*52) || p.OvertimeHours != 10 {
+		t.Errorf("expected %d with overtime, got %s", 20*55*52, p.Gross.Annual)
+	}
+
+	// Paid time off is paid but not worked
+	p, _ = HourlyToSalary(20, WorkSchedule{PaidTimeOffDays: 15, PaidHolidays: 10}, TaxInput{})
+	if p.Gross.Annual != NewMoney(41600) || p.WorkedHours != 1880 || p.EffectiveHourly <= 20 {
+		t.Errorf("expected 41600 over 1880 worked hours, got %s over %.0f", p.Gross.Annual, p.WorkedHours)
+	}
+
+	if _, err := HourlyToSalary(0, WorkSchedule{}, TaxInput{}); err == nil {
+		t.Error("expected an error for a zero rate")
+	}
+	if _, err := HourlyToSalary(20, WorkSchedule{UnpaidTimeOffDays: 260}, TaxInput{}); err == nil {
+		t.Error("expected an error for a year with no working days")
+	}
+}
+
+func TestSalaryToHourly(t *testing.T) {
+	p, err := SalaryToHourly(104000, WorkSchedule{PaidTimeOffDays: 20, PaidHolidays: 6}, TaxInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.HourlyRate != 50 {
+		t.Errorf("expected a 50/hr rate, got %.2f", p.HourlyRate)
+	}
+	// 2080 - 26 days * 8 hours = 1872 hours worked
+	if want := math.Round(104000.0/1872*100) / 100; p.EffectiveHourly != want || p.Gross.Hourly != NewMoney(want) {
+		t.Errorf("expected an effective rate of %.2f, got %.2f", want, p.EffectiveHourly)
+	}
+
+	// Round trip through the other direction
+	schedule := WorkSchedule{HoursPerWeek: 45, DaysPerWeek: 5, UnpaidTimeOffDays: 5}
+	back, _ := SalaryToHourly(80000, schedule, TaxInput{StateTaxRate: 4})
+	there, _ := HourlyToSalary(back.HourlyRate, schedule, TaxInput{StateTaxRate: 4})
+	if math.Abs(there.Gross.Annual.Float()-80000) > 10 {
+		t.Errorf("expected a round trip to 80000, got %s", there.Gross.Annual)
+	}
+}
@@ -0,0 +1,68 @@
+package calc
+
+import (
+	"math"
+	"strconv"
+)
+
+// Money is an exact amount in cents. An amount is rounded once, to the cent,
+// when it becomes Money; totals are then sums of Money, so they reconcile
+// with their parts to the penny.
+type Money int64
+
+// NewMoney converts a dollar amount to Money, rounding half to even.
+func NewMoney(dollars float64) Money {
+	return Money(math.RoundToEven(dollars * 100))
+}
+
+// Float returns m in dollars.
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// Dollars rounds m to whole dollars the way tax forms do: amounts under 50
+// cents are dropped and 50 to 99 cents round up (away from zero).
+func (m Money) Dollars() int {
+	if m < 0 {
+		return -(-m).Dollars()
+	}
+	return int((m + 50) / 100)
+}
+
+// Mul scales m by a rate, rounding half to even.
+func (m Money) Mul(rate float64) Money {
+	return Money(math.RoundToEven(float64(m) * rate))
+}
+
+// Div splits m into n equal parts, rounding half to even.
+func (m Money) Div(n int) Money {
+	return Money(math.RoundToEven(float64(m) / float64(n)))
+}
+
+// String formats m in dollars and cents, e.g. "-1234.50".
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign, m = "-", -m
+	}
+	cents := strconv.FormatInt(int64(m%100), 10)
+	if len(cents) < 2 {
+		cents = "0" + cents
+	}
+	return sign + strconv.FormatInt(int64(m/100), 10) + "." + cents
+}
+
+// MarshalJSON encodes m as a number of dollars with two decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a number of dollars, rounding to the cent.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	dollars, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return err
+	}
+	*m = NewMoney(dollars)
+	return nil
+}
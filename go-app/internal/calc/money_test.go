@@ -0,0 +1,86 @@
+package calc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoneyRounding(t *testing.T) {
+	// Cents round half to even
+	if m := NewMoney(0.125); m != 12 {
+		t.Errorf("expected 0.125 to round to 12 cents, got %d", m)
+	}
+	if m := NewMoney(0.135); m != 14 {
+		t.Errorf("expected 0.135 to round to 14 cents, got %d", m)
+	}
+
+	// Whole dollars round the way tax forms do
+	for _, c := range []struct {
+		m    Money
+		want int
+	}{
+		{NewMoney(1234.49), 1234},
+		{NewMoney(1234.50), 1235},
+		{NewMoney(1235.50), 1236},
+		{NewMoney(-1234.50), -1235},
+	} {
+		if got := c.m.Dollars(); got != c.want {
+			t.Errorf("expected %v to round to %d, got %d", c.m, c.want, got)
+		}
+	}
+
+	if m := NewMoney(100).Mul(0.0765); m != 765 {
+		t.Errorf("expected 7.65%% of 100 to be 765 cents, got %d", m)
+	}
+	if m := NewMoney(100).Div(3); m != 3333 {
+		t.Errorf("expected a third of 100 to be 3333 cents, got %d", m)
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	for _, c := range []struct {
+		m    Money
+		want string
+	}{
+		{NewMoney(1234.5), "1234.50"},
+		{NewMoney(0.07), "0.07"},
+		{NewMoney(-12.3), "-12.30"},
+	} {
+		if got := c.m.String(); got != c.want {
+			t.Errorf("expected %q, got %q", c.want, got)
+		}
+	}
+
+	b, err := json.Marshal(struct {
+		Net Money `json:"net"`
+	}{NewMoney(52000.1)})
+	if err != nil || string(b) != `{"net":52000.10}` {
+		t.Errorf("unexpected JSON %s (%v)", b, err)
+	}
+	var m Money
+	if err := json.Unmarshal([]byte("19.999"), &m); err != nil || m != 2000 {
+		t.Errorf("expected 19.999 to decode to 2000 cents, got %d (%v)", m, err)
+	}
+}
+
+func TestTaxBreakdownReconciles(t *testing.T) {
+	for _, in := range []TaxInput{
+		{GrossAnnual: 87654.32, Retirement401kPercent: 7, HealthInsuranceAnnual: 2345.67, StateTaxRate: 4.95},
+		{GrossAnnual: 123456.78, StateTaxRate: 9.3, HSA: 3000, HealthFSA: 1234.56, DependentCareFSA: 4321.09},
+		{GrossAnnual: 61234.5, SelfEmploymentIncome: 23456.78, LongTermGains: 12345.67, QualifiedDividends: 890.12, StateTaxRate: 5},
+		{GrossAnnual: 400000, ShortTermGains: 5432.1, PassiveIncome: 9876.54, FilingStatus: FilingMarriedJoint},
+	} {
+		r := CalculateTaxBreakdown(in)
+		if r.FICATax != r.SocialSecurity+r.Medicare {
+			t.Errorf("FICA %v doesn't equal Social Security %v plus Medicare %v", r.FICATax, r.SocialSecurity, r.Medicare)
+		}
+		parts := r.FederalTax + r.CapitalGainsTax + r.NIIT + r.StateTax + r.FICATax + r.SelfEmploymentTax +
+			r.Retirement401k + r.HealthInsurance + r.HSA + r.HealthFSA + r.DependentCareFSA
+		if r.TotalDeductions != parts {
+			t.Errorf("total deductions %v don't equal their parts %v", r.TotalDeductions, parts)
+		}
+		if net := r.GrossAnnual + r.InvestmentIncome + r.OtherIncome - r.TotalDeductions; r.NetAnnual != net {
+			t.Errorf("net annual %v doesn't equal income less deductions %v", r.NetAnnual, net)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package calc
+
+import (
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Monte Carlo defaults.
+const (
+	DefaultSimulations = 5000
+	MaxSimulations     = 50000
+	DefaultSeed        = 42
+)
+
+// MonteCarloInput holds the assumptions for a Monte Carlo simulation. Each
+// year's return is drawn from a normal distribution with the given mean and
+// volatility and applied monthly; contributions are added and withdrawals
+// taken at the start of each month.
+type MonteCarloInput struct {
+	InitialBalance      float64 `json:"initial_balance"`
+	MonthlyContribution float64 `json:"monthly_contribution"`
+	MonthlyWithdrawal   float64 `json:"monthly_withdrawal"` // in today's dollars, grows with inflation
+	Years               int     `json:"years"`
+	MeanReturn          float64 `json:"mean_return"` // nominal annual return, percent
+	Volatility          float64 `json:"volatility"`  // standard deviation of annual returns, percent
+	InflationRate       float64 `json:"inflation_rate"`
+	Goal                float64 `json:"goal"` // target ending balance in today's dollars
+	Simulations         int     `json:"simulations"`
+	Seed                uint64  `json:"seed"` // zero uses DefaultSeed
+}
+
+// MonteCarloBand is the spread of simulated balances at the end of a year.
+// The *Pct fields scale each percentile against the largest 90th percentile
+// so bands can be drawn as progress bars.
+type MonteCarloBand struct {
+	Year   int     `json:"year"`
+	P10    Money   `json:"p10"`
+	P50    Money   `json:"p50"`
+	P90    Money   `json:"p90"`
+	P10Pct float64 `json:"p10_pct"`
+	P50Pct float64 `json:"p50_pct"`
+	P90Pct float64 `json:"p90_pct"`
+}
+
+// MonteCarloResult summarizes the simulated outcomes.
+type MonteCarloResult struct {
+	Simulations        int              `json:"simulations"`
+	Years              int              `json:"years"`
+	Seed               uint64           `json:"seed"`
+	P10                Money            `json:"p10"`
+	P50                Money            `json:"p50"`
+	P90                Money            `json:"p90"`
+	RealP10            Money            `json:"real_p10"`
+	RealP50            Money            `json:"real_p50"`
+	RealP90            Money            `json:"real_p90"`
+	ProbabilityGoal    float64          `json:"probability_goal"`    // percent of paths ending at or above the goal
+	ProbabilitySuccess float64          `json:"probability_success"` // percent of paths that never ran out of money
+	Bands              []MonteCarloBand `json:"bands"`
+}
+
+// simulatePath fills balances with the end-of-year balance for each year of
+// one path and reports whether the money ran out, meaning a scheduled
+// withdrawal couldn't be paid in full.
+func simulatePath(in MonteCarloInput, rng *rand.Rand, balances []float64) bool {
+	balance := in.InitialBalance
+	depleted := false
+	for y := 0; y < in.Years; y++ {
+		annual := in.MeanReturn/100 + in.Volatility/100*rng.NormFloat64()
+		monthly := math.Pow(1+math.Max(annual, -1), 1.0/12) - 1
+		withdrawal := in.MonthlyWithdrawal * math.Pow(1+in.InflationRate/100, float64(y))
+		for m := 0; m < 12; m++ {
+			balance += in.MonthlyContribution
+			if withdrawal > 0 && balance < withdrawal {
+				balance, depleted = 0, true
+			} else {
+				balance -= withdrawal
+			}
+			balance *= 1 + monthly
+		}
+		balances[y] = balance
+	}
+	return depleted
+}
+
+// percentile returns the p-th percentile of sorted values using linear
+// interpolation between closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// RunMonteCarlo simulates the input across many random return paths in
+// parallel. Every path draws from its own generator seeded by the input seed
+// and the path index, so results are reproducible regardless of scheduling.
+func RunMonteCarlo(in MonteCarloInput) *MonteCarloResult {
+	if in.Simulations <= 0 {
+		in.Simulations = DefaultSimulations
+	}
+	if in.Simulations > MaxSimulations {
+		in.Simulations = MaxSimulations
+	}
+	if in.Seed == 0 {
+		in.Seed = DefaultSeed
+	}
+	if in.Years < 0 {
+		in.Years = 0
+	}
+
+	result := &MonteCarloResult{Simulations: in.Simulations, Years: in.Years, Seed: in.Seed}
+	if in.Years == 0 {
+		return result
+	}
+
+	// paths[i][y] is the balance of path i at the end of year y+1
+	paths := make([][]float64, in.Simulations)
+	depleted := make([]bool, in.Simulations)
+
+	workers := runtime.GOMAXPROCS(0)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < in.Simulations; i += workers {
+				rng := rand.New(rand.NewPCG(in.Seed, uint64(i)))
+				paths[i] = make([]float64, in.Years)
+				depleted[i] = simulatePath(in, rng, paths[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	column := make([]float64, in.Simulations)
+	for y := 0; y < in.Years; y++ {
+		for i := range paths {
+			column[i] = paths[i][y]
+		}
+		sort.Float64s(column)
+		result.Bands = append(result.Bands, MonteCarloBand{
+			Year: y + 1,
+			P10:  NewMoney(percentile(column, 10)),
+			P50:  NewMoney(percentile(column, 50)),
+			P90:  NewMoney(percentile(column, 90)),
+		})
+	}
+
+	// column holds the sorted final-year balances
+	deflator := math.Pow(1+in.InflationRate/100, float64(in.Years))
+	goal := in.Goal * deflator
+	var hitGoal, survived int
+	for i := range paths {
+		if in.Goal > 0 && paths[i][in.Years-1] >= goal {
+			hitGoal++
+		}
+		if !depleted[i] {
+			survived++
+		}
+	}
+
+	final := result.Bands[in.Years-1]
+	result.P10, result.P50, result.P90 = final.P10, final.P50, final.P90
+	result.RealP10 = final.P10.Mul(1 / deflator)
+	result.RealP50 = final.P50.Mul(1 / deflator)
+	result.RealP90 = final.P90.Mul(1 / deflator)
+	if in.Goal > 0 {
+		result.ProbabilityGoal = math.Round(float64(hitGoal)/float64(in.Simulations)*1000) / 10
+	}
+	result.ProbabilitySuccess = math.Round(float64(survived)/float64(in.Simulations)*1000) / 10
+
+	var scale Money
+	for _, b := range result.Bands {
+		if b.P90 > scale {
+			scale = b.P90
+		}
+	}
+	if scale > 0 {
+		for i := range result.Bands {
+			b := &result.Bands[i]
+			b.P10Pct = math.Round(float64(b.P10)/float64(scale)*1000) / 10
+			b.P50Pct = math.Round(float64(b.P50)/float64(scale)*1000) / 10
+			b.P90Pct = math.Round(float64(b.P90)/float64(scale)*1000) / 10
+		}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package calc
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRunMonteCarlo_Reproducible(t *testing.T) {
+	in := MonteCarloInput{
+		InitialBalance:      10000,
+		MonthlyContribution: 500,
+		Years:               20,
+		MeanReturn:          7,
+		Volatility:          15,
+		InflationRate:       3,
+		Goal:                200000,
+		Simulations:         2000,
+		Seed:                7,
+	}
+	a := RunMonteCarlo(in)
+	b := RunMonteCarlo(in)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("expected identical results for the same seed")
+	}
+
+	in.Seed = 8
+	if c := RunMonteCarlo(in); c.P50 == a.P50 {
+		t.Error("expected a different median for a different seed")
+	}
+
+	if len(a.Bands) != 20 {
+		t.Fatalf("expected 20 bands, got %d", len(a.Bands))
+	}
+	for _, band := range a.Bands {
+		if band.P10 > band.P50 || band.P50 > band.P90 {
+			t.Errorf("year %d: percentiles out of order %s/%s/%s", band.Year, band.P10, band.P50, band.P90)
+		}
+		if band.P90Pct > 100 {
+			t.Errorf("year %d: P90Pct %.1f exceeds 100", band.Year, band.P90Pct)
+		}
+	}
+	if a.RealP50 >= a.P50 {
+		t.Error("real median should be below nominal with positive inflation")
+	}
+	if a.ProbabilityGoal <= 0 || a.ProbabilityGoal >= 100 {
+		t.Errorf("expected a goal probability between 0 and 100, got %.1f", a.ProbabilityGoal)
+	}
+	if a.ProbabilitySuccess != 100 {
+		t.Errorf("paths without withdrawals should never run out, got %.1f", a.ProbabilitySuccess)
+	}
+}
+
+func TestRunMonteCarlo_ZeroVolatility(t *testing.T) {
+	result := RunMonteCarlo(MonteCarloInput{
+		InitialBalance: 10000,
+		Years:          10,
+		MeanReturn:     6,
+		Simulations:    100,
+	})
+
+	want := 10000 * math.Pow(1.06, 10)
+	if math.Abs(result.P10.Float()-want) > 1 || result.P10 != result.P90 {
+		t.Errorf("expected every path to reach %.0f, got %s..%s", want, result.P10, result.P90)
+	}
+}
+
+func TestRunMonteCarlo_EmptyWithoutWithdrawals(t *testing.T) {
+	// Nothing to withdraw means nothing can run out, even from a zero balance
+	result := RunMonteCarlo(MonteCarloInput{Years: 10, MeanReturn: 6, Volatility: 15, Simulations: 100})
+	if result.ProbabilitySuccess != 100 {
+		t.Errorf("expected 100%% success with no withdrawals, got %.1f", result.ProbabilitySuccess)
+	}
+
+	// The same empty account can't pay a withdrawal
+	result = RunMonteCarlo(MonteCarloInput{MonthlyWithdrawal: 100, Years: 10, MeanReturn: 6, Volatility: 15, Simulations: 100})
+	if result.ProbabilitySuccess != 0 {
+		t.Errorf("expected 0%% success withdrawing from an empty account, got %.1f", result.ProbabilitySuccess)
+	}
+}
+
+func TestRunMonteCarlo_Withdrawals(t *testing.T) {
+	result := RunMonteCarlo(MonteCarloInput{
+		InitialBalance:    1000000,
+		MonthlyWithdrawal: 5000,
+		Years:             30,
+		MeanReturn:        6,
+		Volatility:        18,
+		InflationRate:     3,
+	})
+
+	if result.Simulations != DefaultSimulations {
+		t.Errorf("expected %d simulations by default, got %d", DefaultSimulations, result.Simulations)
+	}
+	if result.ProbabilitySuccess <= 0 || result.ProbabilitySuccess >= 100 {
+		t.Errorf("expected some paths to run out of money, got %.1f%% success", result.ProbabilitySuccess)
+	}
+	if result.P10 != 0 {
+		t.Errorf("expected the 10th percentile to be depleted, got %s", result.P10)
+	}
+}
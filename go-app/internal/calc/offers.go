@@ -0,0 +1,165 @@
+package calc
+
+import (
+	"errors"
+	"math"
+)
+
+// WorkDaysPerYear is used to value paid time off at the base daily rate.
+const WorkDaysPerYear = 260
+
+// DefaultEquityVesting is the common four-year even vest.
+var DefaultEquityVesting = []float64{25, 25, 25, 25}
+
+// JobOffer holds one offer for a side-by-side comparison. Equity is the
+// total grant value, vested by the yearly percentages in EquityVesting.
+type JobOffer struct {
+	Name            string      `json:"name"`
+	BaseSalary      float64     `json:"base_salary"`
+	Bonus           float64     `json:"bonus"`
+	EquityGrant     float64     `json:"equity_grant"`
+	EquityVesting   []float64   `json:"equity_vesting"`   // percent of the grant vesting each year
+	ContributionPct float64     `json:"contribution_pct"` // employee 401(k) deferral, percent of base
+	EmployerMatch   []MatchTier `json:"employer_match"`
+	HealthPremium   float64     `json:"health_premium"` // employee share, annual, pre-tax
+	CommuteCost     float64     `json:"commute_cost"`   // annual
+	PTODays         int         `json:"pto_days"`
+	Location        Location    `json:"location"`
+}
+
+// OfferResult is the annual after-tax value of one offer. Equity is the
+// average yearly vest over the schedule.
+type OfferResult struct {
+	Name             string        `json:"name"`
+	BaseSalary       Money         `json:"base_salary"`
+	Bonus            Money         `json:"bonus"`
+	Equity           Money         `json:"equity"`
+	EquityFirstYear  Money         `json:"equity_first_year"`
+	VestingYears     int           `json:"vesting_years"`
+	Contribution401k Money         `json:"contribution_401k"`
+	EmployerMatch    Money         `json:"employer_match"`
+	TotalComp        Money         `json:"total_comp"` // base + bonus + equity + match
+	Taxes            LocationTaxes `json:"taxes"`
+	HealthPremium    Money         `json:"health_premium"`
+	CommuteCost      Money         `json:"commute_cost"`
+	PTODays          int           `json:"pto_days"`
+	PTOValue         Money         `json:"pto_value"`
+	// AfterTaxComp is take-home pay plus 401(k) savings from both sides and
+	// PTOValue at the take-home rate, less commuting, so more paid days off
+	// rank an offer higher.
+	AfterTaxComp Money `json:"after_tax_comp"`
+	// AdjustedComp restates AfterTaxComp at national-average prices.
+	AdjustedComp Money `json:"adjusted_comp"`
+	Best         bool  `json:"best"`
+}
+
+// OfferComparison ranks offers by cost-of-living-adjusted after-tax value.
+type OfferComparison struct {
+	Offers []OfferResult `json:"offers"`
+	Best   int           `json:"best"`   // index into Offers
+	Margin Money         `json:"margin"` // adjusted lead over the runner-up
+}
+
+// vestingYears returns the schedule's yearly percentages, defaulting to an
+// even four-year vest.
+func vestingYears(schedule []float64) []float64 {
+	for _, pct := range schedule {
+		if pct > 0 {
+			return schedule
+		}
+	}
+	return DefaultEquityVesting
+}
+
+// EvaluateOffer computes the after-tax value of a single offer. Bonus and
+// vested equity are taxed as wages alongside base salary.
+func EvaluateOffer(o JobOffer) OfferResult {
+	base := math.Max(0, o.BaseSalary)
+	bonus := math.Max(0, o.Bonus)
+
+	schedule := vestingYears(o.EquityVesting)
+	var vested float64
+	for _, pct := range schedule {
+		vested += pct
+	}
+	grant := math.Max(0, o.EquityGrant) * math.Min(vested, 100) / 100
+	equity := grant / float64(len(schedule))
+	equityFirst := math.Max(0, o.EquityGrant) * schedule[0] / 100
+
+	// Deferrals past the IRS limit aren't possible, so they earn no match
+	deferralPct := math.Max(0, o.ContributionPct)
+	if base > 0 {
+		deferralPct = math.Min(deferralPct, Limit401k/base*100)
+	}
+	contribution := base * deferralPct / 100
+	match := EmployerMatchAmount(base, deferralPct, o.EmployerMatch)
+
+	gross := base + bonus + equity
+	contribPct := 0.0
+	if gross > 0 {
+		contribPct = contribution / gross * 100
+	}
+	taxes := locationTaxes(TaxInput{
+		GrossAnnual:           gross,
+		Retirement401kPercent: contribPct,
+		HealthInsuranceAnnual: math.Max(0, o.HealthPremium),
+	}, o.Location)
+
+	// Round each part to the cent, then build the totals from the parts
+	r := OfferResult{
+		Name:             o.Name,
+		BaseSalary:       NewMoney(base),
+		Bonus:            NewMoney(bonus),
+		Equity:           NewMoney(equity),
+		EquityFirstYear:  NewMoney(equityFirst),
+		VestingYears:     len(schedule),
+		Contribution401k: NewMoney(contribution),
+		EmployerMatch:    NewMoney(match),
+		Taxes:            taxes,
+		HealthPremium:    NewMoney(math.Max(0, o.HealthPremium)),
+		CommuteCost:      NewMoney(math.Max(0, o.CommuteCost)),
+		PTODays:          o.PTODays,
+		PTOValue:         NewMoney(base / WorkDaysPerYear * float64(max(0, o.PTODays))),
+	}
+	r.TotalComp = r.BaseSalary + r.Bonus + r.Equity + r.EmployerMatch
+	var takeHomeShare float64
+	if gross > 0 {
+		takeHomeShare = taxes.NetAnnual.Float() / gross
+	}
+	r.AfterTaxComp = taxes.NetAnnual + r.Contribution401k + r.EmployerMatch + r.PTOValue.Mul(takeHomeShare) - r.CommuteCost
+	r.AdjustedComp = r.AfterTaxComp
+	if o.Location.CostOfLiving > 0 {
+		r.AdjustedComp = r.AfterTaxComp.Mul(100 / o.Location.CostOfLiving)
+	}
+	return r
+}
+
+// CompareOffers evaluates each offer and marks the one worth the most after
+// tax and cost of living.
+func CompareOffers(offers []JobOffer) (*OfferComparison, error) {
+	if len(offers) < 2 {
+		return nil, errors.New("at least two offers are needed")
+	}
+
+	c := &OfferComparison{}
+	for i, o := range offers {
+		if o.BaseSalary <= 0 {
+			return nil, errors.New("each offer needs a base salary")
+		}
+		r := EvaluateOffer(o)
+		c.Offers = append(c.Offers, r)
+		if r.AdjustedComp > c.Offers[c.Best].AdjustedComp {
+			c.Best = i
+		}
+	}
+	c.Offers[c.Best].Best = true
+
+	runnerUp := Money(math.MinInt64)
+	for i, r := range c.Offers {
+		if i != c.Best && r.AdjustedComp > runnerUp {
+			runnerUp = r.AdjustedComp
+		}
+	}
+	c.Margin = c.Offers[c.Best].AdjustedComp - runnerUp
+	return c, nil
+}
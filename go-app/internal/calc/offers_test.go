@@ -0,0 +1,95 @@
+package calc
+
+import "testing"
+
+func TestEvaluateOffer(t *testing.T) {
+	o := JobOffer{
+		Name:            "Startup",
+		BaseSalary:      100000,
+		Bonus:           10000,
+		EquityGrant:     40000,
+		ContributionPct: 6,
+		EmployerMatch:   MatchHalfUpTo6,
+		HealthPremium:   2400,
+		CommuteCost:     1200,
+		PTODays:         15,
+		Location:        Location{Name: "Austin", CostOfLiving: 100},
+	}
+	r := EvaluateOffer(o)
+
+	if r.Equity != NewMoney(10000) || r.EquityFirstYear != NewMoney(10000) || r.VestingYears != 4 {
+		t.Errorf("expected an even four-year vest of 10000, got %s (%s first year, %d years)", r.Equity, r.EquityFirstYear, r.VestingYears)
+	}
+	if r.Contribution401k != NewMoney(6000) || r.EmployerMatch != NewMoney(3000) {
+		t.Errorf("expected 6000 deferred and 3000 matched, got %s and %s", r.Contribution401k, r.EmployerMatch)
+	}
+	if r.TotalComp != NewMoney(123000) {
+		t.Errorf("expected 123000 total comp, got %s", r.TotalComp)
+	}
+
+	// Everything but base is taxed as wages, with the deferral and premium pre-tax
+	tax := CalculateTaxes(120000, 5, 2400, 0)
+	if r.Taxes.NetAnnual != tax.NetAnnual {
+		t.Errorf("expected take-home %v, got %v", tax.NetAnnual, r.Taxes.NetAnnual)
+	}
+	pto := r.PTOValue.Mul(tax.NetAnnual.Float() / 120000)
+	if want := tax.NetAnnual + NewMoney(6000+3000-1200) + pto; r.AfterTaxComp != want || r.AdjustedComp != want {
+		t.Errorf("expected after-tax comp %s, got %s (adjusted %s)", want, r.AfterTaxComp, r.AdjustedComp)
+	}
+	if r.PTOValue != NewMoney(5769.23) {
+		t.Errorf("expected 15 days of PTO worth 5769.23, got %s", r.PTOValue)
+	}
+}
+
+func TestEvaluateOfferBackloadedVesting(t *testing.T) {
+	r := EvaluateOffer(JobOffer{BaseSalary: 150000, EquityGrant: 100000, EquityVesting: []float64{5, 15, 40, 40}})
+	if r.EquityFirstYear != NewMoney(5000) || r.Equity != NewMoney(25000) {
+		t.Errorf("expected 5000 in year one and 25000 on average, got %s and %s", r.EquityFirstYear, r.Equity)
+	}
+
+	// The deferral is capped at the IRS limit
+	r = EvaluateOffer(JobOffer{BaseSalary: 400000, ContributionPct: 10, EmployerMatch: MatchHalfUpTo6})
+	if r.Contribution401k != NewMoney(Limit401k) {
+		t.Errorf("expected the deferral capped at %.0f, got %s", Limit401k, r.Contribution401k)
+	}
+}
+
+func TestCompareOffers_PTO(t *testing.T) {
+	// Identical pay, so the extra week off decides it
+	c, err := CompareOffers([]JobOffer{
+		{Name: "Lean", BaseSalary: 100000, PTODays: 10},
+		{Name: "Generous", BaseSalary: 100000, PTODays: 15},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Best != 1 || c.Margin <= 0 {
+		t.Errorf("expected more PTO to win, got best %d by %s", c.Best, c.Margin)
+	}
+}
+
+func TestCompareOffers(t *testing.T) {
+	// A higher salary in an expensive, taxed city can lose to a lower one
+	nyc := JobOffer{Name: "NYC", BaseSalary: 150000, Location: Location{CostOfLiving: 187, StateTaxRate: 6.85, LocalTaxRate: 3.876}}
+	austin := JobOffer{Name: "Austin", BaseSalary: 110000, Location: Location{CostOfLiving: 102}}
+	c, err := CompareOffers([]JobOffer{nyc, austin})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Best != 1 || !c.Offers[1].Best || c.Offers[0].Best {
+		t.Errorf("expected Austin to win after cost of living, got %+v", c.Offers)
+	}
+	if c.Offers[0].AfterTaxComp <= c.Offers[1].AfterTaxComp {
+		t.Error("expected NYC to pay more before the cost-of-living adjustment")
+	}
+	if c.Margin != c.Offers[1].AdjustedComp-c.Offers[0].AdjustedComp {
+		t.Errorf("unexpected margin %s", c.Margin)
+	}
+
+	if _, err := CompareOffers([]JobOffer{nyc}); err == nil {
+		t.Error("expected an error for a single offer")
+	}
+	if _, err := CompareOffers([]JobOffer{nyc, {Name: "Empty"}}); err == nil {
+		t.Error("expected an error for an offer without a base salary")
+	}
+}
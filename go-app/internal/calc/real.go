@@ -0,0 +1,49 @@
+package calc
+
+import "math"
+
+// DefaultInflationRate is the inflation assumption, in percent, used to
+// express projections in today's dollars.
+const DefaultInflationRate = 3.0
+
+// Dollars is a projected amount in both nominal (future) dollars and real
+// (today's) dollars.
+type Dollars struct {
+	Nominal Money `json:"nominal"`
+	Real    Money `json:"real"`
+}
+
+// RealValue discounts a nominal amount received years from now into today's
+// dollars.
+func RealValue(nominal, inflationRate, years float64) float64 {
+	return nominal / math.Pow(1+inflationRate/100, years)
+}
+
+// NewDollars rounds a nominal amount years from now and its real value.
+func NewDollars(nominal, inflationRate, years float64) Dollars {
+	return Dollars{
+		Nominal: NewMoney(nominal),
+		Real:    NewMoney(RealValue(nominal, inflationRate, years)),
+	}
+}
+
+// Sub returns d less e in both nominal and real terms.
+func (d Dollars) Sub(e Dollars) Dollars {
+	return Dollars{Nominal: d.Nominal - e.Nominal, Real: d.Real - e.Real}
+}
+
+// dollarFlow accumulates cash flows spread over time in both nominal and
+// real terms.
+type dollarFlow struct {
+	nominal, real float64
+}
+
+// add records an amount paid years from now.
+func (f *dollarFlow) add(amount, inflationRate, years float64) {
+	f.nominal += amount
+	f.real += RealValue(amount, inflationRate, years)
+}
+
+func (f dollarFlow) dollars() Dollars {
+	return Dollars{Nominal: NewMoney(f.nominal), Real: NewMoney(f.real)}
+}
@@ -0,0 +1,82 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRealValue(t *testing.T) {
+	if got := RealValue(1000, 0, 10); got != 1000 {
+		t.Errorf("expected no change without inflation, got %.2f", got)
+	}
+	d := NewDollars(1000, 3, 10)
+	if d.Nominal != NewMoney(1000) || d.Real != NewMoney(744.09) {
+		t.Errorf("expected 1000 nominal and 744.09 real, got %+v", d)
+	}
+}
+
+func TestCalculateCompound(t *testing.T) {
+	result := CalculateCompound(CompoundInput{Principal: 10000, MonthlyContribution: 500, ReturnRate: 7, Years: 20, InflationRate: 3})
+
+	// Matches the month-by-month loop the calculator has always used
+	balance := 10000.0
+	for m := 0; m < 240; m++ {
+		balance = balance*(1+0.07/12) + 500
+	}
+	if result.FutureValue.Nominal != NewMoney(balance) {
+		t.Errorf("expected nominal future value %.2f, got %s", balance, result.FutureValue.Nominal)
+	}
+	if result.TotalInvested.Nominal != NewMoney(130000) || result.TotalInvested.Real >= NewMoney(130000) {
+		t.Errorf("expected 130000 invested nominal and less in real terms, got %+v", result.TotalInvested)
+	}
+	wantReal := balance / math.Pow(1.03, 20)
+	if result.FutureValue.Real != NewMoney(wantReal) {
+		t.Errorf("expected real future value %.2f, got %s", wantReal, result.FutureValue.Real)
+	}
+	if len(result.Yearly) != 20 || result.Yearly[19].Balance != result.FutureValue {
+		t.Errorf("expected the last year to match the future value, got %d years", len(result.Yearly))
+	}
+	if result.InterestEarned.Real >= result.InterestEarned.Nominal {
+		t.Errorf("expected less real interest than nominal, got %+v", result.InterestEarned)
+	}
+	if result.InterestEarned != result.FutureValue.Sub(result.TotalInvested) {
+		t.Errorf("expected interest to be the future value less what was invested, got %+v", result.InterestEarned)
+	}
+}
+
+func TestCompareRentVsBuy(t *testing.T) {
+	in := RentVsBuyInput{
+		HomePrice:      400000,
+		DownPaymentPct: 20,
+		MortgageRate:   6.5,
+		Appreciation:   3,
+		MonthlyRent:    2000,
+		RentIncrease:   3,
+		Years:          10,
+		InflationRate:  3,
+	}
+	result := CompareRentVsBuy(in)
+
+	if result.DownPayment != NewMoney(80000) || result.RentStart != NewMoney(2000) {
+		t.Errorf("unexpected inputs echoed: %+v", result)
+	}
+	// Appreciation equal to inflation keeps the home's real value flat
+	if result.HomeValue.Real != NewMoney(400000) {
+		t.Errorf("expected a real home value of 400000, got %s", result.HomeValue.Real)
+	}
+	if result.BuyNetCost.Nominal != result.BuyTotalPaid.Nominal-result.Equity.Nominal {
+		t.Errorf("buy net cost doesn't add up: %+v", result)
+	}
+	if result.BuyAdvantage.Nominal != result.RentNetCost.Nominal-result.BuyNetCost.Nominal {
+		t.Errorf("advantage doesn't add up: %+v", result)
+	}
+	if result.RentTotal.Real >= result.RentTotal.Nominal || result.PriceToRent < 16.6 || result.PriceToRent > 16.7 {
+		t.Errorf("unexpected rent totals %+v or price-to-rent %.2f", result.RentTotal, result.PriceToRent)
+	}
+
+	in.InflationRate = 0
+	flat := CompareRentVsBuy(in)
+	if flat.RentTotal.Real != flat.RentTotal.Nominal || flat.Equity.Real != flat.Equity.Nominal {
+		t.Errorf("expected real to equal nominal without inflation, got %+v", flat)
+	}
+}
@@ -0,0 +1,281 @@
+package calc
+
+import "math"
+
+// Rental property assumptions.
+const (
+	ResidentialRecoveryYears = 27.5 // straight-line depreciation period for residential rentals
+	DepreciationRecaptureMax = 25.0 // maximum rate on unrecaptured section 1250 gain, percent
+	DefaultRentalHoldYears   = 10
+	DefaultSellingCostPct    = 6.0
+	DefaultLandValuePct      = 20.0 // share of the price that is land and can't be depreciated
+	DefaultRentalGainsRate   = 15.0
+	PMIRemovalLTV            = 78.0 // loan-to-value, percent of the purchase price, at which PMI ends
+)
+
+// RentalInput describes a rental property purchase. Vacancy is a percent of
+// scheduled rent; management, maintenance and capex reserves are percents of
+// collected rent. Property tax is a percent of the purchase price.
+type RentalInput struct {
+	PurchasePrice   float64 `json:"purchase_price"`
+	ClosingCosts    float64 `json:"closing_costs"`
+	DownPaymentPct  float64 `json:"down_payment_pct"`
+	InterestRate    float64 `json:"interest_rate"`
+	TermYears       int     `json:"term_years"`
+	MonthlyRent     float64 `json:"monthly_rent"`
+	VacancyPct      float64 `json:"vacancy_pct"`
+	ManagementPct   float64 `json:"management_pct"`
+	MaintenancePct  float64 `json:"maintenance_pct"`
+	CapExPct        float64 `json:"capex_pct"`
+	PropertyTaxRate float64 `json:"property_tax_rate"`
+	AnnualInsurance float64 `json:"annual_insurance"`
+	MonthlyHOA      float64 `json:"monthly_hoa"`
+
+	// Multi-year projection
+	HoldYears        int     `json:"hold_years"`
+	AppreciationPct  float64 `json:"appreciation_pct"`
+	RentGrowthPct    float64 `json:"rent_growth_pct"`
+	ExpenseGrowthPct float64 `json:"expense_growth_pct"` // property tax, insurance and HOA
+	SellingCostPct   float64 `json:"selling_cost_pct"`
+	LandValuePct     float64 `json:"land_value_pct"`
+	MarginalTaxRate  float64 `json:"marginal_tax_rate"`  // on rental income or losses, percent
+	CapitalGainsRate float64 `json:"capital_gains_rate"` // on appreciation at sale, percent
+}
+
+// RentalMonthly is the first-year monthly operating budget.
+type RentalMonthly struct {
+	ScheduledRent Money `json:"scheduled_rent"`
+	Vacancy       Money `json:"vacancy"`
+	EffectiveRent Money `json:"effective_rent"`
+	Mortgage      Money `json:"mortgage"` // principal, interest and any PMI
+	PropertyTax   Money `json:"property_tax"`
+	Insurance     Money `json:"insurance"`
+	HOA           Money `json:"hoa"`
+	Management    Money `json:"management"`
+	Maintenance   Money `json:"maintenance"`
+	CapEx         Money `json:"capex"`
+	TotalExpenses Money `json:"total_expenses"`
+	CashFlow      Money `json:"cash_flow"`
+}
+
+// RentalYear is one year of the hold-period projection.
+type RentalYear struct {
+	Year           int   `json:"year"`
+	NOI            Money `json:"noi"`
+	CashFlow       Money `json:"cash_flow"` // before income tax
+	Interest       Money `json:"interest"`
+	PMI            Money `json:"pmi"`
+	Depreciation   Money `json:"depreciation"`
+	TaxableIncome  Money `json:"taxable_income"` // negative for a paper loss
+	IncomeTax      Money `json:"income_tax"`     // negative when the loss saves tax
+	AfterTaxCash   Money `json:"after_tax_cash"`
+	PropertyValue  Money `json:"property_value"`
+	LoanBalance    Money `json:"loan_balance"`
+	Equity         Money `json:"equity"`
+	CumulativeCash Money `json:"cumulative_cash"`
+}
+
+// RentalAnalysis is the cash-flow and return analysis of a rental property.
+type RentalAnalysis struct {
+	Mortgage           *MortgageResult `json:"mortgage"`
+	CashInvested       Money           `json:"cash_invested"` // down payment plus closing costs
+	Monthly            RentalMonthly   `json:"monthly"`
+	AnnualCashFlow     Money           `json:"annual_cash_flow"`
+	NOI                Money           `json:"noi"`
+	CapRate            float64         `json:"cap_rate"`
+	CashOnCash         float64         `json:"cash_on_cash"`
+	DSCR               float64         `json:"dscr"`
+	AnnualDepreciation Money           `json:"annual_depreciation"`
+
+	// Sale at the end of the hold period
+	HoldYears      int          `json:"hold_years"`
+	SalePrice      Money        `json:"sale_price"`
+	SaleCosts      Money        `json:"sale_costs"`
+	SaleTax        Money        `json:"sale_tax"` // depreciation recapture plus capital gains
+	SaleProceeds   Money        `json:"sale_proceeds"`
+	IRR            float64      `json:"irr"` // after-tax, percent
+	IRRValid       bool         `json:"irr_valid"`
+	EquityMultiple float64      `json:"equity_multiple"`
+	Years          []RentalYear `json:"years"`
+}
+
+// IRR returns the internal rate of return of annual cash flows, where
+// flows[0] is the initial investment (negative). It reports false when the
+// flows don't change sign within the search range.
+func IRR(flows []float64) (float64, bool) {
+	npv := func(rate float64) float64 {
+		var total float64
+		for t, f := range flows {
+			total += f / math.Pow(1+rate, float64(t))
+		}
+		return total
+	}
+	lo, hi := -0.99, 10.0
+	if npv(lo)*npv(hi) > 0 {
+		return 0, false
+	}
+	for i := 0; i < 200; i++ {
+		mid := (lo + hi) / 2
+		if npv(lo)*npv(mid) <= 0 {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return (lo + hi) / 2, true
+}
+
+// AnalyzeRental computes first-year cash flow, cap rate, cash-on-cash return
+// and DSCR, then projects the hold period with rent growth, appreciation and
+// depreciation to an after-tax IRR including the sale.
+func AnalyzeRental(in RentalInput) *RentalAnalysis {
+	termYears := in.TermYears
+	if termYears <= 0 {
+		termYears = 30
+	}
+	holdYears := in.HoldYears
+	if holdYears <= 0 {
+		holdYears = DefaultRentalHoldYears
+	}
+	sellingCostPct := in.SellingCostPct
+	if sellingCostPct <= 0 {
+		sellingCostPct = DefaultSellingCostPct
+	}
+	landValuePct := in.LandValuePct
+	if landValuePct <= 0 {
+		landValuePct = DefaultLandValuePct
+	}
+	gainsRate := in.CapitalGainsRate
+	if gainsRate <= 0 {
+		gainsRate = DefaultRentalGainsRate
+	}
+
+	mortgage := CalculateMortgage(in.PurchasePrice, in.DownPaymentPct, in.InterestRate, termYears, in.PropertyTaxRate, in.AnnualInsurance)
+	loan := mortgage.LoanAmount.Float()
+	payment := amortizedPayment(loan, in.InterestRate, termYears*12)
+	pmi := mortgage.PITI.PMI.Float()
+	cashInvested := mortgage.DownPayment.Float() + in.ClosingCosts
+
+	// Closing costs are added to the basis; only the building depreciates
+	basis := in.PurchasePrice + in.ClosingCosts
+	depreciable := basis * (1 - landValuePct/100)
+	depreciation := depreciable / ResidentialRecoveryYears
+
+	result := &RentalAnalysis{
+		Mortgage:           mortgage,
+		CashInvested:       NewMoney(cashInvested),
+		AnnualDepreciation: NewMoney(depreciation),
+		HoldYears:          holdYears,
+	}
+
+	flows := []float64{-cashInvested}
+	balance := loan
+	rent := in.MonthlyRent * 12
+	fixed := in.PurchasePrice*in.PropertyTaxRate/100 + in.AnnualInsurance + in.MonthlyHOA*12
+	value := in.PurchasePrice
+	monthlyRate := in.InterestRate / 100 / 12
+	// PMI is dropped automatically once the balance is amortized down to 78%
+	// of the original value
+	pmiRemovalBalance := in.PurchasePrice * PMIRemovalLTV / 100
+	var cumulative, totalDepreciation float64
+	for year := 1; year <= holdYears; year++ {
+		collected := rent * (1 - in.VacancyPct/100)
+		variable := collected * (in.ManagementPct + in.MaintenancePct + in.CapExPct) / 100
+		noi := collected - fixed - variable
+
+		var interest, pmiPaid, debtService float64
+		for m := 0; m < 12 && balance > 0.005; m++ {
+			monthInterest := balance * monthlyRate
+			principal := math.Min(payment-monthInterest, balance)
+			monthPMI := pmi
+			if balance <= pmiRemovalBalance {
+				monthPMI = 0
+			}
+			interest += monthInterest
+			pmiPaid += monthPMI
+			debtService += monthInterest + principal + monthPMI
+			balance -= principal
+		}
+		cashFlow := noi - debtService
+
+		yearDepreciation := math.Min(depreciation, depreciable-totalDepreciation)
+		totalDepreciation += yearDepreciation
+		taxable := noi - interest - pmiPaid - yearDepreciation
+		incomeTax := taxable * in.MarginalTaxRate / 100
+		afterTax := cashFlow - incomeTax
+		cumulative += afterTax
+		value *= 1 + in.AppreciationPct/100
+
+		if year == 1 {
+			monthly := RentalMonthly{
+				ScheduledRent: NewMoney(rent / 12),
+				Vacancy:       NewMoney((rent - collected) / 12),
+				EffectiveRent: NewMoney(collected / 12),
+				Mortgage:      NewMoney(payment + pmi),
+				PropertyTax:   mortgage.PITI.PropertyTax,
+				Insurance:     mortgage.PITI.Insurance,
+				HOA:           NewMoney(in.MonthlyHOA),
+				Management:    NewMoney(collected * in.ManagementPct / 100 / 12),
+				Maintenance:   NewMoney(collected * in.MaintenancePct / 100 / 12),
+				CapEx:         NewMoney(collected * in.CapExPct / 100 / 12),
+				CashFlow:      NewMoney(cashFlow / 12),
+			}
+			monthly.TotalExpenses = monthly.EffectiveRent - monthly.CashFlow
+			result.Monthly = monthly
+			result.AnnualCashFlow = NewMoney(cashFlow)
+			result.NOI = NewMoney(noi)
+			if in.PurchasePrice > 0 {
+				result.CapRate = math.Round(noi/in.PurchasePrice*1000) / 10
+			}
+			if cashInvested > 0 {
+				result.CashOnCash = math.Round(cashFlow/cashInvested*1000) / 10
+			}
+			if debtService > 0 {
+				result.DSCR = math.Round(noi/debtService*100) / 100
+			}
+		}
+
+		result.Years = append(result.Years, RentalYear{
+			Year:           year,
+			NOI:            NewMoney(noi),
+			CashFlow:       NewMoney(cashFlow),
+			Interest:       NewMoney(interest),
+			PMI:            NewMoney(pmiPaid),
+			Depreciation:   NewMoney(yearDepreciation),
+			TaxableIncome:  NewMoney(taxable),
+			IncomeTax:      NewMoney(incomeTax),
+			AfterTaxCash:   NewMoney(afterTax),
+			PropertyValue:  NewMoney(value),
+			LoanBalance:    NewMoney(balance),
+			Equity:         NewMoney(value - balance),
+			CumulativeCash: NewMoney(cumulative),
+		})
+		flows = append(flows, afterTax)
+
+		rent *= 1 + in.RentGrowthPct/100
+		fixed *= 1 + in.ExpenseGrowthPct/100
+	}
+
+	// Sale: depreciation taken is recaptured at up to 25%, the rest of the
+	// gain over the original basis is taxed at capital gains rates
+	saleCosts := value * sellingCostPct / 100
+	gain := value - saleCosts - (basis - totalDepreciation)
+	recaptured := math.Min(math.Max(0, gain), totalDepreciation)
+	saleTax := recaptured*math.Min(in.MarginalTaxRate, DepreciationRecaptureMax)/100 +
+		math.Max(0, gain-recaptured)*gainsRate/100
+	proceeds := value - saleCosts - balance - saleTax
+	flows[len(flows)-1] += proceeds
+
+	result.SalePrice = NewMoney(value)
+	result.SaleCosts = NewMoney(saleCosts)
+	result.SaleTax = NewMoney(saleTax)
+	result.SaleProceeds = NewMoney(proceeds)
+	if irr, ok := IRR(flows); ok {
+		result.IRR = math.Round(irr*1000) / 10
+		result.IRRValid = true
+	}
+	if cashInvested > 0 {
+		result.EquityMultiple = math.Round((cumulative+proceeds)/cashInvested*100) / 100
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIRR(t *testing.T) {
+	if irr, ok := IRR([]float64{-100, 110}); !ok || math.Abs(irr-0.10) > 1e-6 {
+		t.Errorf("expected 10%%, got %.6f (%v)", irr, ok)
+	}
+	if irr, ok := IRR([]float64{-1000, 0, 0, 1331}); !ok || math.Abs(irr-0.10) > 1e-6 {
+		t.Errorf("expected 10%%, got %.6f (%v)", irr, ok)
+	}
+	if _, ok := IRR([]float64{100, 100}); ok {
+		t.Error("expected no IRR for flows that never change sign")
+	}
+}
+
+func rentalFixture() RentalInput {
+	return RentalInput{
+		PurchasePrice:    200000,
+		DownPaymentPct:   25,
+		InterestRate:     7,
+		TermYears:        30,
+		MonthlyRent:      2000,
+		VacancyPct:       5,
+		ManagementPct:    8,
+		MaintenancePct:   5,
+		CapExPct:         5,
+		PropertyTaxRate:  1.2,
+		AnnualInsurance:  1200,
+		AppreciationPct:  3,
+		RentGrowthPct:    3,
+		ExpenseGrowthPct: 2,
+		MarginalTaxRate:  22,
+	}
+}
+
+func TestAnalyzeRental_FirstYear(t *testing.T) {
+	result := AnalyzeRental(rentalFixture())
+
+	// 22,800 collected less 3,600 tax and insurance and 4,104 reserves
+	if result.NOI != NewMoney(15096) {
+		t.Errorf("expected NOI 15096, got %s", result.NOI)
+	}
+	if result.CapRate != 7.5 {
+		t.Errorf("expected cap rate 7.5, got %.1f", result.CapRate)
+	}
+	if result.Monthly.Mortgage != NewMoney(997.95) {
+		t.Errorf("expected a 997.95 mortgage payment, got %s", result.Monthly.Mortgage)
+	}
+	// 15,096 NOI less 11,975.44 of debt service on 50,000 invested
+	if result.AnnualCashFlow != NewMoney(3120.56) || result.CashOnCash != 6.2 {
+		t.Errorf("expected 3120.56 cash flow and 6.2%% cash-on-cash, got %s and %.1f", result.AnnualCashFlow, result.CashOnCash)
+	}
+	if result.DSCR != 1.26 {
+		t.Errorf("expected DSCR 1.26, got %.2f", result.DSCR)
+	}
+	if result.AnnualDepreciation != NewMoney(5818.18) {
+		t.Errorf("expected 5818.18 of depreciation on the building, got %s", result.AnnualDepreciation)
+	}
+	m := result.Monthly
+	if m.EffectiveRent-m.TotalExpenses != m.CashFlow {
+		t.Errorf("monthly budget doesn't balance: %s - %s != %s", m.EffectiveRent, m.TotalExpenses, m.CashFlow)
+	}
+}
+
+func TestAnalyzeRental_HoldPeriod(t *testing.T) {
+	result := AnalyzeRental(rentalFixture())
+
+	if len(result.Years) != DefaultRentalHoldYears {
+		t.Fatalf("expected %d years, got %d", DefaultRentalHoldYears, len(result.Years))
+	}
+	if !result.IRRValid || result.IRR <= result.CashOnCash {
+		t.Errorf("expected appreciation and paydown to lift IRR above cash-on-cash, got %.1f", result.IRR)
+	}
+	last := result.Years[len(result.Years)-1]
+	if want := NewMoney(200000 * math.Pow(1.03, 10)); math.Abs((last.PropertyValue - want).Float()) > 1 {
+		t.Errorf("expected value %s after 10 years, got %s", want, last.PropertyValue)
+	}
+	if last.LoanBalance >= result.Mortgage.LoanAmount {
+		t.Error("expected the loan to amortize")
+	}
+	// Recapture at 22% on 58,182 of depreciation plus 15% on the appreciation
+	if result.SaleTax <= 0 || result.SaleProceeds <= 0 {
+		t.Errorf("expected a taxable sale with positive proceeds, got %s tax and %s proceeds", result.SaleTax, result.SaleProceeds)
+	}
+	if result.EquityMultiple <= 1 {
+		t.Errorf("expected an equity multiple above 1, got %.2f", result.EquityMultiple)
+	}
+}
+
+func TestAnalyzeRental_AllCash(t *testing.T) {
+	in := rentalFixture()
+	in.DownPaymentPct = 100
+	result := AnalyzeRental(in)
+
+	if result.AnnualCashFlow != result.NOI {
+		t.Errorf("with no loan cash flow should equal NOI, got %s vs %s", result.AnnualCashFlow, result.NOI)
+	}
+	if result.DSCR != 0 {
+		t.Errorf("expected no DSCR without debt, got %.2f", result.DSCR)
+	}
+	if result.CashOnCash != result.CapRate {
+		t.Errorf("all-cash cash-on-cash should match the cap rate, got %.1f vs %.1f", result.CashOnCash, result.CapRate)
+	}
+}
+
+func TestAnalyzeRental_PMIRemoval(t *testing.T) {
+	in := rentalFixture()
+	in.PurchasePrice = 200000
+	in.DownPaymentPct = 5
+	in.InterestRate = 4
+	in.HoldYears = 15
+	result := AnalyzeRental(in)
+
+	// $79.17 a month (0.5% of the 190,000 loan) until the balance is amortized
+	// down to 78% of the 200,000 price
+	removal := NewMoney(156000)
+	prevBalance := NewMoney(190000)
+	dropped := false
+	for _, y := range result.Years {
+		switch {
+		case prevBalance <= removal && y.PMI != 0:
+			t.Errorf("year %d: expected no PMI once the balance is %s, got %s", y.Year, prevBalance, y.PMI)
+		case y.LoanBalance > removal && y.PMI != NewMoney(950.04):
+			t.Errorf("year %d: expected a full year of PMI above 78%%, got %s", y.Year, y.PMI)
+		}
+		dropped = dropped || y.PMI == 0
+		prevBalance = y.LoanBalance
+	}
+	if !dropped {
+		t.Error("expected PMI to end during the hold")
+	}
+}
@@ -0,0 +1,103 @@
+package calc
+
+import "math"
+
+// Rent-vs-buy assumptions.
+const (
+	RentVsBuyPropertyTaxRate = 1.1   // percent of price per year
+	RentVsBuyMonthlyInsure   = 100.0 // homeowners insurance per month
+	RentVsBuyMaintenanceRate = 1.0   // percent of price per year
+	RentVsBuyPMIRate         = 0.5   // percent of the loan per year under 20% down
+	RentVsBuyInvestReturn    = 7.0   // return on the down payment if renting, percent
+	RentVsBuyPrincipalShare  = 0.30  // rough share of early mortgage payments going to principal
+	RentVsBuyTermMonths      = 360
+)
+
+// RentVsBuyInput describes the home and rental being compared.
+type RentVsBuyInput struct {
+	HomePrice      float64 `json:"home_price"`
+	DownPaymentPct float64 `json:"down_payment_pct"`
+	MortgageRate   float64 `json:"mortgage_rate"`
+	Appreciation   float64 `json:"appreciation"` // percent per year
+	MonthlyRent    float64 `json:"monthly_rent"`
+	RentIncrease   float64 `json:"rent_increase"` // percent per year
+	Years          int     `json:"years"`
+	InflationRate  float64 `json:"inflation_rate"`
+}
+
+// RentVsBuyResult compares the net cost of buying and renting over the
+// period. Net cost is what was paid less what was built up: equity when
+// buying, investment growth on the down payment when renting. Payments are
+// discounted to today's dollars from the middle of the year they're made.
+type RentVsBuyResult struct {
+	Years             int     `json:"years"`
+	BuyMonthly        Money   `json:"buy_monthly"`
+	DownPayment       Money   `json:"down_payment"`
+	BuyTotalPaid      Dollars `json:"buy_total_paid"`
+	HomeValue         Dollars `json:"home_value"`
+	Equity            Dollars `json:"equity"`
+	BuyNetCost        Dollars `json:"buy_net_cost"`
+	RentStart         Money   `json:"rent_start"`
+	RentEnd           Dollars `json:"rent_end"`
+	RentTotal         Dollars `json:"rent_total"`
+	InvestmentReturns Dollars `json:"investment_returns"`
+	RentNetCost       Dollars `json:"rent_net_cost"`
+	BuyAdvantage      Dollars `json:"buy_advantage"` // rent net cost minus buy net cost
+	PriceToRent       float64 `json:"price_to_rent"`
+}
+
+// CompareRentVsBuy estimates the net cost of buying versus renting.
+func CompareRentVsBuy(in RentVsBuyInput) *RentVsBuyResult {
+	years := in.Years
+	if years <= 0 {
+		years = 5
+	}
+	inflation := in.InflationRate
+	n := float64(years)
+
+	downPayment := in.HomePrice * in.DownPaymentPct / 100
+	loan := in.HomePrice - downPayment
+	mortgage := amortizedPayment(loan, in.MortgageRate, RentVsBuyTermMonths)
+	buyMonthly := mortgage + in.HomePrice*RentVsBuyPropertyTaxRate/100/12 + RentVsBuyMonthlyInsure +
+		in.HomePrice*RentVsBuyMaintenanceRate/100/12
+	if in.DownPaymentPct < 20 {
+		buyMonthly += loan * RentVsBuyPMIRate / 100 / 12
+	}
+
+	var buyPaid, rentPaid dollarFlow
+	buyPaid.add(downPayment, inflation, 0)
+	rent := in.MonthlyRent
+	finalRent := rent
+	for y := 0; y < years; y++ {
+		mid := float64(y) + 0.5
+		buyPaid.add(buyMonthly*12, inflation, mid)
+		rentPaid.add(rent*12, inflation, mid)
+		finalRent = rent
+		rent *= 1 + in.RentIncrease/100
+	}
+
+	homeValue := in.HomePrice * math.Pow(1+in.Appreciation/100, n)
+	principalPaid := mortgage * 12 * n * RentVsBuyPrincipalShare
+	equity := downPayment + homeValue - in.HomePrice + principalPaid
+	investReturns := downPayment*math.Pow(1+RentVsBuyInvestReturn/100, n) - downPayment
+
+	result := &RentVsBuyResult{
+		Years:             years,
+		BuyMonthly:        NewMoney(buyMonthly),
+		DownPayment:       NewMoney(downPayment),
+		BuyTotalPaid:      buyPaid.dollars(),
+		HomeValue:         NewDollars(homeValue, inflation, n),
+		Equity:            NewDollars(equity, inflation, n),
+		RentStart:         NewMoney(in.MonthlyRent),
+		RentEnd:           NewDollars(finalRent, inflation, n-0.5),
+		RentTotal:         rentPaid.dollars(),
+		InvestmentReturns: NewDollars(investReturns, inflation, n),
+	}
+	result.BuyNetCost = result.BuyTotalPaid.Sub(result.Equity)
+	result.RentNetCost = result.RentTotal.Sub(result.InvestmentReturns)
+	result.BuyAdvantage = result.RentNetCost.Sub(result.BuyNetCost)
+	if in.MonthlyRent > 0 {
+		result.PriceToRent = in.HomePrice / (in.MonthlyRent * 12)
+	}
+	return result
+}
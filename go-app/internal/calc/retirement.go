@@ -0,0 +1,282 @@
+package calc
+
+import (
+	"math"
+	"time"
+)
+
+// 2024 contribution limits.
+const (
+	Limit401k             = 23000.0
+	Limit401kCatchUp      = 7500.0
+	LimitIRA              = 7000.0
+	LimitIRACatchUp       = 1000.0
+	LimitAnnualAdditions  = 69000.0 // employee + employer, excluding catch-up
+	CatchUpAge            = 50
+	DefaultWithdrawalRate = 4.0
+)
+
+// MatchTier is one tier of an employer match formula, e.g. 50% of the
+// employee's contributions on the first 6% of pay.
+type MatchTier struct {
+	MatchPercent float64 `json:"match_percent"`
+	UpToPercent  float64 `json:"up_to_percent"`
+}
+
+// Common employer match formulas.
+var (
+	MatchHalfUpTo6  = []MatchTier{{MatchPercent: 50, UpToPercent: 6}}
+	MatchSafeHarbor = []MatchTier{{MatchPercent: 100, UpToPercent: 3}, {MatchPercent: 50, UpToPercent: 2}}
+)
+
+// VestingSchedule describes when employer contributions become the employee's.
+// A cliff schedule vests 100% after CliffYears; a graded schedule vests evenly
+// over GradedYears. A zero schedule means immediate vesting.
+type VestingSchedule struct {
+	CliffYears  int `json:"cliff_years"`
+	GradedYears int `json:"graded_years"`
+}
+
+// RetirementInput holds the assumptions for a retirement projection.
+type RetirementInput struct {
+	CurrentAge      int             `json:"current_age"`
+	RetirementAge   int             `json:"retirement_age"`
+	Salary          float64         `json:"salary"`
+	SalaryGrowth    float64         `json:"salary_growth"`    // percent per year
+	CurrentBalance  float64         `json:"current_balance"`  // existing retirement savings
+	ContributionPct float64         `json:"contribution_pct"` // employee 401(k) deferral, percent of salary
+	IRAContribution float64         `json:"ira_contribution"` // annual IRA contribution
+	EmployerMatch   []MatchTier     `json:"employer_match"`
+	Vesting         VestingSchedule `json:"vesting"`
+	YearsOfService  int             `json:"years_of_service"` // service already credited toward vesting
+	ReturnRate      float64         `json:"return_rate"`      // nominal annual return, percent
+	InflationRate   float64         `json:"inflation_rate"`
+	WithdrawalRate  float64         `json:"withdrawal_rate"` // defaults to the 4% rule
+	SocialSecurity  float64         `json:"social_security"` // annual benefit in today's dollars from age 62 or retirement, whichever is later; 0 estimates it from the salary history
+	CurrentYear     int             `json:"current_year"`    // for the Social Security estimate; defaults to this year
+}
+
+// RetirementYear is one row of the year-by-year projection.
+type RetirementYear struct {
+	Age                  int   `json:"age"`
+	Salary               Money `json:"salary"`
+	EmployeeContribution Money `json:"employee_contribution"`
+	EmployerContribution Money `json:"employer_contribution"`
+	IRAContribution      Money `json:"ira_contribution"`
+	Balance              Money `json:"balance"`
+	RealBalance          Money `json:"real_balance"`
+}
+
+// RetirementProjection represents the projected savings at retirement.
+type RetirementProjection struct {
+	Years                 int              `json:"years"`
+	FinalSalary           Money            `json:"final_salary"`
+	BalanceNominal        Money            `json:"balance_nominal"`
+	BalanceReal           Money            `json:"balance_real"`
+	EmployeeContributions Money            `json:"employee_contributions"`
+	EmployerContributions Money            `json:"employer_contributions"`
+	IRAContributions      Money            `json:"ira_contributions"`
+	InvestmentGrowth      Money            `json:"investment_growth"`
+	VestedPercent         float64          `json:"vested_percent"`
+	ForfeitedMatch        Money            `json:"forfeited_match"`
+	LimitedYears          int              `json:"limited_years"` // years contributions were capped by IRS limits
+	WithdrawalRate        float64          `json:"withdrawal_rate"`
+	AnnualWithdrawal      Money            `json:"annual_withdrawal"`
+	AnnualWithdrawalReal  Money            `json:"annual_withdrawal_real"`
+	MonthlyWithdrawalReal Money            `json:"monthly_withdrawal_real"`
+	SocialSecurityAnnual  Money            `json:"social_security_annual"`
+	SocialSecurityClaim   int              `json:"social_security_claim,omitempty"` // age benefits start
+	SocialSecurityGap     int              `json:"social_security_gap"`             // years from retirement until benefits start
+	TotalIncomeReal       Money            `json:"total_income_real"`               // income from the retirement date, today's dollars; excludes Social Security during a gap
+	MonthlyIncomeReal     Money            `json:"monthly_income_real"`
+	IncomeAtClaimReal     Money            `json:"income_at_claim_real"` // withdrawals plus Social Security once it starts
+	Yearly                []RetirementYear `json:"yearly"`
+}
+
+// EmployerMatchAmount returns the employer match on a year's deferral given
+// the salary and the employee's contribution rate.
+func EmployerMatchAmount(salary, contributionPct float64, tiers []MatchTier) float64 {
+	var match float64
+	remaining := contributionPct
+	for _, t := range tiers {
+		if remaining <= 0 {
+			break
+		}
+		matched := math.Min(remaining, t.UpToPercent)
+		match += salary * matched / 100 * t.MatchPercent / 100
+		remaining -= matched
+	}
+	return match
+}
+
+// VestedPercent returns the share of employer contributions vested after the
+// given years of service.
+func (v VestingSchedule) VestedPercent(years int) float64 {
+	switch {
+	case v.CliffYears > 0:
+		if years >= v.CliffYears {
+			return 100
+		}
+		return 0
+	case v.GradedYears > 0:
+		return math.Min(100, float64(years)/float64(v.GradedYears)*100)
+	default:
+		return 100
+	}
+}
+
+// employeeLimits returns the 401(k) and IRA limits for a given age.
+func employeeLimits(age int) (limit401k, limitIRA float64) {
+	limit401k, limitIRA = Limit401k, LimitIRA
+	if age >= CatchUpAge {
+		limit401k += Limit401kCatchUp
+		limitIRA += LimitIRACatchUp
+	}
+	return limit401k, limitIRA
+}
+
+// ProjectRetirement projects retirement savings from now until retirement age.
+// Contributions are capped at the annual 401(k), IRA and total-additions limits
+// and are assumed to arrive evenly through the year (half a year of growth).
+func ProjectRetirement(in RetirementInput) *RetirementProjection {
+	years := in.RetirementAge - in.CurrentAge
+	if years < 0 {
+		years = 0
+	}
+	withdrawalRate := in.WithdrawalRate
+	if withdrawalRate <= 0 {
+		withdrawalRate = DefaultWithdrawalRate
+	}
+
+	r := in.ReturnRate / 100
+	midYear := math.Sqrt(1 + r)
+
+	employeeBalance := in.CurrentBalance
+	var employerBalance float64
+	var totalEmployee, totalEmployer, totalIRA float64
+	salary := in.Salary
+	result := &RetirementProjection{Years: years, WithdrawalRate: withdrawalRate}
+
+	for y := 0; y < years; y++ {
+		age := in.CurrentAge + y
+		limit401k, limitIRA := employeeLimits(age)
+
+		deferral := salary * in.ContributionPct / 100
+		ira := in.IRAContribution
+		capped := false
+		if deferral > limit401k {
+			deferral, capped = limit401k, true
+		}
+		if ira > limitIRA {
+			ira, capped = limitIRA, true
+		}
+
+		// Match follows the deferral actually made, then the total-additions cap
+		var match float64
+		if salary > 0 {
+			match = EmployerMatchAmount(salary, deferral/salary*100, in.EmployerMatch)
+		}
+		catchUp := math.Max(0, deferral-Limit401k)
+		if deferral-catchUp+match > LimitAnnualAdditions {
+			match, capped = math.Max(0, LimitAnnualAdditions-(deferral-catchUp)), true
+		}
+		if capped {
+			result.LimitedYears++
+		}
+
+		employeeBalance = employeeBalance*(1+r) + (deferral+ira)*midYear
+		employerBalance = employerBalance*(1+r) + match*midYear
+		totalEmployee += deferral
+		totalEmployer += match
+		totalIRA += ira
+
+		balance := employeeBalance + employerBalance
+		result.Yearly = append(result.Yearly, RetirementYear{
+			Age:                  age + 1,
+			Salary:               NewMoney(salary),
+			EmployeeContribution: NewMoney(deferral),
+			EmployerContribution: NewMoney(match),
+			IRAContribution:      NewMoney(ira),
+			Balance:              NewMoney(balance),
+			RealBalance:          NewMoney(RealValue(balance, in.InflationRate, float64(y+1))),
+		})
+
+		if y < years-1 {
+			salary *= 1 + in.SalaryGrowth/100
+		}
+	}
+
+	vested := in.Vesting.VestedPercent(in.YearsOfService + years)
+	forfeited := employerBalance * (1 - vested/100)
+	balance := employeeBalance + employerBalance - forfeited
+	realBalance := RealValue(balance, in.InflationRate, float64(years))
+
+	// Growth is whatever the balance holds beyond the contributions that
+	// stayed in it, so the parts add back up to the balance to the cent
+	result.FinalSalary = NewMoney(salary)
+	result.BalanceNominal = NewMoney(balance)
+	result.BalanceReal = NewMoney(realBalance)
+	result.EmployeeContributions = NewMoney(totalEmployee)
+	result.EmployerContributions = NewMoney(totalEmployer)
+	result.IRAContributions = NewMoney(totalIRA)
+	result.ForfeitedMatch = NewMoney(forfeited)
+	result.InvestmentGrowth = result.BalanceNominal - NewMoney(in.CurrentBalance) - result.EmployeeContributions -
+		result.IRAContributions - result.EmployerContributions.Mul(vested/100)
+	result.VestedPercent = vested
+	result.AnnualWithdrawal = result.BalanceNominal.Mul(withdrawalRate / 100)
+	result.AnnualWithdrawalReal = result.BalanceReal.Mul(withdrawalRate / 100)
+	result.MonthlyWithdrawalReal = result.AnnualWithdrawalReal.Div(12)
+
+	// Benefits can't start before 62, so an earlier retirement lives on
+	// withdrawals alone until then
+	socialSecurity := NewMoney(in.SocialSecurity)
+	claimAge := max(in.RetirementAge, EarliestClaimAge)
+	if socialSecurity == 0 && in.Salary > 0 {
+		var benefit float64
+		benefit, claimAge = retirementSocialSecurity(in)
+		socialSecurity = NewMoney(benefit)
+	}
+	result.SocialSecurityAnnual = socialSecurity
+	result.IncomeAtClaimReal = result.AnnualWithdrawalReal + socialSecurity
+	result.TotalIncomeReal = result.IncomeAtClaimReal
+	if socialSecurity > 0 {
+		result.SocialSecurityClaim = claimAge
+		result.SocialSecurityGap = max(0, claimAge-in.RetirementAge)
+		if result.SocialSecurityGap > 0 {
+			result.TotalIncomeReal = result.AnnualWithdrawalReal
+		}
+	}
+	result.MonthlyIncomeReal = result.TotalIncomeReal.Div(12)
+	return result
+}
+
+// retirementSocialSecurity estimates the annual benefit and claiming age from
+// a career at the projection's salary, growing at its rate, until retirement.
+// The benefit is claimed at the retirement age, within the 62 to 70 claiming
+// window, and returned in the projection's today's dollars.
+func retirementSocialSecurity(in RetirementInput) (float64, int) {
+	currentYear := in.CurrentYear
+	if currentYear == 0 {
+		currentYear = time.Now().Year()
+	}
+	birthYear := currentYear - in.CurrentAge
+	est := EstimateSocialSecurity(SocialSecurityInput{
+		BirthYear:     birthYear,
+		CurrentYear:   currentYear,
+		CurrentSalary: in.Salary,
+		SalaryGrowth:  in.SalaryGrowth,
+		StopWorkAge:   in.RetirementAge,
+		ClaimAge:      in.RetirementAge,
+	})
+
+	// The estimate is discounted by wage growth to eligibility at 62, after
+	// which cost-of-living adjustments hold its value. Restore the nominal
+	// benefit at 62 and deflate it by the projection's inflation instead.
+	benefit := est.AnnualBenefit.Float()
+	eligibilityYear := birthYear + EarliestClaimAge
+	if eligibilityYear > currentYear {
+		wageGrowth := wageIndex(eligibilityYear-2, DefaultWageIndexGrowth) / wageIndex(currentYear-2, DefaultWageIndexGrowth)
+		benefit = RealValue(benefit*wageGrowth, in.InflationRate, float64(eligibilityYear-currentYear))
+	}
+	return benefit, est.ClaimAge
+}
@@ -0,0 +1,189 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEmployerMatchAmount(t *testing.T) {
+	tests := []struct {
+		name            string
+		contributionPct float64
+		tiers           []MatchTier
+		want            float64
+	}{
+		{name: "half up to 6, full deferral", contributionPct: 10, tiers: MatchHalfUpTo6, want: 3000},
+		{name: "half up to 6, partial deferral", contributionPct: 4, tiers: MatchHalfUpTo6, want: 2000},
+		{name: "safe harbor", contributionPct: 5, tiers: MatchSafeHarbor, want: 4000},
+		{name: "no deferral", contributionPct: 0, tiers: MatchSafeHarbor, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EmployerMatchAmount(100000, tt.contributionPct, tt.tiers)
+			if got != tt.want {
+				t.Errorf("EmployerMatchAmount() = %.0f, want %.0f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVestingSchedule(t *testing.T) {
+	cliff := VestingSchedule{CliffYears: 3}
+	if cliff.VestedPercent(2) != 0 || cliff.VestedPercent(3) != 100 {
+		t.Error("cliff vesting should jump from 0% to 100% at 3 years")
+	}
+	graded := VestingSchedule{GradedYears: 5}
+	if graded.VestedPercent(2) != 40 || graded.VestedPercent(7) != 100 {
+		t.Error("graded vesting should be 20% per year up to 100%")
+	}
+	if (VestingSchedule{}).VestedPercent(0) != 100 {
+		t.Error("empty schedule should vest immediately")
+	}
+}
+
+func TestProjectRetirement(t *testing.T) {
+	result := ProjectRetirement(RetirementInput{
+		CurrentAge:      30,
+		RetirementAge:   65,
+		Salary:          80000,
+		SalaryGrowth:    3,
+		ContributionPct: 10,
+		EmployerMatch:   MatchHalfUpTo6,
+		ReturnRate:      7,
+		InflationRate:   3,
+	})
+
+	if result.Years != 35 || len(result.Yearly) != 35 {
+		t.Fatalf("expected 35 yearly rows, got %d", len(result.Yearly))
+	}
+	if result.BalanceReal >= result.BalanceNominal {
+		t.Error("real balance should be below nominal with positive inflation")
+	}
+	if result.AnnualWithdrawal != result.BalanceNominal.Mul(0.04) {
+		t.Errorf("expected 4%% withdrawal of balance, got %s", result.AnnualWithdrawal)
+	}
+
+	// First year: 10% of 80,000 deferral and 3% of salary match
+	first := result.Yearly[0]
+	if first.EmployeeContribution != NewMoney(8000) || first.EmployerContribution != NewMoney(2400) {
+		t.Errorf("expected 8000/2400 first-year contributions, got %s/%s",
+			first.EmployeeContribution, first.EmployerContribution)
+	}
+
+	total := result.EmployeeContributions + result.EmployerContributions + result.InvestmentGrowth
+	if total != result.BalanceNominal {
+		t.Errorf("contributions + growth %s don't reconcile with balance %s", total, result.BalanceNominal)
+	}
+}
+
+func TestProjectRetirement_ContributionLimits(t *testing.T) {
+	result := ProjectRetirement(RetirementInput{
+		CurrentAge:      48,
+		RetirementAge:   52,
+		Salary:          400000,
+		ContributionPct: 20,
+		IRAContribution: 10000,
+		ReturnRate:      0,
+	})
+
+	if result.LimitedYears != 4 {
+		t.Errorf("expected all 4 years to hit limits, got %d", result.LimitedYears)
+	}
+	// Ages 48-49 at the base limit, 50-51 with catch-up
+	if result.Yearly[0].EmployeeContribution != NewMoney(23000) || result.Yearly[2].EmployeeContribution != NewMoney(30500) {
+		t.Errorf("expected 23000 then 30500 deferrals, got %s and %s",
+			result.Yearly[0].EmployeeContribution, result.Yearly[2].EmployeeContribution)
+	}
+	if result.Yearly[0].IRAContribution != NewMoney(7000) || result.Yearly[2].IRAContribution != NewMoney(8000) {
+		t.Errorf("expected 7000 then 8000 IRA contributions, got %s and %s",
+			result.Yearly[0].IRAContribution, result.Yearly[2].IRAContribution)
+	}
+}
+
+func TestProjectRetirement_Vesting(t *testing.T) {
+	result := ProjectRetirement(RetirementInput{
+		CurrentAge:      30,
+		RetirementAge:   32,
+		Salary:          100000,
+		ContributionPct: 6,
+		EmployerMatch:   MatchHalfUpTo6,
+		Vesting:         VestingSchedule{CliffYears: 3},
+	})
+
+	if result.VestedPercent != 0 {
+		t.Errorf("expected 0%% vested before the cliff, got %.0f", result.VestedPercent)
+	}
+	if result.ForfeitedMatch != NewMoney(6000) {
+		t.Errorf("expected 6000 forfeited match, got %s", result.ForfeitedMatch)
+	}
+	if result.BalanceNominal != NewMoney(12000) {
+		t.Errorf("expected only employee money (12000), got %s", result.BalanceNominal)
+	}
+}
+
+func TestProjectRetirement_SocialSecurity(t *testing.T) {
+	ss := EstimateSocialSecurity(SocialSecurityInput{BirthYear: 1990, CurrentYear: 2024, CurrentSalary: 80000, StopWorkAge: 65})
+	result := ProjectRetirement(RetirementInput{
+		CurrentAge:      34,
+		RetirementAge:   65,
+		Salary:          80000,
+		ContributionPct: 10,
+		ReturnRate:      7,
+		InflationRate:   3,
+		SocialSecurity:  ss.AnnualBenefit.Float(),
+	})
+
+	benefit := ss.AnnualBenefit
+	if result.SocialSecurityAnnual != benefit {
+		t.Errorf("expected Social Security %s, got %s", ss.AnnualBenefit, result.SocialSecurityAnnual)
+	}
+	if result.TotalIncomeReal != result.AnnualWithdrawalReal+benefit {
+		t.Errorf("total income %s should be withdrawals %s plus Social Security %s",
+			result.TotalIncomeReal, result.AnnualWithdrawalReal, ss.AnnualBenefit)
+	}
+}
+
+func TestProjectRetirement_EstimatesSocialSecurity(t *testing.T) {
+	ss := EstimateSocialSecurity(SocialSecurityInput{
+		BirthYear:     1990,
+		CurrentYear:   2024,
+		CurrentSalary: 80000,
+		SalaryGrowth:  2,
+		StopWorkAge:   65,
+		ClaimAge:      65,
+	})
+	result := ProjectRetirement(RetirementInput{
+		CurrentAge:      34,
+		RetirementAge:   65,
+		Salary:          80000,
+		SalaryGrowth:    2,
+		ContributionPct: 10,
+		ReturnRate:      7,
+		InflationRate:   3,
+		CurrentYear:     2024,
+	})
+
+	// SSA's wage-indexed estimate is grown to 2052, the year this worker
+	// turns 62, then deflated at the projection's 3% inflation
+	wageGrowth := wageIndex(2050, DefaultWageIndexGrowth) / wageIndex(2022, DefaultWageIndexGrowth)
+	want := ss.AnnualBenefit.Mul(wageGrowth / math.Pow(1.03, 28))
+	if ss.AnnualBenefit <= 0 || result.SocialSecurityAnnual != want {
+		t.Errorf("expected the estimated benefit %s in today's dollars as %s, got %s", ss.AnnualBenefit, want, result.SocialSecurityAnnual)
+	}
+	if result.SocialSecurityClaim != 65 || result.SocialSecurityGap != 0 {
+		t.Errorf("expected a claim at the retirement age, got %d (%d year gap)", result.SocialSecurityClaim, result.SocialSecurityGap)
+	}
+
+	// An early retirement still can't claim before 62, so income until then
+	// is withdrawals alone
+	early := ProjectRetirement(RetirementInput{CurrentAge: 34, RetirementAge: 55, Salary: 80000, CurrentYear: 2024})
+	if early.SocialSecurityClaim != EarliestClaimAge || early.SocialSecurityAnnual <= 0 || early.SocialSecurityGap != 7 {
+		t.Errorf("expected a benefit claimed at %d after a 7 year gap, got %s at %d (%d year gap)",
+			EarliestClaimAge, early.SocialSecurityAnnual, early.SocialSecurityClaim, early.SocialSecurityGap)
+	}
+	if early.TotalIncomeReal != early.AnnualWithdrawalReal || early.IncomeAtClaimReal != early.AnnualWithdrawalReal+early.SocialSecurityAnnual {
+		t.Errorf("expected Social Security only in income from %d, got %s at retirement and %s at the claim",
+			EarliestClaimAge, early.TotalIncomeReal, early.IncomeAtClaimReal)
+	}
+}
@@ -0,0 +1,185 @@
+package calc
+
+import "math"
+
+// LongTermCapitalGainsRate is the flat rate applied to growth in the taxable
+// side account that holds a Traditional contributor's tax savings.
+const LongTermCapitalGainsRate = 0.15
+
+// RothInput holds the assumptions for a Traditional vs Roth comparison.
+type RothInput struct {
+	GrossAnnual           float64 `json:"gross_annual"`
+	HealthInsuranceAnnual float64 `json:"health_insurance_annual"`
+	StateTaxRate          float64 `json:"state_tax_rate"`
+	Contribution          float64 `json:"contribution"` // annual dollars going into the account
+	Years                 int     `json:"years"`
+	ReturnRate            float64 `json:"return_rate"`
+	RetirementIncome      float64 `json:"retirement_income"` // other taxable income in retirement (pension, Social Security)
+	RetirementStateRate   float64 `json:"retirement_state_rate"`
+	WithdrawalYears       int     `json:"withdrawal_years"`
+	RothSharePct          float64 `json:"roth_share_pct"` // Roth share of the split option
+
+	FilingStatus FilingStatus `json:"filing_status"` // now and in retirement; defaults to single
+}
+
+// RothOption is the projected outcome of one contribution mix.
+type RothOption struct {
+	Name              string  `json:"name"`
+	RothSharePct      float64 `json:"roth_share_pct"`
+	TaxSavingsToday   Money   `json:"tax_savings_today"`
+	TakeHomeAnnual    Money   `json:"take_home_annual"`
+	TraditionalValue  Money   `json:"traditional_value"`
+	RothValue         Money   `json:"roth_value"`
+	SideAccountValue  Money   `json:"side_account_value"` // invested tax savings, after capital gains tax
+	WithdrawalTaxRate float64 `json:"withdrawal_tax_rate"`
+	TaxOnWithdrawals  Money   `json:"tax_on_withdrawals"`
+	AfterTaxWealth    Money   `json:"after_tax_wealth"`
+}
+
+// RothComparison compares Traditional, Roth and split contributions.
+type RothComparison struct {
+	MarginalRateNow       float64      `json:"marginal_rate_now"`
+	FederalMarginalNow    float64      `json:"federal_marginal_now"`
+	RetirementRate        float64      `json:"retirement_rate"`
+	Contribution          Money        `json:"contribution"`
+	ContributionCapped    bool         `json:"contribution_capped"`    // asked for more than Limit401k
+	MonthlyTakeHomeDiff   Money        `json:"monthly_take_home_diff"` // Traditional minus Roth
+	Options               []RothOption `json:"options"`
+	Best                  string       `json:"best"`
+	AdvantageOverNextBest Money        `json:"advantage_over_next_best"`
+}
+
+// MarginalTaxRate returns the combined federal and state rate, as a percentage,
+// on the next dollar of wages after pre-tax deductions.
+func MarginalTaxRate(grossAnnual, retirement401kPercent, healthInsuranceAnnual, stateTaxRate float64, status FilingStatus) float64 {
+	agi := grossAnnual - grossAnnual*retirement401kPercent/100 - healthInsuranceAnnual
+	federal := status.MarginalRate(math.Max(0, agi-status.StandardDeduction()))
+	return math.Round((federal*100+stateTaxRate)*10) / 10
+}
+
+// withdrawalTaxRate returns the average federal and state rate on a year's
+// Traditional withdrawal stacked on top of other retirement income.
+func withdrawalTaxRate(withdrawal, otherIncome, stateRate float64, status FilingStatus) float64 {
+	if withdrawal <= 0 {
+		return 0
+	}
+	base := math.Max(0, otherIncome-status.StandardDeduction())
+	stacked := math.Max(0, otherIncome+withdrawal-status.StandardDeduction())
+	federal := federalIncomeTax(stacked, status) - federalIncomeTax(base, status)
+	return federal/withdrawal + stateRate/100
+}
+
+// futureValueAnnual returns the value of equal year-end contributions.
+func futureValueAnnual(contribution, ratePct float64, years int) float64 {
+	r := ratePct / 100
+	if r == 0 {
+		return contribution * float64(years)
+	}
+	return contribution * (math.Pow(1+r, float64(years)) - 1) / r
+}
+
+// CompareTraditionalRoth projects after-tax wealth for all-Traditional,
+// all-Roth and split contributions of the same annual amount. The Traditional
+// deduction's tax savings are invested in a taxable account so each option
+// costs the same out of today's take-home pay. Traditional and Roth deferrals
+// share one IRS limit, so the contribution is capped at Limit401k.
+func CompareTraditionalRoth(in RothInput) *RothComparison {
+	contribution := math.Min(math.Max(0, in.Contribution), Limit401k)
+	withdrawalYears := in.WithdrawalYears
+	if withdrawalYears <= 0 {
+		withdrawalYears = 25
+	}
+	splitShare := in.RothSharePct
+	if splitShare <= 0 || splitShare >= 100 {
+		splitShare = 50
+	}
+
+	taxes := func(retirement401kPercent float64) *TaxBreakdown {
+		return CalculateTaxBreakdown(TaxInput{
+			GrossAnnual:           in.GrossAnnual,
+			Retirement401kPercent: retirement401kPercent,
+			HealthInsuranceAnnual: in.HealthInsuranceAnnual,
+			StateTaxRate:          in.StateTaxRate,
+			FilingStatus:          in.FilingStatus,
+		})
+	}
+	roth0 := taxes(0)
+	fedMarginal := in.FilingStatus.MarginalRate(math.Max(0, roth0.AGI.Float()-in.FilingStatus.StandardDeduction()))
+
+	result := &RothComparison{
+		MarginalRateNow:    MarginalTaxRate(in.GrossAnnual, 0, in.HealthInsuranceAnnual, in.StateTaxRate, in.FilingStatus),
+		FederalMarginalNow: fedMarginal * 100,
+		Contribution:       NewMoney(contribution),
+		ContributionCapped: in.Contribution > Limit401k,
+	}
+
+	shares := []struct {
+		name string
+		roth float64
+	}{
+		{"Traditional", 0},
+		{"Roth", 100},
+		{"Split", splitShare},
+	}
+
+	var traditionalTakeHome, rothTakeHome Money
+	for _, s := range shares {
+		traditional := contribution * (100 - s.roth) / 100
+		roth := contribution - traditional
+
+		// Traditional dollars are pre-tax; Roth dollars come out of take-home pay
+		var pct float64
+		if in.GrossAnnual > 0 {
+			pct = traditional / in.GrossAnnual * 100
+		}
+		t := taxes(pct)
+		takeHome := t.NetAnnual.Float() - roth
+		savings := (roth0.FederalTax + roth0.StateTax - t.FederalTax - t.StateTax).Float()
+
+		tradValue := futureValueAnnual(traditional, in.ReturnRate, in.Years)
+		rothValue := futureValueAnnual(roth, in.ReturnRate, in.Years)
+		sideValue := futureValueAnnual(savings, in.ReturnRate, in.Years)
+		sideGain := sideValue - savings*float64(in.Years)
+		sideAfterTax := sideValue - math.Max(0, sideGain)*LongTermCapitalGainsRate
+
+		rate := withdrawalTaxRate(tradValue/float64(withdrawalYears), in.RetirementIncome, in.RetirementStateRate, in.FilingStatus)
+		taxOnWithdrawals := tradValue * rate
+		if s.roth == 0 {
+			result.RetirementRate = math.Round(rate*1000) / 10
+		}
+
+		option := RothOption{
+			Name:              s.name,
+			RothSharePct:      s.roth,
+			TaxSavingsToday:   NewMoney(savings),
+			TakeHomeAnnual:    NewMoney(takeHome),
+			TraditionalValue:  NewMoney(tradValue),
+			RothValue:         NewMoney(rothValue),
+			SideAccountValue:  NewMoney(sideAfterTax),
+			WithdrawalTaxRate: math.Round(rate*1000) / 10,
+			TaxOnWithdrawals:  NewMoney(taxOnWithdrawals),
+			AfterTaxWealth:    NewMoney(tradValue - taxOnWithdrawals + rothValue + sideAfterTax),
+		}
+		result.Options = append(result.Options, option)
+
+		switch s.roth {
+		case 0:
+			traditionalTakeHome = option.TakeHomeAnnual
+		case 100:
+			rothTakeHome = option.TakeHomeAnnual
+		}
+	}
+	result.MonthlyTakeHomeDiff = (traditionalTakeHome - rothTakeHome).Div(12)
+
+	best, next := -1, -1
+	for i, o := range result.Options {
+		if best == -1 || o.AfterTaxWealth > result.Options[best].AfterTaxWealth {
+			best, next = i, best
+		} else if next == -1 || o.AfterTaxWealth > result.Options[next].AfterTaxWealth {
+			next = i
+		}
+	}
+	result.Best = result.Options[best].Name
+	result.AdvantageOverNextBest = result.Options[best].AfterTaxWealth - result.Options[next].AfterTaxWealth
+	return result
+}
@@ -0,0 +1,114 @@
+package calc
+
+import "testing"
+
+func TestMarginalTaxRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		gross     float64
+		stateRate float64
+		status    FilingStatus
+		want      float64
+	}{
+		{name: "12% bracket", gross: 50000, stateRate: 0, want: 12},
+		{name: "22% bracket plus state", gross: 100000, stateRate: 5, want: 27},
+		{name: "below standard deduction", gross: 10000, stateRate: 0, want: 10},
+		{name: "married joint stays at 12%", gross: 100000, stateRate: 5, status: FilingMarriedJoint, want: 17},
+		{name: "head of household", gross: 80000, stateRate: 0, status: FilingHeadHousehold, want: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MarginalTaxRate(tt.gross, 0, 0, tt.stateRate, tt.status)
+			if got != tt.want {
+				t.Errorf("MarginalTaxRate() = %.1f, want %.1f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareTraditionalRoth(t *testing.T) {
+	// High earner now, modest retirement income: Traditional should win
+	result := CompareTraditionalRoth(RothInput{
+		GrossAnnual:      180000,
+		StateTaxRate:     5,
+		Contribution:     20000,
+		Years:            30,
+		ReturnRate:       7,
+		RetirementIncome: 20000,
+	})
+
+	if len(result.Options) != 3 {
+		t.Fatalf("expected 3 options, got %d", len(result.Options))
+	}
+	trad, roth := result.Options[0], result.Options[1]
+
+	if result.MarginalRateNow != 29 {
+		t.Errorf("expected 24%% federal + 5%% state marginal rate, got %.1f", result.MarginalRateNow)
+	}
+	if result.RetirementRate >= result.MarginalRateNow {
+		t.Errorf("expected lower rate in retirement, got %.1f", result.RetirementRate)
+	}
+	if trad.TaxSavingsToday != NewMoney(5800) {
+		t.Errorf("expected $20,000 * 29%% = 5800 tax savings, got %s", trad.TaxSavingsToday)
+	}
+	if trad.TakeHomeAnnual-roth.TakeHomeAnnual != trad.TaxSavingsToday {
+		t.Errorf("take-home difference %s should equal tax savings %s",
+			trad.TakeHomeAnnual-roth.TakeHomeAnnual, trad.TaxSavingsToday)
+	}
+	if roth.TaxSavingsToday != 0 || roth.TaxOnWithdrawals != 0 {
+		t.Error("Roth should have no deduction and no tax on withdrawals")
+	}
+	if result.Best != "Traditional" {
+		t.Errorf("expected Traditional to win, got %s", result.Best)
+	}
+}
+
+func TestCompareTraditionalRoth_LowBracketFavorsRoth(t *testing.T) {
+	// Low bracket now, large taxable income in retirement: Roth should win
+	result := CompareTraditionalRoth(RothInput{
+		GrossAnnual:      40000,
+		Contribution:     5000,
+		Years:            35,
+		ReturnRate:       7,
+		RetirementIncome: 120000,
+	})
+
+	if result.Best != "Roth" {
+		t.Errorf("expected Roth to win, got %s", result.Best)
+	}
+	if result.AdvantageOverNextBest <= 0 {
+		t.Error("expected a positive advantage for the best option")
+	}
+}
+
+func TestCompareTraditionalRoth_CapsContribution(t *testing.T) {
+	over := CompareTraditionalRoth(RothInput{GrossAnnual: 300000, Contribution: 40000, Years: 20, ReturnRate: 7})
+	atLimit := CompareTraditionalRoth(RothInput{GrossAnnual: 300000, Contribution: Limit401k, Years: 20, ReturnRate: 7})
+
+	if over.Contribution != NewMoney(Limit401k) || !over.ContributionCapped {
+		t.Errorf("expected the contribution capped at %.0f, got %s", Limit401k, over.Contribution)
+	}
+	if atLimit.ContributionCapped {
+		t.Error("a contribution at the limit shouldn't be reported as capped")
+	}
+	if over.Options[0].TaxSavingsToday != atLimit.Options[0].TaxSavingsToday ||
+		over.Options[0].TraditionalValue != atLimit.Options[0].TraditionalValue {
+		t.Errorf("expected no credit past the limit, got %+v vs %+v", over.Options[0], atLimit.Options[0])
+	}
+}
+
+func TestCompareTraditionalRoth_FilingStatus(t *testing.T) {
+	in := RothInput{GrossAnnual: 180000, StateTaxRate: 5, Contribution: 20000, Years: 30, ReturnRate: 7, RetirementIncome: 60000}
+	single := CompareTraditionalRoth(in)
+	in.FilingStatus = FilingMarriedJoint
+	joint := CompareTraditionalRoth(in)
+
+	// $150,800 taxable is in the joint 22% bracket rather than the single 24%
+	if joint.MarginalRateNow != 27 || single.MarginalRateNow != 29 {
+		t.Errorf("expected 27%% joint and 29%% single marginal rates, got %.1f and %.1f", joint.MarginalRateNow, single.MarginalRateNow)
+	}
+	if joint.RetirementRate >= single.RetirementRate {
+		t.Errorf("expected joint withdrawals taxed below single, got %.1f vs %.1f", joint.RetirementRate, single.RetirementRate)
+	}
+}
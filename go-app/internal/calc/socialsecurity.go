@@ -0,0 +1,286 @@
+package calc
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Social Security benefit formula constants.
+const (
+	EarliestClaimAge       = 62
+	LatestClaimAge         = 70
+	ComputationYears       = 35
+	DefaultWageIndexGrowth = 3.5 // projected growth of the national average wage index, percent
+	DefaultStartWorkAge    = 22
+
+	bendPoint1Base   = 180.0  // first bend point in the 1979 formula
+	bendPoint2Base   = 1085.0 // second bend point in the 1979 formula
+	bendPointAWIBase = 9779.44
+	wageBaseBase     = 60600.0 // 1994 wage base, indexed from the 1992 AWI
+	wageBaseAWIBase  = 22935.42
+	spousalShare     = 0.5
+)
+
+// averageWageIndex is the SSA national average wage index by year.
+var averageWageIndex = map[int]float64{
+	1980: 12513.46, 1981: 13773.10, 1982: 14531.34, 1983: 15239.24, 1984: 16135.07,
+	1985: 16822.51, 1986: 17321.82, 1987: 18426.51, 1988: 19334.04, 1989: 20099.55,
+	1990: 21027.98, 1991: 21811.60, 1992: 22935.42, 1993: 23132.67, 1994: 23753.53,
+	1995: 24705.66, 1996: 25913.90, 1997: 27426.00, 1998: 28861.44, 1999: 30469.84,
+	2000: 32154.82, 2001: 32921.92, 2002: 33252.09, 2003: 34064.95, 2004: 35648.55,
+	2005: 36952.94, 2006: 38651.41, 2007: 40405.48, 2008: 41334.97, 2009: 40711.61,
+	2010: 41673.83, 2011: 42979.61, 2012: 44321.67, 2013: 44888.16, 2014: 46481.52,
+	2015: 48098.63, 2016: 48642.15, 2017: 50321.89, 2018: 52145.80, 2019: 54099.99,
+	2020: 55628.60, 2021: 60575.07, 2022: 63795.13,
+}
+
+const (
+	firstAWIYear = 1980
+	lastAWIYear  = 2022
+)
+
+// YearlyEarnings is one year of Social Security covered earnings.
+type YearlyEarnings struct {
+	Year   int     `json:"year"`
+	Amount float64 `json:"amount"`
+}
+
+// SocialSecurityInput holds a worker's earnings record and claiming plan.
+// When Earnings is empty a history is generated from CurrentSalary, assuming
+// past pay tracked the national average wage and future pay grows at
+// SalaryGrowth until StopWorkAge.
+type SocialSecurityInput struct {
+	BirthYear     int                  `json:"birth_year"`
+	CurrentYear   int                  `json:"current_year"` // defaults to this year
+	Earnings      []YearlyEarnings     `json:"earnings"`
+	CurrentSalary float64              `json:"current_salary"`
+	SalaryGrowth  float64              `json:"salary_growth"`
+	StartWorkAge  int                  `json:"start_work_age"`
+	StopWorkAge   int                  `json:"stop_work_age"` // defaults to the claiming age
+	ClaimAge      int                  `json:"claim_age"`     // 62-70, defaults to 67
+	WageGrowth    float64              `json:"wage_growth"`   // projected wage index growth, percent
+	Spouse        *SocialSecurityInput `json:"spouse,omitempty"`
+}
+
+// SocialSecurityClaim is the benefit for claiming at a given age.
+type SocialSecurityClaim struct {
+	Age           int     `json:"age"`
+	AdjustmentPct float64 `json:"adjustment_pct"` // percent of PIA
+	Monthly       Money   `json:"monthly"`
+	Annual        Money   `json:"annual"`
+}
+
+// SocialSecurityEstimate is a worker's estimated benefit. Dollar amounts are
+// in today's dollars, discounted by projected wage growth the way SSA's quick
+// calculator reports them.
+type SocialSecurityEstimate struct {
+	YearsOfEarnings   int                   `json:"years_of_earnings"`
+	AIME              Money                 `json:"aime"`
+	BendPoints        [2]Money              `json:"bend_points"`
+	PIA               float64               `json:"pia"`
+	FullRetirementAge float64               `json:"full_retirement_age"`
+	ClaimAge          int                   `json:"claim_age"`
+	AdjustmentPct     float64               `json:"adjustment_pct"`
+	MonthlyBenefit    Money                 `json:"monthly_benefit"`
+	AnnualBenefit     Money                 `json:"annual_benefit"`
+	Claims            []SocialSecurityClaim `json:"claims"`
+
+	// Spousal fields are set when a spouse is included.
+	SpouseOwnMonthly     Money `json:"spouse_own_monthly,omitempty"`
+	SpousalMonthly       Money `json:"spousal_monthly,omitempty"` // excess spousal benefit on top of their own
+	SpouseMonthlyBenefit Money `json:"spouse_monthly_benefit,omitempty"`
+	HouseholdMonthly     Money `json:"household_monthly"`
+	HouseholdAnnual      Money `json:"household_annual"`
+}
+
+// wageIndex returns the average wage index for a year, projecting beyond the
+// published table at the given growth rate.
+func wageIndex(year int, growthPct float64) float64 {
+	switch {
+	case year < firstAWIYear:
+		return averageWageIndex[firstAWIYear]
+	case year > lastAWIYear:
+		return averageWageIndex[lastAWIYear] * math.Pow(1+growthPct/100, float64(year-lastAWIYear))
+	default:
+		return averageWageIndex[year]
+	}
+}
+
+// taxableMaximum returns the Social Security wage base for a year.
+func taxableMaximum(year int, growthPct float64) float64 {
+	base := wageBaseBase * wageIndex(year-2, growthPct) / wageBaseAWIBase
+	return math.Round(base/300) * 300
+}
+
+// FullRetirementAge returns the full retirement age in months for a birth year.
+func FullRetirementAge(birthYear int) int {
+	switch {
+	case birthYear <= 1954:
+		return 66 * 12
+	case birthYear >= 1960:
+		return 67 * 12
+	default:
+		return 66*12 + (birthYear-1954)*2
+	}
+}
+
+// claimAdjustment returns the share of PIA paid when claiming monthsFromFRA
+// months before (negative) or after full retirement age.
+func claimAdjustment(monthsFromFRA int) float64 {
+	if monthsFromFRA >= 0 {
+		return 1 + float64(monthsFromFRA)*2.0/3/100
+	}
+	early := -monthsFromFRA
+	first := math.Min(float64(early), 36)
+	rest := math.Max(0, float64(early-36))
+	return 1 - first*5.0/9/100 - rest*5.0/12/100
+}
+
+// spousalAdjustment returns the share of the spousal benefit paid when
+// claiming early. Spousal benefits earn no delayed retirement credits.
+func spousalAdjustment(monthsFromFRA int) float64 {
+	if monthsFromFRA >= 0 {
+		return 1
+	}
+	early := -monthsFromFRA
+	first := math.Min(float64(early), 36)
+	rest := math.Max(0, float64(early-36))
+	return 1 - first*25.0/36/100 - rest*5.0/12/100
+}
+
+// PrimaryInsuranceAmount applies the bend-point formula to AIME, rounding
+// down to the dime.
+func PrimaryInsuranceAmount(aime float64, bend1, bend2 float64) float64 {
+	pia := 0.9 * math.Min(aime, bend1)
+	if aime > bend1 {
+		pia += 0.32 * (math.Min(aime, bend2) - bend1)
+	}
+	if aime > bend2 {
+		pia += 0.15 * (aime - bend2)
+	}
+	return math.Floor(pia*10) / 10
+}
+
+// earningsHistory returns the input's earnings or generates one from salary.
+func earningsHistory(in SocialSecurityInput, claimAge int, growth float64) []YearlyEarnings {
+	if len(in.Earnings) > 0 {
+		return in.Earnings
+	}
+	startAge := in.StartWorkAge
+	if startAge <= 0 {
+		startAge = DefaultStartWorkAge
+	}
+	stopAge := in.StopWorkAge
+	if stopAge <= 0 {
+		stopAge = claimAge
+	}
+
+	var history []YearlyEarnings
+	for year := in.BirthYear + startAge; year < in.BirthYear+stopAge; year++ {
+		var amount float64
+		if year <= in.CurrentYear {
+			amount = in.CurrentSalary * wageIndex(year, growth) / wageIndex(in.CurrentYear, growth)
+		} else {
+			amount = in.CurrentSalary * math.Pow(1+in.SalaryGrowth/100, float64(year-in.CurrentYear))
+		}
+		history = append(history, YearlyEarnings{Year: year, Amount: amount})
+	}
+	return history
+}
+
+// EstimateSocialSecurity estimates retirement benefits from an earnings record:
+// earnings are capped at each year's wage base, indexed to the year the worker
+// turns 60, and the highest 35 years are averaged into AIME.
+func EstimateSocialSecurity(in SocialSecurityInput) *SocialSecurityEstimate {
+	if in.CurrentYear == 0 {
+		in.CurrentYear = time.Now().Year()
+	}
+	growth := in.WageGrowth
+	if growth <= 0 {
+		growth = DefaultWageIndexGrowth
+	}
+	claimAge := in.ClaimAge
+	if claimAge == 0 {
+		claimAge = 67
+	}
+	claimAge = max(EarliestClaimAge, min(LatestClaimAge, claimAge))
+
+	indexYear := in.BirthYear + 60
+	eligibilityYear := in.BirthYear + EarliestClaimAge
+	indexAWI := wageIndex(indexYear, growth)
+
+	var indexed []float64
+	for _, e := range earningsHistory(in, claimAge, growth) {
+		if e.Amount <= 0 {
+			continue
+		}
+		amount := math.Min(e.Amount, taxableMaximum(e.Year, growth))
+		if e.Year < indexYear {
+			amount *= indexAWI / wageIndex(e.Year, growth)
+		}
+		indexed = append(indexed, amount)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(indexed)))
+	var total float64
+	for i := 0; i < len(indexed) && i < ComputationYears; i++ {
+		total += indexed[i]
+	}
+	aime := math.Floor(total / (ComputationYears * 12))
+
+	awi := wageIndex(eligibilityYear-2, growth)
+	bend1 := math.Round(bendPoint1Base * awi / bendPointAWIBase)
+	bend2 := math.Round(bendPoint2Base * awi / bendPointAWIBase)
+	pia := PrimaryInsuranceAmount(aime, bend1, bend2)
+
+	// Express future benefits in today's wage-indexed dollars
+	deflator := 1.0
+	if eligibilityYear > in.CurrentYear {
+		deflator = wageIndex(in.CurrentYear-2, growth) / awi
+	}
+
+	fra := FullRetirementAge(in.BirthYear)
+	result := &SocialSecurityEstimate{
+		YearsOfEarnings:   len(indexed),
+		AIME:              NewMoney(aime * deflator),
+		BendPoints:        [2]Money{NewMoney(bend1 * deflator), NewMoney(bend2 * deflator)},
+		PIA:               math.Floor(pia*deflator*10) / 10,
+		FullRetirementAge: float64(fra) / 12,
+		ClaimAge:          claimAge,
+	}
+	for age := EarliestClaimAge; age <= LatestClaimAge; age++ {
+		adj := claimAdjustment(age*12 - fra)
+		monthly := math.Floor(pia * adj * deflator)
+		claim := SocialSecurityClaim{
+			Age:           age,
+			AdjustmentPct: math.Round(adj*1000) / 10,
+			Monthly:       NewMoney(monthly),
+			Annual:        NewMoney(monthly * 12),
+		}
+		result.Claims = append(result.Claims, claim)
+		if age == claimAge {
+			result.AdjustmentPct = claim.AdjustmentPct
+			result.MonthlyBenefit = claim.Monthly
+			result.AnnualBenefit = claim.Annual
+		}
+	}
+	result.HouseholdMonthly = result.MonthlyBenefit
+
+	if in.Spouse != nil {
+		spouseIn := *in.Spouse
+		if spouseIn.CurrentYear == 0 {
+			spouseIn.CurrentYear = in.CurrentYear
+		}
+		spouse := EstimateSocialSecurity(spouseIn)
+		spouseFRA := FullRetirementAge(spouseIn.BirthYear)
+		excess := math.Max(0, result.PIA*spousalShare-spouse.PIA)
+		spousal := math.Floor(excess * spousalAdjustment(spouse.ClaimAge*12-spouseFRA))
+
+		result.SpouseOwnMonthly = spouse.MonthlyBenefit
+		result.SpousalMonthly = NewMoney(spousal)
+		result.SpouseMonthlyBenefit = spouse.MonthlyBenefit + result.SpousalMonthly
+		result.HouseholdMonthly += result.SpouseMonthlyBenefit
+	}
+	result.HouseholdAnnual = result.HouseholdMonthly * 12
+	return result
+}
@@ -0,0 +1,90 @@
+package calc
+
+import "testing"
+
+func TestPrimaryInsuranceAmount(t *testing.T) {
+	// 90% of 1,174 + 32% of (5,000 - 1,174) = 2,280.92, rounded down to the dime
+	if got := PrimaryInsuranceAmount(5000, 1174, 7078); got != 2280.9 {
+		t.Errorf("expected PIA 2280.90, got %.2f", got)
+	}
+	// 90% + 32% of the full middle band + 15% above
+	if got := PrimaryInsuranceAmount(8000, 1174, 7078); got != 3084.1 {
+		t.Errorf("expected PIA 3084.10, got %.2f", got)
+	}
+}
+
+func TestFullRetirementAge(t *testing.T) {
+	tests := []struct {
+		birthYear int
+		want      int
+	}{
+		{1950, 66 * 12},
+		{1957, 66*12 + 6},
+		{1962, 67 * 12},
+	}
+	for _, tt := range tests {
+		if got := FullRetirementAge(tt.birthYear); got != tt.want {
+			t.Errorf("FullRetirementAge(%d) = %d months, want %d", tt.birthYear, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateSocialSecurity(t *testing.T) {
+	// Earn the national average wage from 22 to 56; each year indexes to the
+	// 2022 AWI of 63,795.13, so AIME = 5,316
+	var earnings []YearlyEarnings
+	for year := 1984; year <= 2018; year++ {
+		earnings = append(earnings, YearlyEarnings{Year: year, Amount: averageWageIndex[year]})
+	}
+	result := EstimateSocialSecurity(SocialSecurityInput{
+		BirthYear:   1962,
+		CurrentYear: 2024,
+		Earnings:    earnings,
+		Spouse:      &SocialSecurityInput{BirthYear: 1962, ClaimAge: 62},
+	})
+
+	if result.BendPoints != [2]Money{NewMoney(1174), NewMoney(7078)} {
+		t.Errorf("expected 2024 bend points 1174/7078, got %v", result.BendPoints)
+	}
+	if result.YearsOfEarnings != 35 || result.AIME != NewMoney(5316) {
+		t.Errorf("expected 35 years and AIME 5316, got %d years, AIME %s", result.YearsOfEarnings, result.AIME)
+	}
+	if result.PIA != 2382 {
+		t.Errorf("expected PIA 2382.00, got %.2f", result.PIA)
+	}
+	if result.ClaimAge != 67 || result.MonthlyBenefit != NewMoney(2382) {
+		t.Errorf("expected $2382/mo at 67 by default, got $%s at %d", result.MonthlyBenefit, result.ClaimAge)
+	}
+
+	first, last := result.Claims[0], result.Claims[len(result.Claims)-1]
+	if first.Age != 62 || first.AdjustmentPct != 70 {
+		t.Errorf("expected 70%% of PIA at 62, got %.1f%% at %d", first.AdjustmentPct, first.Age)
+	}
+	if last.Age != 70 || last.AdjustmentPct != 124 {
+		t.Errorf("expected 124%% of PIA at 70, got %.1f%% at %d", last.AdjustmentPct, last.Age)
+	}
+
+	// Spouse with no record claims at 62: 50% of 2,382 reduced by 35% = 774.15
+	if result.SpouseOwnMonthly != 0 || result.SpousalMonthly != NewMoney(774) {
+		t.Errorf("expected $0 own and $774 spousal, got $%s and $%s", result.SpouseOwnMonthly, result.SpousalMonthly)
+	}
+	if result.HouseholdMonthly != NewMoney(2382+774) {
+		t.Errorf("expected household $%d/mo, got $%s", 2382+774, result.HouseholdMonthly)
+	}
+}
+
+func TestEstimateSocialSecurity_GeneratedHistory(t *testing.T) {
+	low := EstimateSocialSecurity(SocialSecurityInput{BirthYear: 1990, CurrentYear: 2024, CurrentSalary: 40000, SalaryGrowth: 3})
+	high := EstimateSocialSecurity(SocialSecurityInput{BirthYear: 1990, CurrentYear: 2024, CurrentSalary: 400000, SalaryGrowth: 3})
+
+	if low.YearsOfEarnings != 67-22 {
+		t.Errorf("expected earnings from 22 until claiming at 67, got %d years", low.YearsOfEarnings)
+	}
+	if low.MonthlyBenefit <= 0 || high.MonthlyBenefit <= low.MonthlyBenefit {
+		t.Errorf("expected higher earners to get more, got $%s vs $%s", high.MonthlyBenefit, low.MonthlyBenefit)
+	}
+	// Benefits replace a smaller share of higher, wage-base-capped earnings
+	if high.AnnualBenefit.Float()/400000 >= low.AnnualBenefit.Float()/40000 {
+		t.Error("expected a lower replacement rate for the higher earner")
+	}
+}
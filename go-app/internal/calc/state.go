@@ -0,0 +1,345 @@
+package calc
+
+import "math"
+
+// stateRules holds a graduated state's 2024 brackets and deduction by filing
+// status. The deduction is the state's standard deduction plus personal
+// exemptions for the filers, at their full amounts; income-based phase-outs
+// aren't modeled. States that start from federal taxable income use the
+// federal standard deduction.
+type stateRules struct {
+	brackets  map[FilingStatus][]bracket
+	deduction map[FilingStatus]float64
+}
+
+// perStatus maps the single, married-joint and head-of-household values.
+func perStatus[T any](single, joint, head T) map[FilingStatus]T {
+	return map[FilingStatus]T{FilingSingle: single, FilingMarriedJoint: joint, FilingHeadHousehold: head}
+}
+
+// scaleBrackets multiplies every bracket threshold by factor, for states
+// whose joint or head-of-household brackets are a multiple of single.
+func scaleBrackets(brackets []bracket, factor float64) []bracket {
+	scaled := make([]bracket, len(brackets))
+	for i, b := range brackets {
+		scaled[i] = bracket{b.Min * factor, b.Max * factor, b.Rate}
+		if b.Max == math.MaxFloat64 {
+			scaled[i].Max = math.MaxFloat64
+		}
+	}
+	return scaled
+}
+
+// federalDeductions is the 2024 federal standard deduction by status.
+var federalDeductions = perStatus(StandardDeduction, 29200.0, 21900.0)
+
+var (
+	alSingle = []bracket{{0, 500, 0.02}, {500, 3000, 0.04}, {3000, math.MaxFloat64, 0.05}}
+	arAll    = []bracket{{0, 5500, 0}, {5500, 10900, 0.02}, {10900, 15600, 0.03}, {15600, 25700, 0.034}, {25700, math.MaxFloat64, 0.039}}
+	caSingle = []bracket{
+		{0, 10756, 0.01},
+		{10756, 25499, 0.02},
+		{25499, 40245, 0.04},
+		{40245, 55866, 0.06},
+		{55866, 70606, 0.08},
+		{70606, 360659, 0.093},
+		{360659, 432787, 0.103},
+		{432787, 721314, 0.113},
+		{721314, 1000000, 0.123},
+		{1000000, math.MaxFloat64, 0.133}, // mental health services surcharge
+	}
+	// The 1% surcharge starts at $1 million for every status
+	caJoint = []bracket{
+		{0, 21512, 0.01},
+		{21512, 50998, 0.02},
+		{50998, 80490, 0.04},
+		{80490, 111732, 0.06},
+		{111732, 141212, 0.08},
+		{141212, 721318, 0.093},
+		{721318, 865574, 0.103},
+		{865574, 1000000, 0.113},
+		{1000000, 1442628, 0.123},
+		{1442628, math.MaxFloat64, 0.133},
+	}
+	caHead = []bracket{
+		{0, 21527, 0.01},
+		{21527, 51000, 0.02},
+		{51000, 65744, 0.04},
+		{65744, 81364, 0.06},
+		{81364, 96107, 0.08},
+		{96107, 490493, 0.093},
+		{490493, 588593, 0.103},
+		{588593, 980987, 0.113},
+		{980987, 1000000, 0.123},
+		{1000000, math.MaxFloat64, 0.133},
+	}
+	ctSingle = []bracket{
+		{0, 10000, 0.02},
+		{10000, 50000, 0.045},
+		{50000, 100000, 0.055},
+		{100000, 200000, 0.06},
+		{200000, 250000, 0.065},
+		{250000, 500000, 0.069},
+		{500000, math.MaxFloat64, 0.0699},
+	}
+	dcAll = []bracket{
+		{0, 10000, 0.04},
+		{10000, 40000, 0.06},
+		{40000, 60000, 0.065},
+		{60000, 250000, 0.085},
+		{250000, 500000, 0.0925},
+		{500000, 1000000, 0.0975},
+		{1000000, math.MaxFloat64, 0.1075},
+	}
+	deAll = []bracket{
+		{0, 2000, 0},
+		{2000, 5000, 0.022},
+		{5000, 10000, 0.039},
+		{10000, 20000, 0.048},
+		{20000, 25000, 0.052},
+		{25000, 60000, 0.0555},
+		{60000, math.MaxFloat64, 0.066},
+	}
+	hiSingle = []bracket{
+		{0, 2400, 0.014},
+		{2400, 4800, 0.032},
+		{4800, 9600, 0.055},
+		{9600, 14400, 0.064},
+		{14400, 19200, 0.068},
+		{19200, 24000, 0.072},
+		{24000, 36000, 0.076},
+		{36000, 48000, 0.0825},
+		{48000, 150000, 0.09},
+		{150000, 175000, 0.10},
+		{175000, math.MaxFloat64, 0.11},
+	}
+	iaSingle = []bracket{{0, 6210, 0.044}, {6210, 31050, 0.0482}, {31050, math.MaxFloat64, 0.057}}
+	ksSingle = []bracket{{0, 23000, 0.052}, {23000, math.MaxFloat64, 0.0558}}
+	laSingle = []bracket{{0, 12500, 0.0185}, {12500, 50000, 0.035}, {50000, math.MaxFloat64, 0.0425}}
+	// The 4% surtax threshold is the same for every status
+	maAll    = []bracket{{0, 1053750, 0.05}, {1053750, math.MaxFloat64, 0.09}}
+	mdSingle = []bracket{
+		{0, 1000, 0.02},
+		{1000, 2000, 0.03},
+		{2000, 3000, 0.04},
+		{3000, 100000, 0.0475},
+		{100000, 125000, 0.05},
+		{125000, 150000, 0.0525},
+		{150000, 250000, 0.055},
+		{250000, math.MaxFloat64, 0.0575},
+	}
+	mdJoint = []bracket{
+		{0, 1000, 0.02},
+		{1000, 2000, 0.03},
+		{2000, 3000, 0.04},
+		{3000, 150000, 0.0475},
+		{150000, 175000, 0.05},
+		{175000, 225000, 0.0525},
+		{225000, 300000, 0.055},
+		{300000, math.MaxFloat64, 0.0575},
+	}
+	moAll = []bracket{
+		{0, 1273, 0},
+		{1273, 2546, 0.02},
+		{2546, 3819, 0.025},
+		{3819, 5092, 0.03},
+		{5092, 6365, 0.035},
+		{6365, 7638, 0.04},
+		{7638, 8911, 0.045},
+		{8911, math.MaxFloat64, 0.048},
+	}
+	msAll    = []bracket{{0, 10000, 0}, {10000, math.MaxFloat64, 0.047}}
+	mtSingle = []bracket{{0, 20500, 0.047}, {20500, math.MaxFloat64, 0.059}}
+	njSingle = []bracket{
+		{0, 20000, 0.014},
+		{20000, 35000, 0.0175},
+		{35000, 40000, 0.035},
+		{40000, 75000, 0.05525},
+		{75000, 500000, 0.0637},
+		{500000, 1000000, 0.0897},
+		{1000000, math.MaxFloat64, 0.1075},
+	}
+	njJoint = []bracket{
+		{0, 20000, 0.014},
+		{20000, 50000, 0.0175},
+		{50000, 70000, 0.0245},
+		{70000, 80000, 0.035},
+		{80000, 150000, 0.05525},
+		{150000, 500000, 0.0637},
+		{500000, 1000000, 0.0897},
+		{1000000, math.MaxFloat64, 0.1075},
+	}
+	nmSingle = []bracket{{0, 5500, 0.017}, {5500, 11000, 0.032}, {11000, 16000, 0.047}, {16000, 210000, 0.049}, {210000, math.MaxFloat64, 0.059}}
+	nmJoint  = []bracket{{0, 8000, 0.017}, {8000, 16000, 0.032}, {16000, 24000, 0.047}, {24000, 315000, 0.049}, {315000, math.MaxFloat64, 0.059}}
+	nySingle = []bracket{
+		{0, 8500, 0.04},
+		{8500, 11700, 0.045},
+		{11700, 13900, 0.0525},
+		{13900, 80650, 0.055},
+		{80650, 215400, 0.06},
+		{215400, 1077550, 0.0685},
+		{1077550, 5000000, 0.0965},
+		{5000000, 25000000, 0.103},
+		{25000000, math.MaxFloat64, 0.109},
+	}
+	nyJoint = []bracket{
+		{0, 17150, 0.04},
+		{17150, 23600, 0.045},
+		{23600, 27900, 0.0525},
+		{27900, 161550, 0.055},
+		{161550, 323200, 0.06},
+		{323200, 2155350, 0.0685},
+		{2155350, 5000000, 0.0965},
+		{5000000, 25000000, 0.103},
+		{25000000, math.MaxFloat64, 0.109},
+	}
+	nyHead = []bracket{
+		{0, 12800, 0.04},
+		{12800, 17650, 0.045},
+		{17650, 20900, 0.0525},
+		{20900, 107650, 0.055},
+		{107650, 269300, 0.06},
+		{269300, 1616450, 0.0685},
+		{1616450, 5000000, 0.0965},
+		{5000000, 25000000, 0.103},
+		{25000000, math.MaxFloat64, 0.109},
+	}
+	ohAll    = []bracket{{0, 26050, 0}, {26050, 100000, 0.0275}, {100000, math.MaxFloat64, 0.035}}
+	okSingle = []bracket{
+		{0, 1000, 0.0025},
+		{1000, 2500, 0.0075},
+		{2500, 3750, 0.0175},
+		{3750, 4900, 0.0275},
+		{4900, 7200, 0.0375},
+		{7200, math.MaxFloat64, 0.0475},
+	}
+	orSingle = []bracket{{0, 4300, 0.0475}, {4300, 10750, 0.0675}, {10750, 125000, 0.0875}, {125000, math.MaxFloat64, 0.099}}
+	riAll    = []bracket{{0, 77450, 0.0375}, {77450, 176050, 0.0475}, {176050, math.MaxFloat64, 0.0599}}
+	scAll    = []bracket{{0, 3460, 0}, {3460, 17330, 0.03}, {17330, math.MaxFloat64, 0.062}}
+	vaAll    = []bracket{{0, 3000, 0.02}, {3000, 5000, 0.03}, {5000, 17000, 0.05}, {17000, math.MaxFloat64, 0.0575}}
+	wiSingle = []bracket{{0, 14320, 0.035}, {14320, 28640, 0.044}, {28640, 315310, 0.053}, {315310, math.MaxFloat64, 0.0765}}
+	wvAll    = []bracket{
+		{0, 10000, 0.0236},
+		{10000, 25000, 0.0315},
+		{25000, 40000, 0.0354},
+		{40000, 60000, 0.0472},
+		{60000, math.MaxFloat64, 0.0512},
+	}
+)
+
+// stateTable holds the states with a graduated income tax, keyed by postal
+// code. States with a flat tax aren't listed and are taxed at
+// TaxInput.StateTaxRate.
+var stateTable = map[string]stateRules{
+	"AL": {perStatus(alSingle, scaleBrackets(alSingle, 2), alSingle), perStatus(4500.0, 11500, 8200)},
+	"AR": {perStatus(arAll, arAll, arAll), perStatus(2340.0, 4680, 2340)},
+	"CA": {perStatus(caSingle, caJoint, caHead), perStatus(5540.0, 11080, 11080)},
+	"CT": {perStatus(ctSingle, scaleBrackets(ctSingle, 2), scaleBrackets(ctSingle, 1.6)), perStatus(0.0, 0, 0)},
+	"DC": {perStatus(dcAll, dcAll, dcAll), federalDeductions},
+	"DE": {perStatus(deAll, deAll, deAll), perStatus(3250.0, 6500, 3250)},
+	"HI": {perStatus(hiSingle, scaleBrackets(hiSingle, 2), scaleBrackets(hiSingle, 1.5)), perStatus(3344.0, 6688, 4356)},
+	"IA": {perStatus(iaSingle, scaleBrackets(iaSingle, 2), iaSingle), federalDeductions},
+	"KS": {perStatus(ksSingle, scaleBrackets(ksSingle, 2), ksSingle), perStatus(12765.0, 26560, 15340)},
+	"LA": {perStatus(laSingle, scaleBrackets(laSingle, 2), laSingle), perStatus(4500.0, 9000, 9000)},
+	"MA": {perStatus(maAll, maAll, maAll), perStatus(4400.0, 8800, 6800)},
+	"MD": {perStatus(mdSingle, mdJoint, mdJoint), perStatus(5750.0, 11550, 8350)},
+	"ME": {
+		perStatus(
+			[]bracket{{0, 26050, 0.058}, {26050, 61600, 0.0675}, {61600, math.MaxFloat64, 0.0715}},
+			[]bracket{{0, 52100, 0.058}, {52100, 123250, 0.0675}, {123250, math.MaxFloat64, 0.0715}},
+			[]bracket{{0, 39050, 0.058}, {39050, 92450, 0.0675}, {92450, math.MaxFloat64, 0.0715}},
+		),
+		perStatus(19600.0, 39200, 26900),
+	},
+	"MN": {
+		perStatus(
+			[]bracket{{0, 31690, 0.0535}, {31690, 104090, 0.068}, {104090, 193240, 0.0785}, {193240, math.MaxFloat64, 0.0985}},
+			[]bracket{{0, 46330, 0.0535}, {46330, 184040, 0.068}, {184040, 321450, 0.0785}, {321450, math.MaxFloat64, 0.0985}},
+			[]bracket{{0, 39010, 0.0535}, {39010, 156760, 0.068}, {156760, 256880, 0.0785}, {256880, math.MaxFloat64, 0.0985}},
+		),
+		perStatus(14575.0, 29150, 21900),
+	},
+	"MO": {perStatus(moAll, moAll, moAll), federalDeductions},
+	"MS": {perStatus(msAll, msAll, msAll), perStatus(8300.0, 16600, 11400)},
+	"MT": {perStatus(mtSingle, scaleBrackets(mtSingle, 2), scaleBrackets(mtSingle, 1.5)), federalDeductions},
+	"ND": {
+		perStatus(
+			[]bracket{{0, 47150, 0}, {47150, 238200, 0.0195}, {238200, math.MaxFloat64, 0.025}},
+			[]bracket{{0, 78775, 0}, {78775, 289975, 0.0195}, {289975, math.MaxFloat64, 0.025}},
+			[]bracket{{0, 63175, 0}, {63175, 264100, 0.0195}, {264100, math.MaxFloat64, 0.025}},
+		),
+		federalDeductions,
+	},
+	"NE": {
+		perStatus(
+			[]bracket{{0, 3880, 0.0246}, {3880, 23370, 0.0351}, {23370, 37670, 0.0501}, {37670, math.MaxFloat64, 0.0584}},
+			[]bracket{{0, 7770, 0.0246}, {7770, 46750, 0.0351}, {46750, 75340, 0.0501}, {75340, math.MaxFloat64, 0.0584}},
+			[]bracket{{0, 7220, 0.0246}, {7220, 37030, 0.0351}, {37030, 55260, 0.0501}, {55260, math.MaxFloat64, 0.0584}},
+		),
+		perStatus(8300.0, 16600, 12200),
+	},
+	"NJ": {perStatus(njSingle, njJoint, njJoint), perStatus(1000.0, 2000, 1000)},
+	"NM": {perStatus(nmSingle, nmJoint, nmJoint), federalDeductions},
+	"NY": {perStatus(nySingle, nyJoint, nyHead), perStatus(8000.0, 16050, 11200)},
+	"OH": {perStatus(ohAll, ohAll, ohAll), perStatus(0.0, 0, 0)},
+	"OK": {perStatus(okSingle, scaleBrackets(okSingle, 2), scaleBrackets(okSingle, 2)), perStatus(7350.0, 14700, 10350)},
+	"OR": {perStatus(orSingle, scaleBrackets(orSingle, 2), scaleBrackets(orSingle, 2)), perStatus(2745.0, 5495, 4420)},
+	"RI": {perStatus(riAll, riAll, riAll), perStatus(15500.0, 31050, 20800)},
+	"SC": {perStatus(scAll, scAll, scAll), federalDeductions},
+	"VA": {perStatus(vaAll, vaAll, vaAll), perStatus(9430.0, 18860, 9430)},
+	"VT": {
+		perStatus(
+			[]bracket{{0, 45400, 0.0335}, {45400, 110050, 0.066}, {110050, 229550, 0.076}, {229550, math.MaxFloat64, 0.0875}},
+			[]bracket{{0, 75850, 0.0335}, {75850, 183400, 0.066}, {183400, 279450, 0.076}, {279450, math.MaxFloat64, 0.0875}},
+			[]bracket{{0, 60850, 0.0335}, {60850, 157150, 0.066}, {157150, 254500, 0.076}, {254500, math.MaxFloat64, 0.0875}},
+		),
+		perStatus(12500.0, 25050, 16200),
+	},
+	"WI": {
+		perStatus(
+			wiSingle,
+			[]bracket{{0, 19090, 0.035}, {19090, 38190, 0.044}, {38190, 420420, 0.053}, {420420, math.MaxFloat64, 0.0765}},
+			wiSingle,
+		),
+		perStatus(13930.0, 25890, 17790),
+	},
+	"WV": {perStatus(wvAll, wvAll, wvAll), perStatus(2000.0, 4000, 2000)},
+}
+
+// HasStateBrackets reports whether the state with the given postal code
+// taxes income on graduated brackets rather than a flat rate.
+func HasStateBrackets(code string) bool {
+	_, ok := stateTable[code]
+	return ok
+}
+
+// stateDeduction returns the deduction a graduated state subtracts before
+// applying its brackets, or zero for flat-tax states.
+func stateDeduction(code string, status FilingStatus) float64 {
+	if !status.Valid() {
+		status = FilingSingle
+	}
+	return stateTable[code].deduction[status]
+}
+
+// stateIncomeTax returns the state tax on base: on the state's brackets for
+// the filing status, after its deduction, when it has them, otherwise at the
+// flat rate (a percent).
+func stateIncomeTax(base float64, code string, flatRate float64, status FilingStatus) float64 {
+	rules, ok := stateTable[code]
+	if !ok {
+		return base * flatRate / 100
+	}
+	if !status.Valid() {
+		status = FilingSingle
+	}
+	taxable := math.Max(0, base-rules.deduction[status])
+	var tax float64
+	for _, b := range rules.brackets[status] {
+		if taxable <= b.Min {
+			break
+		}
+		tax += (math.Min(taxable, b.Max) - b.Min) * b.Rate
+	}
+	return tax
+}
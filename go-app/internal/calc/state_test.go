@@ -0,0 +1,61 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateTaxBreakdown_StateBrackets(t *testing.T) {
+	// California on 100,000 less the 5,540 deduction: 1% to 10,756, 2% to
+	// 25,499, 4% to 40,245, 6% to 55,866, 8% to 70,606 and 9.3% on the last
+	// 23,854
+	ca := CalculateTaxBreakdown(TaxInput{GrossAnnual: 100000, StateTaxRate: 13.3, StateCode: "CA"})
+	if ca.StateTax != NewMoney(5327.14) {
+		t.Errorf("expected 5327.14 of California tax, got %s", ca.StateTax)
+	}
+
+	// Joint filers on 200,000 less the 11,080 deduction, with 9.3% on the
+	// 47,708 above 141,212
+	joint := CalculateTaxBreakdown(TaxInput{GrossAnnual: 200000, StateTaxRate: 13.3, StateCode: "CA", FilingStatus: FilingMarriedJoint})
+	if joint.StateTax != NewMoney(10654.28) {
+		t.Errorf("expected 10654.28 of California tax filing jointly, got %s", joint.StateTax)
+	}
+
+	// Flat-tax states aren't in the table and keep the flat rate
+	il := CalculateTaxBreakdown(TaxInput{GrossAnnual: 100000, StateTaxRate: 4.95, StateCode: "IL"})
+	if il.StateTax != NewMoney(4950) {
+		t.Errorf("expected 4950 of Illinois tax, got %s", il.StateTax)
+	}
+}
+
+func TestStateIncomeTax(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   float64
+		code   string
+		status FilingStatus
+		want   float64
+	}{
+		{"below the first bracket", 0, "CA", FilingSingle, 0},
+		{"below the deduction", 5000, "CA", FilingSingle, 0},
+		{"zero-rate first bracket", 20000, "OH", FilingSingle, 0},
+		// 2.75% of 73,950 plus 3.5% of 20,000
+		{"top bracket", 120000, "OH", FilingSingle, 2733.625},
+		// 52,000 after the 8,000 deduction: 4% to 8,500, 4.5% to 11,700,
+		// 5.25% to 13,900, 5.5% to 52,000
+		{"New York", 60000, "NY", FilingSingle, 2695},
+		// 83,950 after the 16,050 deduction: 4% to 17,150, 4.5% to 23,600,
+		// 5.25% to 27,900, 5.5% to 83,950
+		{"New York joint", 100000, "NY", FilingMarriedJoint, 4284.75},
+		// 88,920 after the 11,080 deduction: 1% to 21,527, 2% to 51,000,
+		// 4% to 65,744, 6% to 81,364, 8% to 88,920
+		{"California head of household", 100000, "CA", FilingHeadHousehold, 2936.17},
+		{"blank status files single", 60000, "NY", "", 2695},
+		{"unknown code uses the flat rate", 50000, "", FilingSingle, 2500},
+	}
+	for _, tt := range tests {
+		if got := stateIncomeTax(tt.base, tt.code, 5, tt.status); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("%s: expected %.3f, got %.3f", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package calc
+
+import (
+	"math"
+	"sort"
+)
+
+// StreamType identifies how an income stream is taxed.
+type StreamType string
+
+// Income stream types.
+const (
+	StreamW2           StreamType = "w2"
+	Stream1099         StreamType = "1099"
+	StreamRental       StreamType = "rental"
+	StreamDividends    StreamType = "dividends"
+	StreamInterest     StreamType = "interest"
+	StreamCapitalGains StreamType = "capital_gains"
+	StreamRoyalties    StreamType = "royalties"
+)
+
+// Passive activity loss rules for rental real estate (single filer).
+const (
+	RentalLossAllowance     = 25000.0  // deductible against other income with active participation
+	RentalLossPhaseOutStart = 100000.0 // allowance shrinks by $1 for every $2 of MAGI above this
+)
+
+// streamTypeOrder is the order streams are stacked in when attributing tax:
+// wages first, then other ordinary income, then preferential income on top.
+var streamTypeOrder = []StreamType{
+	StreamW2, Stream1099, StreamRental, StreamInterest, StreamRoyalties, StreamDividends, StreamCapitalGains,
+}
+
+// Label returns the display name of a stream type.
+func (t StreamType) Label() string {
+	switch t {
+	case StreamW2:
+		return "W-2 Wages"
+	case Stream1099:
+		return "1099 / Self-Employment"
+	case StreamRental:
+		return "Rental"
+	case StreamDividends:
+		return "Dividends"
+	case StreamInterest:
+		return "Interest"
+	case StreamCapitalGains:
+		return "Capital Gains"
+	case StreamRoyalties:
+		return "Royalties"
+	default:
+		return string(t)
+	}
+}
+
+// Valid reports whether t is a known stream type.
+func (t StreamType) Valid() bool {
+	for _, st := range streamTypeOrder {
+		if t == st {
+			return true
+		}
+	}
+	return false
+}
+
+// stackRank returns a stream type's position in streamTypeOrder. Unknown
+// types are taxed as passive income and stacked with royalties.
+func (t StreamType) stackRank() int {
+	for i, st := range streamTypeOrder {
+		if t == st {
+			return i
+		}
+	}
+	return StreamRoyalties.stackRank()
+}
+
+// IncomeStream is one source of annual income. Expenses are deductible costs
+// (business expenses for 1099 work, operating costs for a rental);
+// Depreciation is the non-cash rental deduction. Dividends are treated as
+// qualified and capital gains as long-term unless ShortTerm is set.
+type IncomeStream struct {
+	Name         string     `json:"name"`
+	Type         StreamType `json:"type"`
+	Annual       float64    `json:"annual"`
+	Expenses     float64    `json:"expenses"`
+	Depreciation float64    `json:"depreciation"`
+	ShortTerm    bool       `json:"short_term"`
+}
+
+// StreamsInput holds the income streams for CalculateStreams.
+type StreamsInput struct {
+	Streams      []IncomeStream `json:"streams"`
+	StateTaxRate float64        `json:"state_tax_rate"`
+}
+
+// StreamResult is the tax attributed to one income stream.
+type StreamResult struct {
+	Name          string     `json:"name"`
+	Type          StreamType `json:"type"`
+	Annual        Money      `json:"annual"`
+	Taxable       Money      `json:"taxable"` // after expenses, depreciation and loss limits
+	Tax           Money      `json:"tax"`
+	AfterTax      Money      `json:"after_tax"` // annual income less cash expenses and tax
+	EffectiveRate float64    `json:"effective_rate"`
+	SuspendedLoss Money      `json:"suspended_loss,omitempty"` // rental loss carried forward
+	Percent       float64    `json:"percent"`                  // share of total annual income
+}
+
+// StreamsResult is the combined after-tax picture across income streams.
+type StreamsResult struct {
+	Streams         []StreamResult `json:"streams"`
+	TotalAnnual     Money          `json:"total_annual"`
+	TotalTax        Money          `json:"total_tax"`
+	TotalAfterTax   Money          `json:"total_after_tax"`
+	MonthlyAfterTax Money          `json:"monthly_after_tax"`
+	EffectiveRate   float64        `json:"effective_rate"`
+	SuspendedLoss   Money          `json:"suspended_loss"`
+	Taxes           *TaxBreakdown  `json:"taxes"`
+}
+
+// totalTax is every tax in a breakdown, excluding pre-tax contributions.
+func totalTax(t *TaxBreakdown) Money {
+	return t.FederalTax + t.CapitalGainsTax + t.NIIT + t.StateTax + t.FICATax + t.SelfEmploymentTax
+}
+
+// rentalLossAllowance returns how much of a net rental loss may offset other
+// income, given modified AGI from everything else.
+func rentalLossAllowance(magi float64) float64 {
+	return math.Max(0, RentalLossAllowance-math.Max(0, magi-RentalLossPhaseOutStart)/2)
+}
+
+// addStream folds a stream's taxable amount into the tax input.
+func addStream(in *TaxInput, s IncomeStream, taxable float64) {
+	switch s.Type {
+	case StreamW2:
+		in.GrossAnnual += taxable
+	case Stream1099:
+		in.SelfEmploymentIncome += taxable
+	case StreamDividends:
+		in.QualifiedDividends += taxable
+	case StreamCapitalGains:
+		if s.ShortTerm {
+			in.ShortTermGains += taxable
+		} else {
+			in.LongTermGains += taxable
+		}
+	default:
+		in.PassiveIncome += taxable
+	}
+}
+
+// CalculateStreams computes the after-tax value of each income stream. Tax is
+// attributed by stacking streams in streamTypeOrder and charging each with
+// the tax it adds, so the per-stream taxes sum to the total.
+func CalculateStreams(in StreamsInput) *StreamsResult {
+	taxable := make([]float64, len(in.Streams))
+	var totalAnnual, otherMAGI, rentalNet float64
+	for i, s := range in.Streams {
+		totalAnnual += s.Annual
+		taxable[i] = s.Annual - s.Expenses
+		if s.Type == StreamRental {
+			taxable[i] -= s.Depreciation
+			rentalNet += taxable[i]
+		} else {
+			otherMAGI += taxable[i]
+		}
+	}
+
+	// Rental losses beyond the allowance are suspended, pro rata across
+	// the losing properties
+	var suspended float64
+	suspendedByStream := make([]float64, len(in.Streams))
+	if rentalNet < 0 {
+		suspended = math.Max(0, -rentalNet-rentalLossAllowance(otherMAGI))
+		var losses float64
+		for i, s := range in.Streams {
+			if s.Type == StreamRental && taxable[i] < 0 {
+				losses -= taxable[i]
+			}
+		}
+		for i, s := range in.Streams {
+			if s.Type == StreamRental && taxable[i] < 0 {
+				suspendedByStream[i] = suspended * -taxable[i] / losses
+				taxable[i] += suspendedByStream[i]
+			}
+		}
+	}
+
+	order := make([]int, len(in.Streams))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return in.Streams[order[a]].Type.stackRank() < in.Streams[order[b]].Type.stackRank()
+	})
+
+	taxIn := TaxInput{StateTaxRate: in.StateTaxRate}
+	prevTax := totalTax(CalculateTaxBreakdown(taxIn))
+	streamTax := make([]Money, len(in.Streams))
+	for _, i := range order {
+		addStream(&taxIn, in.Streams[i], taxable[i])
+		tax := totalTax(CalculateTaxBreakdown(taxIn))
+		streamTax[i] = tax - prevTax
+		prevTax = tax
+	}
+	breakdown := CalculateTaxBreakdown(taxIn)
+
+	result := &StreamsResult{
+		TotalTax:      totalTax(breakdown),
+		SuspendedLoss: NewMoney(suspended),
+		Taxes:         breakdown,
+	}
+	for i, s := range in.Streams {
+		annual := NewMoney(s.Annual)
+		afterTax := annual - NewMoney(s.Expenses) - streamTax[i]
+		result.TotalAnnual += annual
+		result.TotalAfterTax += afterTax
+		r := StreamResult{
+			Name:          s.Name,
+			Type:          s.Type,
+			Annual:        annual,
+			Taxable:       NewMoney(taxable[i]),
+			Tax:           streamTax[i],
+			AfterTax:      afterTax,
+			SuspendedLoss: NewMoney(suspendedByStream[i]),
+		}
+		if s.Annual > 0 {
+			r.EffectiveRate = math.Round(streamTax[i].Float()/s.Annual*1000) / 10
+		}
+		if totalAnnual > 0 {
+			r.Percent = math.Round(s.Annual/totalAnnual*1000) / 10
+		}
+		result.Streams = append(result.Streams, r)
+	}
+	result.MonthlyAfterTax = result.TotalAfterTax.Div(12)
+	if totalAnnual > 0 {
+		result.EffectiveRate = math.Round(result.TotalTax.Float()/totalAnnual*1000) / 10
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package calc
+
+import "testing"
+
+func TestCalculateStreams_W2Only(t *testing.T) {
+	result := CalculateStreams(StreamsInput{
+		Streams: []IncomeStream{{Name: "Job", Type: StreamW2, Annual: 80000}},
+	})
+
+	want := CalculateTaxes(80000, 0, 0, 0)
+	if result.TotalTax != want.FederalTax+want.FICATax {
+		t.Errorf("expected total tax %v, got %v", want.FederalTax+want.FICATax, result.TotalTax)
+	}
+	if result.Streams[0].AfterTax != want.NetAnnual {
+		t.Errorf("expected after-tax %v, got %v", want.NetAnnual, result.Streams[0].AfterTax)
+	}
+}
+
+func TestCalculateStreams_SelfEmployment(t *testing.T) {
+	result := CalculateStreams(StreamsInput{
+		Streams: []IncomeStream{{Name: "Consulting", Type: Stream1099, Annual: 50000, Expenses: 10000}},
+	})
+
+	// SE tax on 92.35% of 40,000 net, half of it deducted from AGI
+	s := result.Streams[0]
+	if result.Taxes.SelfEmploymentTax.Dollars() != 5652 {
+		t.Errorf("expected SE tax 5652, got %v", result.Taxes.SelfEmploymentTax)
+	}
+	if result.Taxes.FICATax != 0 {
+		t.Errorf("1099 income shouldn't owe FICA, got %v", result.Taxes.FICATax)
+	}
+	if s.Taxable != NewMoney(40000) || s.Tax.Dollars() != 8129 || s.AfterTax != NewMoney(40000)-s.Tax {
+		t.Errorf("expected 40000 taxable, 8129 tax, 31871 after tax, got %v/%v/%v", s.Taxable, s.Tax, s.AfterTax)
+	}
+}
+
+func TestCalculateStreams_PreferentialStacking(t *testing.T) {
+	result := CalculateStreams(StreamsInput{
+		Streams: []IncomeStream{
+			{Name: "Portfolio", Type: StreamDividends, Annual: 20000},
+			{Name: "Job", Type: StreamW2, Annual: 50000},
+		},
+	})
+
+	// Dividends stack on top of wages: 11,625 at 0% and 8,375 at 15%
+	if result.Streams[0].Tax.Dollars() != 1256 {
+		t.Errorf("expected dividend tax 1256, got %v", result.Streams[0].Tax)
+	}
+	if result.Streams[0].EffectiveRate != 6.3 {
+		t.Errorf("expected dividend effective rate 6.3, got %.1f", result.Streams[0].EffectiveRate)
+	}
+
+	var sum Money
+	for _, s := range result.Streams {
+		sum += s.Tax
+	}
+	if sum != result.TotalTax {
+		t.Errorf("stream taxes %v should sum to the total %v", sum, result.TotalTax)
+	}
+}
+
+func TestCalculateStreams_RentalLoss(t *testing.T) {
+	result := CalculateStreams(StreamsInput{
+		Streams: []IncomeStream{
+			{Name: "Job", Type: StreamW2, Annual: 120000},
+			{Name: "Duplex", Type: StreamRental, Annual: 20000, Expenses: 30000, Depreciation: 10000},
+		},
+		StateTaxRate: 5,
+	})
+
+	// MAGI of 120,000 cuts the 25,000 allowance to 15,000
+	rental := result.Streams[1]
+	if rental.Taxable != NewMoney(-15000) || rental.SuspendedLoss != NewMoney(5000) || result.SuspendedLoss != NewMoney(5000) {
+		t.Errorf("expected 15000 allowed and 5000 suspended, got %v and %v", rental.Taxable, rental.SuspendedLoss)
+	}
+	if rental.Tax >= 0 {
+		t.Errorf("an allowed rental loss should reduce tax, got %v", rental.Tax)
+	}
+	// Depreciation is a non-cash deduction, so it doesn't reduce after-tax cash
+	if rental.AfterTax != NewMoney(-10000)-rental.Tax {
+		t.Errorf("expected after-tax %v, got %v", NewMoney(-10000)-rental.Tax, rental.AfterTax)
+	}
+	if result.TotalAfterTax != result.Streams[0].AfterTax+rental.AfterTax {
+		t.Errorf("total after-tax %v doesn't match the streams", result.TotalAfterTax)
+	}
+}
@@ -0,0 +1,336 @@
+package calc
+
+import (
+	"math"
+	"time"
+)
+
+// 2024 HHS poverty guidelines for the 48 contiguous states.
+const (
+	PovertyGuidelineBase      = 15060.0
+	PovertyGuidelinePerPerson = 5380.0
+)
+
+const (
+	// PSLFQualifyingMonths is the number of qualifying payments before
+	// Public Service Loan Forgiveness.
+	PSLFQualifyingMonths = 120
+	// firstTaxableForgivenessYear is the first year IDR forgiveness counts as
+	// taxable income again after the American Rescue Plan exclusion lapses.
+	firstTaxableForgivenessYear = 2026
+)
+
+// StudentLoanInput holds the borrower details shared by every repayment plan.
+// AGI comes from the TaxBreakdown passed alongside it.
+type StudentLoanInput struct {
+	Balance      float64 `json:"balance"`
+	InterestRate float64 `json:"interest_rate"`
+	FamilySize   int     `json:"family_size"`
+	IncomeGrowth float64 `json:"income_growth"` // annual AGI growth, percent
+	GraduateDebt bool    `json:"graduate_debt"` // SAVE: 10% of discretionary income and 25-year forgiveness
+	NewBorrower  bool    `json:"new_borrower"`  // first borrowed on or after July 1, 2014: IBR at 10% over 20 years
+	PSLF         bool    `json:"pslf"`          // employed by a qualifying public-service employer
+	PAYEEnrolled bool    `json:"paye_enrolled"` // already on PAYE, which is closed to new borrowers
+	StartYear    int     `json:"start_year"`    // first repayment year (defaults to the current year)
+
+	FilingStatus FilingStatus `json:"filing_status"` // for the tax on forgiven balances; defaults to single
+}
+
+// StudentLoanPlan represents the projected cost of one repayment plan.
+type StudentLoanPlan struct {
+	Key             string `json:"key"`
+	Name            string `json:"name"`
+	Eligible        bool   `json:"eligible"`
+	IncomeDriven    bool   `json:"income_driven"`
+	Note            string `json:"note,omitempty"`
+	FirstPayment    Money  `json:"first_payment"`
+	MaxPayment      Money  `json:"max_payment"`
+	Months          int    `json:"months"`
+	TotalPaid       Money  `json:"total_paid"`
+	TotalInterest   Money  `json:"total_interest"`
+	Forgiven        Money  `json:"forgiven"`
+	ForgivenessYear int    `json:"forgiveness_year,omitempty"`
+	ForgivenessTax  Money  `json:"forgiveness_tax"`
+	LifetimeCost    Money  `json:"lifetime_cost"`
+	PSLFQualifying  bool   `json:"pslf_qualifying"`
+	PSLFTotalPaid   Money  `json:"pslf_total_paid"`
+	PSLFForgiven    Money  `json:"pslf_forgiven"`
+}
+
+// StudentLoanComparison lists every repayment plan side by side.
+type StudentLoanComparison struct {
+	Balance          Money             `json:"balance"`
+	AGI              Money             `json:"agi"`
+	FamilySize       int               `json:"family_size"`
+	PovertyGuideline Money             `json:"poverty_guideline"`
+	StandardPayment  Money             `json:"standard_payment"`
+	Plans            []StudentLoanPlan `json:"plans"`
+	Cheapest         string            `json:"cheapest"`
+}
+
+// PovertyGuideline returns the annual poverty guideline for a household.
+func PovertyGuideline(familySize int) float64 {
+	if familySize < 1 {
+		familySize = 1
+	}
+	return PovertyGuidelineBase + PovertyGuidelinePerPerson*float64(familySize-1)
+}
+
+// discretionaryPayment returns a monthly IDR payment: percent of the AGI above
+// a multiple of the poverty guideline, divided over twelve months.
+func discretionaryPayment(agi float64, familySize int, povertyMultiple, percent float64) float64 {
+	discretionary := math.Max(0, agi-povertyMultiple*PovertyGuideline(familySize))
+	return discretionary * percent / 100 / 12
+}
+
+// rapPayment returns the monthly payment under the Repayment Assistance Plan:
+// 1-10% of total AGI by income tier, less $50 per dependent, with a $10 minimum.
+func rapPayment(agi float64, dependents int) float64 {
+	if agi <= 10000 {
+		return 10
+	}
+	percent := math.Min(10, math.Floor(agi/10000))
+	payment := agi*percent/100/12 - 50*float64(dependents)
+	return math.Max(10, payment)
+}
+
+// loanSchedule describes how a plan sets each month's payment.
+type loanSchedule struct {
+	maxMonths           int
+	payment             func(month int, agi float64) float64
+	waiveUnpaidInterest bool
+	principalMatch      float64
+}
+
+type loanOutcome struct {
+	firstPayment  float64
+	maxPayment    float64
+	months        int
+	totalPaid     float64
+	totalInterest float64
+	forgiven      float64
+	pslfPaid      float64
+	pslfForgiven  float64
+}
+
+// simulateStudentLoan runs a plan month by month with AGI growing each year.
+// Any balance left after maxMonths is forgiven.
+func simulateStudentLoan(balance, annualRate, agi, incomeGrowth float64, s loanSchedule) loanOutcome {
+	var out loanOutcome
+	monthlyRate := annualRate / 100 / 12
+
+	for m := 0; m < s.maxMonths && balance > 0.005; m++ {
+		yearAGI := agi * math.Pow(1+incomeGrowth/100, float64(m/12))
+		interest := balance * monthlyRate
+		pay := math.Min(math.Max(0, s.payment(m, yearAGI)), balance+interest)
+
+		if s.waiveUnpaidInterest && pay < interest {
+			interest = pay
+		}
+		balance += interest - pay
+		if s.principalMatch > 0 {
+			principalPaid := pay - interest
+			if principalPaid < s.principalMatch {
+				balance -= math.Min(s.principalMatch-principalPaid, pay)
+				balance = math.Max(0, balance)
+			}
+		}
+
+		if m == 0 {
+			out.firstPayment = pay
+		}
+		out.maxPayment = math.Max(out.maxPayment, pay)
+		out.totalPaid += pay
+		out.totalInterest += interest
+		out.months = m + 1
+		if m < PSLFQualifyingMonths {
+			out.pslfPaid += pay
+			if m == PSLFQualifyingMonths-1 {
+				out.pslfForgiven = math.Max(0, balance)
+			}
+		}
+	}
+	if balance > 0.005 {
+		out.forgiven = balance
+	}
+	return out
+}
+
+// graduatedSchedule starts at the larger of interest-only and half the standard
+// payment and steps up every two years, with the step sized to retire the loan
+// in ten years.
+func graduatedSchedule(balance, annualRate, standard float64) loanSchedule {
+	start := math.Max(balance*annualRate/100/12, standard/2)
+	build := func(step float64) loanSchedule {
+		return loanSchedule{maxMonths: 120, payment: func(month int, _ float64) float64 {
+			return start * math.Pow(step, float64(month/24))
+		}}
+	}
+
+	lo, hi := 1.0, 3.0
+	for i := 0; i < 50; i++ {
+		mid := (lo + hi) / 2
+		if simulateStudentLoan(balance, annualRate, 0, 0, build(mid)).forgiven > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return build(hi)
+}
+
+// forgivenessTax estimates federal and state tax owed on a forgiven balance by
+// stacking it on top of that year's AGI.
+func forgivenessTax(forgiven, agi, stateRate float64, status FilingStatus) float64 {
+	taxable := math.Max(0, agi-status.StandardDeduction())
+	federal := federalIncomeTax(taxable+forgiven, status) - federalIncomeTax(taxable, status)
+	return federal + forgiven*stateRate
+}
+
+// CompareStudentLoanPlans projects the federal Standard, Graduated and Extended
+// plans and the income-driven IBR, PAYE, SAVE and RAP plans side by side.
+// IDR payments are based on the AGI in tax, recalculated each year.
+func CompareStudentLoanPlans(in StudentLoanInput, tax *TaxBreakdown) *StudentLoanComparison {
+	agi := tax.AGI.Float()
+	startYear := in.StartYear
+	if startYear == 0 {
+		startYear = time.Now().Year()
+	}
+	familySize := in.FamilySize
+	if familySize < 1 {
+		familySize = 1
+	}
+	dependents := familySize - 1
+
+	// Apply the state's average rate on AGI to the forgiven balance; on
+	// graduated states this slightly understates the tax on the top dollars
+	var stateRate float64
+	if tax.AGI > 0 {
+		stateRate = tax.StateTax.Float() / tax.AGI.Float()
+	}
+
+	standard := amortizedPayment(in.Balance, in.InterestRate, 120)
+	fixed := func(months int, payment float64) loanSchedule {
+		return loanSchedule{maxMonths: months, payment: func(int, float64) float64 { return payment }}
+	}
+
+	saveMonths, savePercent := 240, 5.0
+	if in.GraduateDebt {
+		saveMonths, savePercent = 300, 10.0
+	}
+
+	// Borrowers before July 2014 pay 15% over 25 years on IBR
+	ibrMonths, ibrPercent := 300, 15.0
+	ibrNote := "15% of income above 150% of poverty, capped at the standard payment; forgiven after 25 years."
+	if in.NewBorrower {
+		ibrMonths, ibrPercent = 240, 10.0
+		ibrNote = "10% of income above 150% of poverty for new borrowers since July 2014, capped at the standard payment; forgiven after 20 years."
+	}
+
+	plans := []struct {
+		key, name, note string
+		idr, eligible   bool
+		pslf            bool
+		schedule        loanSchedule
+	}{
+		{
+			key: "standard", name: "Standard (10-Year)", eligible: true, pslf: true,
+			schedule: fixed(120, standard),
+		},
+		{
+			key: "graduated", name: "Graduated (10-Year)", eligible: true,
+			note:     "Payments start low and rise every two years.",
+			schedule: graduatedSchedule(in.Balance, in.InterestRate, standard),
+		},
+		{
+			key: "extended", name: "Extended (25-Year)", eligible: in.Balance > 30000,
+			note:     "Requires more than $30,000 in federal Direct Loans.",
+			schedule: fixed(300, amortizedPayment(in.Balance, in.InterestRate, 300)),
+		},
+		{
+			key: "ibr", name: "Income-Based Repayment (IBR)", idr: true, eligible: true, pslf: true,
+			note: ibrNote,
+			schedule: loanSchedule{maxMonths: ibrMonths, payment: func(_ int, agi float64) float64 {
+				return math.Min(discretionaryPayment(agi, familySize, 1.5, ibrPercent), standard)
+			}},
+		},
+		{
+			key: "paye", name: "Pay As You Earn (PAYE)", idr: true, eligible: in.PAYEEnrolled, pslf: true,
+			note: "Same formula as new-borrower IBR; closed to new enrollment, so only borrowers already on it can stay.",
+			schedule: loanSchedule{maxMonths: 240, payment: func(_ int, agi float64) float64 {
+				return math.Min(discretionaryPayment(agi, familySize, 1.5, 10), standard)
+			}},
+		},
+		{
+			key: "save", name: "Saving on a Valuable Education (SAVE)", idr: true, eligible: true, pslf: true,
+			note: "Income above 225% of poverty, no payment cap, and unpaid interest is waived.",
+			schedule: loanSchedule{maxMonths: saveMonths, waiveUnpaidInterest: true, payment: func(_ int, agi float64) float64 {
+				return discretionaryPayment(agi, familySize, 2.25, savePercent)
+			}},
+		},
+		{
+			key: "rap", name: "Repayment Assistance Plan (RAP)", idr: true, eligible: true, pslf: true,
+			note: "1-10% of AGI less $50 per dependent; unpaid interest waived and up to $50/month principal match; forgiven after 30 years.",
+			schedule: loanSchedule{maxMonths: 360, waiveUnpaidInterest: true, principalMatch: 50, payment: func(_ int, agi float64) float64 {
+				return rapPayment(agi, dependents)
+			}},
+		},
+	}
+
+	result := &StudentLoanComparison{
+		Balance:          NewMoney(in.Balance),
+		AGI:              tax.AGI,
+		FamilySize:       familySize,
+		PovertyGuideline: NewMoney(PovertyGuideline(familySize)),
+		StandardPayment:  NewMoney(standard),
+	}
+
+	cheapest := math.MaxFloat64
+	for _, p := range plans {
+		out := simulateStudentLoan(in.Balance, in.InterestRate, agi, in.IncomeGrowth, p.schedule)
+
+		plan := StudentLoanPlan{
+			Key:            p.key,
+			Name:           p.name,
+			Eligible:       p.eligible,
+			IncomeDriven:   p.idr,
+			Note:           p.note,
+			FirstPayment:   NewMoney(out.firstPayment),
+			MaxPayment:     NewMoney(out.maxPayment),
+			Months:         out.months,
+			TotalPaid:      NewMoney(out.totalPaid),
+			TotalInterest:  NewMoney(out.totalInterest),
+			Forgiven:       NewMoney(out.forgiven),
+			PSLFQualifying: p.pslf,
+		}
+
+		var taxOwed float64
+		if out.forgiven > 0 {
+			plan.ForgivenessYear = startYear + out.months/12
+			if plan.ForgivenessYear >= firstTaxableForgivenessYear {
+				finalAGI := agi * math.Pow(1+in.IncomeGrowth/100, float64(out.months/12))
+				taxOwed = forgivenessTax(out.forgiven, finalAGI, stateRate, in.FilingStatus)
+			}
+		}
+		plan.ForgivenessTax = NewMoney(taxOwed)
+		lifetime := out.totalPaid + taxOwed
+		plan.LifetimeCost = plan.TotalPaid + plan.ForgivenessTax
+
+		// PSLF forgiveness after 120 payments is tax-free
+		if p.pslf {
+			plan.PSLFTotalPaid = NewMoney(out.pslfPaid)
+			plan.PSLFForgiven = NewMoney(out.pslfForgiven)
+			if in.PSLF {
+				lifetime = out.pslfPaid
+			}
+		}
+
+		if p.eligible && lifetime < cheapest {
+			cheapest = lifetime
+			result.Cheapest = p.key
+		}
+		result.Plans = append(result.Plans, plan)
+	}
+
+	return result
+}
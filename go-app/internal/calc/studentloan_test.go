@@ -0,0 +1,161 @@
+package calc
+
+import "testing"
+
+func findPlan(t *testing.T, c *StudentLoanComparison, key string) StudentLoanPlan {
+	t.Helper()
+	for _, p := range c.Plans {
+		if p.Key == key {
+			return p
+		}
+	}
+	t.Fatalf("plan %q not found", key)
+	return StudentLoanPlan{}
+}
+
+func TestPovertyGuideline(t *testing.T) {
+	if got := PovertyGuideline(1); got != 15060 {
+		t.Errorf("expected 15060 for a household of 1, got %.0f", got)
+	}
+	if got := PovertyGuideline(4); got != 31200 {
+		t.Errorf("expected 31200 for a household of 4, got %.0f", got)
+	}
+}
+
+func TestCompareStudentLoanPlans(t *testing.T) {
+	tax := CalculateTaxes(60000, 0, 0, 5)
+	result := CompareStudentLoanPlans(StudentLoanInput{
+		Balance:      40000,
+		InterestRate: 6,
+		FamilySize:   1,
+		StartYear:    2024,
+	}, tax)
+
+	if result.AGI != NewMoney(60000) {
+		t.Errorf("expected AGI 60000 from CalculateTaxes, got %s", result.AGI)
+	}
+	if len(result.Plans) != 7 {
+		t.Fatalf("expected 7 plans, got %d", len(result.Plans))
+	}
+
+	standard := findPlan(t, result, "standard")
+	if standard.Months != 120 || standard.Forgiven != 0 {
+		t.Errorf("standard plan should pay off in 120 months, got %d months, %s forgiven",
+			standard.Months, standard.Forgiven)
+	}
+	if standard.FirstPayment != result.StandardPayment {
+		t.Errorf("standard first payment %s doesn't match %s", standard.FirstPayment, result.StandardPayment)
+	}
+
+	graduated := findPlan(t, result, "graduated")
+	if graduated.FirstPayment >= standard.FirstPayment || graduated.MaxPayment <= standard.FirstPayment {
+		t.Errorf("graduated payments should start below and end above standard, got %s..%s vs %s",
+			graduated.FirstPayment, graduated.MaxPayment, standard.FirstPayment)
+	}
+	if graduated.TotalPaid <= standard.TotalPaid {
+		t.Error("graduated should cost more than standard")
+	}
+
+	// Legacy IBR: 15% of (60,000 - 1.5 * 15,060) / 12 = $467.63, capped at
+	// the standard payment
+	ibr := findPlan(t, result, "ibr")
+	if ibr.FirstPayment != standard.FirstPayment {
+		t.Errorf("expected legacy IBR capped at the standard %s, got %s", standard.FirstPayment, ibr.FirstPayment)
+	}
+
+	// New-borrower IBR: 10% of (60,000 - 1.5 * 15,060) / 12 = $311.75
+	newBorrower := CompareStudentLoanPlans(StudentLoanInput{
+		Balance:      40000,
+		InterestRate: 6,
+		FamilySize:   1,
+		StartYear:    2024,
+		NewBorrower:  true,
+	}, tax)
+	if newIBR := findPlan(t, newBorrower, "ibr"); newIBR.FirstPayment != NewMoney(311.75) {
+		t.Errorf("expected new-borrower IBR first payment 311.75, got %s", newIBR.FirstPayment)
+	}
+
+	// SAVE: 5% of (60,000 - 2.25 * 15,060) / 12 = $108.81
+	save := findPlan(t, result, "save")
+	if save.FirstPayment != NewMoney(108.81) {
+		t.Errorf("expected SAVE first payment 108.81, got %s", save.FirstPayment)
+	}
+	if save.Forgiven == 0 || save.ForgivenessTax == 0 {
+		t.Error("expected SAVE balance to be forgiven and taxed after 2025")
+	}
+	if save.LifetimeCost != save.TotalPaid+save.ForgivenessTax {
+		t.Errorf("lifetime cost %s doesn't match paid + tax %s", save.LifetimeCost, save.TotalPaid+save.ForgivenessTax)
+	}
+
+	// RAP: 6% of 60,000 / 12 = $300
+	rap := findPlan(t, result, "rap")
+	if rap.FirstPayment != NewMoney(300) {
+		t.Errorf("expected RAP first payment 300, got %s", rap.FirstPayment)
+	}
+
+	// PAYE is closed to new enrollment, so it's only an option once enrolled
+	if paye := findPlan(t, result, "paye"); paye.Eligible || result.Cheapest == "paye" {
+		t.Errorf("expected PAYE ineligible for a borrower not on it, got eligible %v, cheapest %s", paye.Eligible, result.Cheapest)
+	}
+	enrolled := CompareStudentLoanPlans(StudentLoanInput{Balance: 40000, InterestRate: 6, StartYear: 2024, PAYEEnrolled: true}, tax)
+	if !findPlan(t, enrolled, "paye").Eligible {
+		t.Error("expected PAYE eligible for a borrower already enrolled")
+	}
+}
+
+func TestForgivenessTax_FilingStatus(t *testing.T) {
+	// $50,000 forgiven on $80,000 AGI: single filers stack it from $65,400
+	// taxable into the 22% bracket; joint filers from $50,800 stay at 12%
+	single := forgivenessTax(50000, 80000, 0, FilingSingle)
+	joint := forgivenessTax(50000, 80000, 0, FilingMarriedJoint)
+	want := federalIncomeTax(100800, FilingMarriedJoint) - federalIncomeTax(50800, FilingMarriedJoint)
+	if joint != want || joint >= single {
+		t.Errorf("expected joint forgiveness tax %.2f below single %.2f, got %.2f", want, single, joint)
+	}
+}
+
+func TestCompareStudentLoanPlans_PSLF(t *testing.T) {
+	tax := CalculateTaxes(50000, 0, 0, 0)
+	result := CompareStudentLoanPlans(StudentLoanInput{
+		Balance:      80000,
+		InterestRate: 6.5,
+		FamilySize:   2,
+		PSLF:         true,
+		StartYear:    2024,
+	}, tax)
+
+	ibr := findPlan(t, result, "ibr")
+	if ibr.PSLFForgiven == 0 {
+		t.Error("expected a balance left for PSLF after 120 IBR payments")
+	}
+	if ibr.PSLFTotalPaid >= ibr.TotalPaid {
+		t.Error("PSLF should stop payments after 120 months")
+	}
+	if result.Cheapest == "standard" || result.Cheapest == "graduated" || result.Cheapest == "extended" {
+		t.Errorf("expected an income-driven plan to be cheapest with PSLF, got %s", result.Cheapest)
+	}
+
+	extended := findPlan(t, result, "extended")
+	if !extended.Eligible || extended.PSLFQualifying {
+		t.Error("extended plan should be eligible over $30k but not PSLF-qualifying")
+	}
+}
+
+func TestCompareStudentLoanPlans_IBRTerm(t *testing.T) {
+	tax := CalculateTaxes(40000, 0, 0, 0)
+	for _, tt := range []struct {
+		newBorrower bool
+		years       int
+	}{{false, 25}, {true, 20}} {
+		result := CompareStudentLoanPlans(StudentLoanInput{
+			Balance:      100000,
+			InterestRate: 6,
+			StartYear:    2024,
+			NewBorrower:  tt.newBorrower,
+		}, tax)
+		ibr := findPlan(t, result, "ibr")
+		if ibr.Forgiven == 0 || ibr.ForgivenessYear != 2024+tt.years {
+			t.Errorf("new borrower %v: expected forgiveness in %d, got %d with %s forgiven", tt.newBorrower, 2024+tt.years, ibr.ForgivenessYear, ibr.Forgiven)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Step is one line of a calculation trace: what was worked out, how, and
+// the amount it came to. Amounts taken away from a running total, such as
+// pre-tax deductions, are negative.
+type Step struct {
+	Label  string `json:"label"`
+	Detail string `json:"detail,omitempty"`
+	Amount Money  `json:"amount"`
+}
+
+// trace records the steps of a calculation. A nil trace records nothing,
+// so calculators can trace unconditionally and only pay for it when asked.
+type trace struct {
+	steps []Step
+}
+
+// add records a step; detail is a fmt format for how amount was worked out.
+func (t *trace) add(label string, amount float64, detail string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	if len(args) > 0 {
+		detail = fmt.Sprintf(detail, args...)
+	}
+	t.steps = append(t.steps, Step{Label: label, Detail: detail, Amount: NewMoney(amount)})
+}
+
+// usd formats a dollar amount for a step's detail, e.g. "$168,600".
+func usd(dollars float64) string {
+	n := NewMoney(dollars).Dollars()
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + "$" + s
+}
+
+// pct formats a rate given as a fraction for a step's detail, e.g. "6.2%".
+func pct(rate float64) string {
+	return strconv.FormatFloat(math.Round(rate*10000)/100, 'f', -1, 64) + "%"
+}
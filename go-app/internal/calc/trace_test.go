@@ -0,0 +1,83 @@
+package calc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// stepAmounts indexes a trace's amounts by label.
+func stepAmounts(steps []Step) map[string]Money {
+	m := map[string]Money{}
+	for _, s := range steps {
+		m[s.Label] = s.Amount
+	}
+	return m
+}
+
+func TestExplainTaxesMatchesBreakdown(t *testing.T) {
+	for _, in := range []TaxInput{
+		{GrossAnnual: 75000, StateTaxRate: 5},
+		{GrossAnnual: 250000, Retirement401kPercent: 6, HealthInsuranceAnnual: 2400, StateTaxRate: 9.3, HSA: 3000, LongTermGains: 10000},
+		{GrossAnnual: 90000, SelfEmploymentIncome: 40000, FilingStatus: FilingMarriedJoint},
+		{GrossAnnual: 12000},
+	} {
+		want := CalculateTaxBreakdown(in)
+		if want.Steps != nil {
+			t.Errorf("expected no steps unless asked, got %d", len(want.Steps))
+		}
+		got := ExplainTaxes(in)
+		if got.NetAnnual != want.NetAnnual || got.FederalTax != want.FederalTax || got.StateTax != want.StateTax {
+			t.Errorf("%+v: explaining changed the result: %v vs %v", in, got.NetAnnual, want.NetAnnual)
+		}
+
+		steps := stepAmounts(got.Steps)
+		for label, amount := range map[string]Money{
+			"Adjusted gross income (AGI)": want.AGI,
+			"Federal income tax":          want.FederalTax,
+			"Social Security":             want.SocialSecurity,
+			"Medicare":                    want.Medicare,
+			"State income tax":            want.StateTax,
+			"Take-home pay":               want.NetAnnual,
+		} {
+			if steps[label] != amount {
+				t.Errorf("%+v: step %q shows %v, breakdown %v", in, label, steps[label], amount)
+			}
+		}
+
+		// The bracket slices add up to the federal tax, give or take a cent each
+		var slices, n Money
+		for _, s := range got.Steps {
+			if strings.HasSuffix(s.Label, " bracket") {
+				slices += s.Amount
+				n++
+			}
+		}
+		if diff := slices - want.FederalTax; diff < -n || diff > n {
+			t.Errorf("%+v: bracket steps add up to %v, federal tax is %v", in, slices, want.FederalTax)
+		}
+	}
+}
+
+func TestExplainMortgageAndIncome(t *testing.T) {
+	m := ExplainMortgage(350000, 10, 6.5, 30, 1.1, 1200)
+	steps := stepAmounts(m.Steps)
+	if steps["Monthly payment"] != m.PITI.TotalMonthly || steps["Loan amount"] != m.LoanAmount {
+		t.Errorf("mortgage steps %v don't match %+v", steps, m.PITI)
+	}
+	if _, ok := steps["PMI"]; !ok {
+		t.Error("expected a PMI step with 10% down")
+	}
+	if CalculateMortgage(350000, 10, 6.5, 30, 1.1, 1200).Steps != nil {
+		t.Error("expected no mortgage steps unless asked")
+	}
+
+	d, err := ExplainIncome(30000, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps = stepAmounts(d.Steps)
+	if steps["Daily rate"] != NewMoney(400) || steps["Annual income"] != d.GrossAnnual {
+		t.Errorf("income steps %v don't match %+v", steps, d)
+	}
+}
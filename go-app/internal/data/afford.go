@@ -1,6 +1,6 @@
 package data
 
-import "math"
+import "github.com/autolytiq/income-calculator/internal/calc"
 
 // AffordabilityData holds pre-calculated budget/affordability data for a salary level.
 type AffordabilityData struct {
@@ -17,6 +17,7 @@ type AffordabilityData struct {
 	MaxCar      int
 	MaxMortgage int
 	EmergencyFund int
+	EmergencyMonths int
 	HourlyRate  int
 	WeeklyPay   int
 }
@@ -76,61 +77,30 @@ func formatCommas(n int) string {
 	return result
 }
 
-func calcFederalTax(income int) int {
-	standardDeduction := 14600
-	taxable := income - standardDeduction
-	if taxable < 0 {
-		taxable = 0
-	}
-
-	brackets := []struct{ limit int; rate float64 }{
-		{11600, 0.10}, {47150, 0.12}, {100525, 0.22},
-		{191950, 0.24}, {243725, 0.32}, {609350, 0.35},
-		{math.MaxInt32, 0.37},
-	}
-
-	tax := 0.0
-	remaining := float64(taxable)
-	prev := 0
-
-	for _, b := range brackets {
-		bracket := float64(b.limit - prev)
-		if remaining <= 0 {
-			break
-		}
-		taxableInBracket := math.Min(remaining, bracket)
-		tax += taxableInBracket * b.rate
-		remaining -= taxableInBracket
-		prev = b.limit
-	}
-
-	return int(math.Round(tax))
-}
-
-func calcFICA(income int) int {
-	ssCap := 168600
-	ssRate := 0.062
-	medicareRate := 0.0145
-	ssTaxable := income
-	if ssTaxable > ssCap {
-		ssTaxable = ssCap
-	}
-	ss := float64(ssTaxable) * ssRate
-	medicare := float64(income) * medicareRate
-	return int(math.Round(ss + medicare))
+// CalculateAffordability generates affordability data for a given salary
+// under the default tax profile.
+func CalculateAffordability(salary int) AffordabilityData {
+	return CalculateAffordabilityFor(salary, DefaultTaxProfile)
 }
 
-// CalculateAffordability generates affordability data for a given salary.
-func CalculateAffordability(salary int) AffordabilityData {
-	federalTax := calcFederalTax(salary)
-	stateTax := int(math.Round(float64(salary) * 0.05))
-	fica := calcFICA(salary)
-	totalTaxes := federalTax + stateTax + fica
-	takeHome := salary - totalTaxes
+// CalculateAffordabilityFor generates affordability data for a salary taxed
+// under the given profile.
+func CalculateAffordabilityFor(salary int, p TaxProfile) AffordabilityData {
+	takeHome := p.Taxes(salary).NetAnnual.Dollars()
 
 	monthlyGross := salary / 12
 	monthlyNet := takeHome / 12
 
+	// Size the emergency fund from essential expenses the same way the
+	// emergency fund calculator does for a salaried single earner
+	needs := int(float64(monthlyNet) * 0.5)
+	emergencyMonths := calc.EmergencyFundMonths(calc.IncomeSalaried, 0, false)
+
+	hourly := 0
+	if c, err := calc.SalaryToHourly(float64(salary), calc.FullTimeSchedule, p.Input(salary)); err == nil {
+		hourly = int(c.HourlyRate)
+	}
+
 	return AffordabilityData{
 		Salary:        salary,
 		Slug:          salarySlug(salary),
@@ -138,14 +108,15 @@ func CalculateAffordability(salary int) AffordabilityData {
 		TakeHome:      takeHome,
 		MonthlyNet:    monthlyNet,
 		MonthlyGross:  monthlyGross,
-		Needs:         int(float64(monthlyNet) * 0.5),
+		Needs:         needs,
 		Wants:         int(float64(monthlyNet) * 0.3),
 		Savings:       int(float64(monthlyNet) * 0.2),
 		MaxRent:       int(float64(monthlyGross) * 0.3),
 		MaxCar:        int(float64(monthlyGross) * 0.12),
 		MaxMortgage:   int(float64(monthlyGross) * 0.28),
-		EmergencyFund: monthlyNet * 6,
-		HourlyRate:    salary / 2080,
+		EmergencyFund: needs * emergencyMonths,
+		EmergencyMonths: emergencyMonths,
+		HourlyRate:    hourly,
 		WeeklyPay:     takeHome / 52,
 	}
 }
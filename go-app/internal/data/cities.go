@@ -0,0 +1,110 @@
+package data
+
+import "strings"
+
+// City holds cost-of-living and local tax data for a metro area.
+// CostOfLiving is an index where 100 is the national average; state income
+// tax comes from the city's state record.
+type City struct {
+	Slug         string
+	Name         string
+	StateCode    string
+	StateSlug    string
+	CostOfLiving int
+	LocalTaxRate float64 // resident local income/wage tax percentage
+}
+
+// Cities is the metro dataset for cost-of-living comparisons.
+var Cities = []City{
+	{Slug: "new-york-ny", Name: "New York", StateCode: "NY", StateSlug: "new-york", CostOfLiving: 187, LocalTaxRate: 3.876},
+	{Slug: "san-francisco-ca", Name: "San Francisco", StateCode: "CA", StateSlug: "california", CostOfLiving: 179},
+	{Slug: "san-jose-ca", Name: "San Jose", StateCode: "CA", StateSlug: "california", CostOfLiving: 176},
+	{Slug: "honolulu-hi", Name: "Honolulu", StateCode: "HI", StateSlug: "hawaii", CostOfLiving: 186},
+	{Slug: "los-angeles-ca", Name: "Los Angeles", StateCode: "CA", StateSlug: "california", CostOfLiving: 150},
+	{Slug: "san-diego-ca", Name: "San Diego", StateCode: "CA", StateSlug: "california", CostOfLiving: 145},
+	{Slug: "boston-ma", Name: "Boston", StateCode: "MA", StateSlug: "massachusetts", CostOfLiving: 153},
+	{Slug: "seattle-wa", Name: "Seattle", StateCode: "WA", StateSlug: "washington", CostOfLiving: 152},
+	{Slug: "anchorage-ak", Name: "Anchorage", StateCode: "AK", StateSlug: "alaska", CostOfLiving: 125},
+	{Slug: "miami-fl", Name: "Miami", StateCode: "FL", StateSlug: "florida", CostOfLiving: 122},
+	{Slug: "portland-or", Name: "Portland", StateCode: "OR", StateSlug: "oregon", CostOfLiving: 121},
+	{Slug: "denver-co", Name: "Denver", StateCode: "CO", StateSlug: "colorado", CostOfLiving: 112},
+	{Slug: "chicago-il", Name: "Chicago", StateCode: "IL", StateSlug: "illinois", CostOfLiving: 107},
+	{Slug: "salt-lake-city-ut", Name: "Salt Lake City", StateCode: "UT", StateSlug: "utah", CostOfLiving: 106},
+	{Slug: "phoenix-az", Name: "Phoenix", StateCode: "AZ", StateSlug: "arizona", CostOfLiving: 104},
+	{Slug: "atlanta-ga", Name: "Atlanta", StateCode: "GA", StateSlug: "georgia", CostOfLiving: 104},
+	{Slug: "minneapolis-mn", Name: "Minneapolis", StateCode: "MN", StateSlug: "minnesota", CostOfLiving: 104},
+	{Slug: "tampa-fl", Name: "Tampa", StateCode: "FL", StateSlug: "florida", CostOfLiving: 104},
+	{Slug: "boise-id", Name: "Boise", StateCode: "ID", StateSlug: "idaho", CostOfLiving: 103},
+	{Slug: "austin-tx", Name: "Austin", StateCode: "TX", StateSlug: "texas", CostOfLiving: 102},
+	{Slug: "baltimore-md", Name: "Baltimore", StateCode: "MD", StateSlug: "maryland", CostOfLiving: 102, LocalTaxRate: 3.2},
+	{Slug: "las-vegas-nv", Name: "Las Vegas", StateCode: "NV", StateSlug: "nevada", CostOfLiving: 101},
+	{Slug: "nashville-tn", Name: "Nashville", StateCode: "TN", StateSlug: "tennessee", CostOfLiving: 101},
+	{Slug: "dallas-tx", Name: "Dallas", StateCode: "TX", StateSlug: "texas", CostOfLiving: 101},
+	{Slug: "philadelphia-pa", Name: "Philadelphia", StateCode: "PA", StateSlug: "pennsylvania", CostOfLiving: 101, LocalTaxRate: 3.75},
+	{Slug: "raleigh-nc", Name: "Raleigh", StateCode: "NC", StateSlug: "north-carolina", CostOfLiving: 100},
+	{Slug: "orlando-fl", Name: "Orlando", StateCode: "FL", StateSlug: "florida", CostOfLiving: 100},
+	{Slug: "charlotte-nc", Name: "Charlotte", StateCode: "NC", StateSlug: "north-carolina", CostOfLiving: 97},
+	{Slug: "houston-tx", Name: "Houston", StateCode: "TX", StateSlug: "texas", CostOfLiving: 94},
+	{Slug: "pittsburgh-pa", Name: "Pittsburgh", StateCode: "PA", StateSlug: "pennsylvania", CostOfLiving: 92, LocalTaxRate: 3.0},
+	{Slug: "kansas-city-mo", Name: "Kansas City", StateCode: "MO", StateSlug: "missouri", CostOfLiving: 92, LocalTaxRate: 1.0},
+	{Slug: "indianapolis-in", Name: "Indianapolis", StateCode: "IN", StateSlug: "indiana", CostOfLiving: 91, LocalTaxRate: 2.02},
+	{Slug: "louisville-ky", Name: "Louisville", StateCode: "KY", StateSlug: "kentucky", CostOfLiving: 91, LocalTaxRate: 2.2},
+	{Slug: "san-antonio-tx", Name: "San Antonio", StateCode: "TX", StateSlug: "texas", CostOfLiving: 90},
+	{Slug: "columbus-oh", Name: "Columbus", StateCode: "OH", StateSlug: "ohio", CostOfLiving: 90, LocalTaxRate: 2.5},
+	{Slug: "cincinnati-oh", Name: "Cincinnati", StateCode: "OH", StateSlug: "ohio", CostOfLiving: 89, LocalTaxRate: 1.8},
+	{Slug: "birmingham-al", Name: "Birmingham", StateCode: "AL", StateSlug: "alabama", CostOfLiving: 89, LocalTaxRate: 1.0},
+	{Slug: "detroit-mi", Name: "Detroit", StateCode: "MI", StateSlug: "michigan", CostOfLiving: 88, LocalTaxRate: 2.4},
+	{Slug: "st-louis-mo", Name: "St. Louis", StateCode: "MO", StateSlug: "missouri", CostOfLiving: 88, LocalTaxRate: 1.0},
+	{Slug: "cleveland-oh", Name: "Cleveland", StateCode: "OH", StateSlug: "ohio", CostOfLiving: 86, LocalTaxRate: 2.5},
+	{Slug: "oklahoma-city-ok", Name: "Oklahoma City", StateCode: "OK", StateSlug: "oklahoma", CostOfLiving: 86},
+	{Slug: "memphis-tn", Name: "Memphis", StateCode: "TN", StateSlug: "tennessee", CostOfLiving: 84},
+}
+
+// PopularCityPairs are the comparisons linked from the index and sitemap.
+// Any two cities or states can be compared; these are just the common moves.
+var PopularCityPairs = [][2]string{
+	{"san-francisco-ca", "austin-tx"},
+	{"new-york-ny", "miami-fl"},
+	{"new-york-ny", "austin-tx"},
+	{"los-angeles-ca", "phoenix-az"},
+	{"seattle-wa", "denver-co"},
+	{"san-francisco-ca", "seattle-wa"},
+	{"boston-ma", "raleigh-nc"},
+	{"chicago-il", "nashville-tn"},
+	{"new-york-ny", "philadelphia-pa"},
+	{"los-angeles-ca", "las-vegas-nv"},
+	{"san-jose-ca", "boise-id"},
+	{"denver-co", "salt-lake-city-ut"},
+	{"chicago-il", "dallas-tx"},
+	{"san-diego-ca", "austin-tx"},
+	{"portland-or", "seattle-wa"},
+	{"atlanta-ga", "charlotte-nc"},
+	{"new-york-ny", "california"},
+	{"california", "texas"},
+	{"new-york", "florida"},
+	{"illinois", "tennessee"},
+}
+
+// GetCity returns a city by slug, or nil if not found.
+func GetCity(slug string) *City {
+	for i := range Cities {
+		if Cities[i].Slug == slug {
+			return &Cities[i]
+		}
+	}
+	return nil
+}
+
+// CityPairSlug joins two location slugs into a /compare-cities page slug.
+func CityPairSlug(a, b string) string {
+	return a + "-vs-" + b
+}
+
+// SplitCityPair splits a "a-vs-b" page slug into its two location slugs.
+func SplitCityPair(slug string) (string, string, bool) {
+	a, b, ok := strings.Cut(slug, "-vs-")
+	if !ok || a == "" || b == "" || a == b {
+		return "", "", false
+	}
+	return a, b, true
+}
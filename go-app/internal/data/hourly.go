@@ -1,6 +1,6 @@
 package data
 
-import "math"
+import "github.com/autolytiq/income-calculator/internal/calc"
 
 // HourlyData holds pre-calculated salary breakdown for an hourly rate.
 type HourlyData struct {
@@ -30,42 +30,36 @@ func init() {
 	}
 }
 
-// CalculateHourly generates salary breakdown data for a given hourly rate.
+// CalculateHourly generates salary breakdown data for a given hourly rate
+// under the default tax profile.
 func CalculateHourly(rate int) HourlyData {
-	annual := rate * 2080 // 40 hrs/week * 52 weeks
-	monthly := annual / 12
-	biweekly := annual / 26
-	weekly := annual / 52
-	daily := annual / 260
-
-	federalTax := calcFederalTax(annual)
-	stateTax := int(math.Round(float64(annual) * 0.05))
-	fica := calcFICA(annual)
-	totalTaxes := federalTax + stateTax + fica
-	takeHome := annual - totalTaxes
-	monthlyNet := takeHome / 12
+	return CalculateHourlyFor(rate, DefaultTaxProfile)
+}
 
-	effRate := 0.0
-	if annual > 0 {
-		effRate = math.Round(float64(totalTaxes)/float64(annual)*1000) / 10
+// CalculateHourlyFor generates salary breakdown data for an hourly rate on a
+// full-time schedule, taxed under the given profile.
+func CalculateHourlyFor(rate int, p TaxProfile) HourlyData {
+	c, err := calc.HourlyToSalary(float64(rate), calc.FullTimeSchedule, p.Input(0))
+	if err != nil {
+		return HourlyData{Rate: rate, Slug: formatInt(rate)}
 	}
 
 	return HourlyData{
 		Rate:       rate,
 		Slug:       formatInt(rate),
-		Annual:     annual,
-		Monthly:    monthly,
-		Biweekly:   biweekly,
-		Weekly:     weekly,
-		Daily:      daily,
-		TakeHome:   takeHome,
-		MonthlyNet: monthlyNet,
-		FederalTax: federalTax,
-		StateTax:   stateTax,
-		FICA:       fica,
-		TotalTaxes: totalTaxes,
-		EffRate:    effRate,
-		AffordSlug: ClosestAffordSlug(annual),
+		Annual:     c.Gross.Annual.Dollars(),
+		Monthly:    c.Gross.Monthly.Dollars(),
+		Biweekly:   c.Gross.Biweekly.Dollars(),
+		Weekly:     c.Gross.Weekly.Dollars(),
+		Daily:      c.Gross.Daily.Dollars(),
+		TakeHome:   c.Net.Annual.Dollars(),
+		MonthlyNet: c.Net.Monthly.Dollars(),
+		FederalTax: c.FederalTax.Dollars(),
+		StateTax:   c.StateTax.Dollars(),
+		FICA:       c.FICATax.Dollars(),
+		TotalTaxes: c.TotalTax.Dollars(),
+		EffRate:    c.EffectiveTaxRate,
+		AffordSlug: ClosestAffordSlug(c.Gross.Annual.Dollars()),
 	}
 }
 
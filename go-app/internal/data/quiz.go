@@ -147,8 +147,8 @@ var QuizPersonalities = map[string]QuizPersonalityType{
 		WatchOuts:  []string{"May neglect emergency savings", "Could over-leverage with debt", "Might chase returns over stability", "May undervalue present experiences"},
 		Tips:       []string{"Maintain at least 3 months in liquid emergency savings", "Diversify across asset classes, not just stocks", "Consider tax-advantaged accounts like 401(k) and IRA", "Set a ceiling on speculative investments"},
 		Tools: []QuizTool{
+			{Name: "FIRE Calculator", Desc: "Find your financial independence age", URL: "/fire"},
 			{Name: "Investment Apps", Desc: "Compare top investment platforms", URL: "/best/investment-apps"},
-			{Name: "Income Calculator", Desc: "Project your annual earnings", URL: "/calculator"},
 			{Name: "Tax Calculator", Desc: "Minimize your tax burden", URL: "/taxes"},
 		},
 	},
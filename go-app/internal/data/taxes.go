@@ -0,0 +1,50 @@
+package data
+
+import (
+	"math"
+
+	"github.com/autolytiq/income-calculator/internal/calc"
+)
+
+// TaxProfile is the filing situation programmatic pages are computed for.
+// Every page runs its numbers through the same calc pipeline as the
+// interactive tax calculator, so the two can never disagree.
+type TaxProfile struct {
+	StateTaxRate float64 // percent of AGI
+	StateCode    string  // graduated states are taxed on their brackets instead
+	FilingStatus calc.FilingStatus
+}
+
+// DefaultTaxProfile is a single filer paying a 5% state income tax.
+var DefaultTaxProfile = TaxProfile{StateTaxRate: 5, FilingStatus: calc.FilingSingle}
+
+// Input returns the calculator input for a salary under the profile.
+func (p TaxProfile) Input(salary int) calc.TaxInput {
+	return calc.TaxInput{
+		GrossAnnual:  float64(salary),
+		StateTaxRate: p.StateTaxRate,
+		StateCode:    p.StateCode,
+		FilingStatus: p.FilingStatus,
+	}
+}
+
+// Taxes computes federal, state and FICA tax on a salary under the profile.
+func (p TaxProfile) Taxes(salary int) *calc.TaxBreakdown {
+	return calc.CalculateTaxBreakdown(p.Input(salary))
+}
+
+// StateRate returns state tax as a percentage of salary, to the hundredth.
+// It is the flat rate unless the profile's state has graduated brackets.
+func (p TaxProfile) StateRate(salary int) float64 {
+	if salary <= 0 {
+		return p.StateTaxRate
+	}
+	return math.Round(p.Taxes(salary).StateTax.Float()/float64(salary)*10000) / 100
+}
+
+// MarginalRate returns the federal bracket, as a percentage, that the next
+// dollar of salary falls in.
+func (p TaxProfile) MarginalRate(salary int) int {
+	taxable := float64(salary) - p.FilingStatus.StandardDeduction()
+	return int(p.FilingStatus.MarginalRate(taxable)*100 + 0.5)
+}
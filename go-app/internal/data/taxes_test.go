@@ -0,0 +1,38 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/autolytiq/income-calculator/internal/calc"
+)
+
+func TestTaxProfileFilingStatus(t *testing.T) {
+	single := TaxProfile{FilingStatus: calc.FilingSingle}.Taxes(100000)
+	joint := TaxProfile{FilingStatus: calc.FilingMarriedJoint}.Taxes(100000)
+	head := TaxProfile{FilingStatus: calc.FilingHeadHousehold}.Taxes(100000)
+	if !(joint.FederalTax < head.FederalTax && head.FederalTax < single.FederalTax) {
+		t.Errorf("expected joint < head of household < single, got %d, %d, %d", joint.FederalTax, head.FederalTax, single.FederalTax)
+	}
+	if joint.FICATax != single.FICATax {
+		t.Errorf("expected FICA to ignore filing status, got %d and %d", joint.FICATax, single.FICATax)
+	}
+
+	if r := DefaultTaxProfile.MarginalRate(100000); r != 22 {
+		t.Errorf("expected a single filer at 100000 in the 22%% bracket, got %d%%", r)
+	}
+	if r := (TaxProfile{FilingStatus: calc.FilingMarriedJoint}).MarginalRate(100000); r != 12 {
+		t.Errorf("expected a joint filer at 100000 in the 12%% bracket, got %d%%", r)
+	}
+}
+
+func TestTaxProfileStateRate(t *testing.T) {
+	flat := TaxProfile{StateTaxRate: 4.95, StateCode: "IL"}
+	if r := flat.StateRate(100000); r != 4.95 {
+		t.Errorf("expected Illinois at its flat 4.95%%, got %g%%", r)
+	}
+	// 5,327.14 of bracketed tax on 100,000, well under the 13.3% top rate
+	graduated := TaxProfile{StateTaxRate: 13.3, StateCode: "CA"}
+	if r := graduated.StateRate(100000); r != 5.33 {
+		t.Errorf("expected California at an effective 5.33%%, got %g%%", r)
+	}
+}
@@ -0,0 +1,478 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/autolytiq/income-calculator/internal/calc"
+)
+
+// The /api/v1/ endpoints run the same calculations as the HTMX partials but
+// take a JSON body and return the calculator's result as JSON. Field names
+// match the calc package's json tags. Omitted fields take the same defaults
+// as a blank form input.
+
+// maxAPIBody caps the size of an API request body.
+const maxAPIBody = 1 << 20
+
+// API error codes.
+const (
+	errUnsupportedMediaType = "unsupported_media_type"
+	errBodyTooLarge         = "body_too_large"
+	errInvalidJSON          = "invalid_json"
+	errInvalidInput         = "invalid_input"
+	errCalculationFailed    = "calculation_failed"
+)
+
+// apiError is the body of every failed API response, under an "error" key.
+// Fields lists each invalid input when Code is errInvalidInput.
+type apiError struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []fieldError `json:"fields,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, e apiError) {
+	writeJSON(w, status, map[string]apiError{"error": e})
+}
+
+// decodeAPI reads a JSON request body into dst, which may hold defaults for
+// omitted fields. It writes an error response and returns false if the body
+// isn't a single JSON object of the expected shape.
+func decodeAPI(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		writeAPIError(w, http.StatusUnsupportedMediaType, apiError{
+			Code:    errUnsupportedMediaType,
+			Message: "Content-Type must be application/json",
+		})
+		return false
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIBody))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(dst)
+	if err == nil && dec.Decode(&struct{}{}) != io.EOF {
+		err = errors.New("body must contain a single JSON object")
+	}
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeAPIError(w, http.StatusRequestEntityTooLarge, apiError{
+			Code:    errBodyTooLarge,
+			Message: fmt.Sprintf("Request body can't be more than %d bytes", maxAPIBody),
+		})
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		writeAPIError(w, http.StatusBadRequest, apiError{
+			Code:    errInvalidJSON,
+			Message: "Request body has a field of the wrong type",
+			Fields:  []fieldError{{Field: typeErr.Field, Message: typeErr.Field + " must be " + jsonTypeName(typeErr.Type.Kind().String())}},
+		})
+	default:
+		writeAPIError(w, http.StatusBadRequest, apiError{
+			Code:    errInvalidJSON,
+			Message: "Invalid JSON: " + strings.TrimPrefix(err.Error(), "json: "),
+		})
+	}
+	return false
+}
+
+// jsonTypeName describes a Go kind the way a JSON client would.
+func jsonTypeName(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "float"), strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"):
+		return "a number"
+	case kind == "bool":
+		return "true or false"
+	case kind == "slice":
+		return "an array"
+	case kind == "struct", kind == "map", kind == "ptr":
+		return "an object"
+	}
+	return "a " + kind
+}
+
+// validAPI writes the validator's messages and returns false if any input
+// failed.
+func validAPI(w http.ResponseWriter, v *validator) bool {
+	if v.valid() {
+		return true
+	}
+	writeAPIError(w, http.StatusUnprocessableEntity, apiError{
+		Code:    errInvalidInput,
+		Message: "Some inputs are invalid",
+		Fields:  v.errors,
+	})
+	return false
+}
+
+// writeCalcError reports a calculation the calc package refused.
+func writeCalcError(w http.ResponseWriter, err error) {
+	writeAPIError(w, http.StatusUnprocessableEntity, apiError{
+		Code:    errCalculationFailed,
+		Message: err.Error(),
+	})
+}
+
+type apiIncomeRequest struct {
+	YTDIncome float64 `json:"ytd_income"`
+	StartDate string  `json:"start_date"` // YYYY-MM-DD
+	CheckDate string  `json:"check_date"` // date of the latest paystub
+}
+
+// APIIncome projects annual income from year-to-date pay, with the steps.
+func (h *Handler) APIIncome(w http.ResponseWriter, r *http.Request) {
+	var in apiIncomeRequest
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	v := &validator{}
+	v.checkMoney("ytd_income", "YTD income", in.YTDIncome, maxIncome)
+	v.check(in.YTDIncome > 0, "ytd_income", "Please enter a valid YTD income")
+	startDate := v.parseDate("start_date", "start date", in.StartDate)
+	checkDate := v.parseDate("check_date", "paystub date", in.CheckDate)
+	v.check(!checkDate.Before(startDate), "check_date", "Paystub date can't be before the start date")
+	if !validAPI(w, v) {
+		return
+	}
+
+	result, err := calc.ExplainIncome(in.YTDIncome, startDate, checkDate)
+	if err != nil {
+		writeCalcError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// APITaxes breaks down federal, state and FICA taxes, with the steps.
+func (h *Handler) APITaxes(w http.ResponseWriter, r *http.Request) {
+	var in calc.TaxInput
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	v := &validator{}
+	v.checkMoney("gross_annual", "Gross income", in.GrossAnnual, maxIncome)
+	v.check(in.GrossAnnual > 0, "gross_annual", "Please enter a valid gross income")
+	v.checkPercent("retirement_401k_percent", 0, "401(k) contribution", in.Retirement401kPercent, 0, 100)
+	v.checkMoney("health_insurance_annual", "Health insurance", in.HealthInsuranceAnnual, maxIncome)
+	v.checkPercent("state_tax_rate", 0, "State tax rate", in.StateTaxRate, 0, maxTaxRate)
+	v.check(in.FilingStatus == "" || in.FilingStatus.Valid(), "filing_status", "Please choose a valid filing status")
+	v.checkMoney("hsa", "HSA contribution", in.HSA, maxIncome)
+	v.checkMoney("health_fsa", "Health FSA contribution", in.HealthFSA, maxIncome)
+	v.checkMoney("dependent_care_fsa", "Dependent care FSA contribution", in.DependentCareFSA, maxIncome)
+	v.checkInteger("age", 0, "Age", in.Age, 0, 120)
+	v.checkSignedMoney("short_term_gains", "Short-term gains", in.ShortTermGains, maxAmount)
+	v.checkSignedMoney("long_term_gains", "Long-term gains", in.LongTermGains, maxAmount)
+	v.checkMoney("qualified_dividends", "Qualified dividends", in.QualifiedDividends, maxAmount)
+	v.checkPercent("state_ltcg_exclusion_pct", 0, "State long-term gains exclusion", in.StateLTCGExclusionPct, 0, 100)
+	v.checkMoney("self_employment_income", "Self-employment income", in.SelfEmploymentIncome, maxIncome)
+	v.checkSignedMoney("passive_income", "Passive income", in.PassiveIncome, maxIncome)
+	pretax := in.GrossAnnual*in.Retirement401kPercent/100 + in.HealthInsuranceAnnual + in.HSA + in.HealthFSA + in.DependentCareFSA
+	v.check(pretax <= in.GrossAnnual || in.GrossAnnual <= 0, "health_insurance_annual", "Pre-tax deductions can't be more than your gross income")
+	if !validAPI(w, v) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, calc.ExplainTaxes(in))
+}
+
+type apiMortgageRequest struct {
+	HomePrice       float64 `json:"home_price"`
+	DownPayment     float64 `json:"down_payment"`      // dollars
+	InterestRate    float64 `json:"interest_rate"`     // percent
+	TermYears       int     `json:"term_years"`        // defaults to 30
+	PropertyTaxRate float64 `json:"property_tax_rate"` // percent of the price a year; defaults to 1.1
+	AnnualInsurance float64 `json:"annual_insurance"`  // defaults to $1,200
+}
+
+// APIMortgage works out a mortgage's PITI payment and lifetime cost, with
+// the steps.
+func (h *Handler) APIMortgage(w http.ResponseWriter, r *http.Request) {
+	in := apiMortgageRequest{TermYears: 30, PropertyTaxRate: 1.1, AnnualInsurance: 1200}
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	v := &validator{}
+	v.checkMoney("home_price", "Home price", in.HomePrice, maxAmount)
+	v.check(in.HomePrice > 0, "home_price", "Please enter a valid home price")
+	v.checkMoney("down_payment", "Down payment", in.DownPayment, maxAmount)
+	v.check(in.DownPayment < in.HomePrice || in.HomePrice <= 0, "down_payment", "Down payment must be less than the home price")
+	v.checkPercent("interest_rate", 0, "Interest rate", in.InterestRate, 0, maxRate)
+	v.checkInteger("term_years", 0, "Loan term", in.TermYears, 1, 50)
+	v.checkPercent("property_tax_rate", 0, "Property tax rate", in.PropertyTaxRate, 0, 10)
+	v.checkMoney("annual_insurance", "Insurance", in.AnnualInsurance, maxAmount)
+	if !validAPI(w, v) {
+		return
+	}
+
+	downPaymentPct := in.DownPayment / in.HomePrice * 100
+	writeJSON(w, http.StatusOK, calc.ExplainMortgage(in.HomePrice, downPaymentPct, in.InterestRate, in.TermYears, in.PropertyTaxRate, in.AnnualInsurance))
+}
+
+// APIAuto works out an auto loan's payment and total cost.
+func (h *Handler) APIAuto(w http.ResponseWriter, r *http.Request) {
+	var in calc.AutoLoanInput
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	v := &validator{}
+	v.checkMoney("vehicle_price", "Vehicle price", in.VehiclePrice, maxAmount)
+	v.check(in.VehiclePrice > 0, "vehicle_price", "Please enter a valid vehicle price")
+	v.checkMoney("down_payment", "Down payment", in.DownPayment, maxAmount)
+	v.checkMoney("trade_in", "Trade-in value", in.TradeIn, maxAmount)
+	v.check(in.VehiclePrice-in.DownPayment-in.TradeIn > 0 || in.VehiclePrice <= 0, "down_payment", "Down payment and trade-in must be less than the vehicle price")
+	v.checkPercent("interest_rate", 0, "Interest rate", in.InterestRate, 0, maxRate)
+	v.checkInteger("term_months", 0, "Loan term", in.TermMonths, 0, maxLoanMonths)
+	v.checkMoney("monthly_income", "Monthly income", in.MonthlyIncome, maxIncome/12)
+	if !validAPI(w, v) {
+		return
+	}
+
+	result, err := calc.CalculateAutoLoan(in)
+	if err != nil {
+		writeCalcError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// apiBudgetSplit is a custom budget rule's percentages.
+type apiBudgetSplit struct {
+	Needs   float64 `json:"needs"`
+	Wants   float64 `json:"wants"`
+	Savings float64 `json:"savings"`
+}
+
+type apiBudgetRequest struct {
+	NetMonthly float64           `json:"net_monthly"`
+	Rule       string            `json:"rule"`     // a rule key, or "custom"; defaults to 50/30/20
+	Custom     *apiBudgetSplit   `json:"custom"`   // required for the custom rule
+	Expenses   []calc.BudgetLine `json:"expenses"` // line items for a zero-based budget
+}
+
+// APIBudget splits monthly income by a budget rule. With expenses it returns
+// a zero-based budget's variance against the rule instead.
+func (h *Handler) APIBudget(w http.ResponseWriter, r *http.Request) {
+	in := apiBudgetRequest{Rule: calc.DefaultBudgetRule}
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	v := &validator{}
+	v.checkMoney("net_monthly", "Monthly income", in.NetMonthly, maxIncome/12)
+	v.check(in.NetMonthly > 0, "net_monthly", "Please enter a valid monthly income")
+
+	var rule calc.BudgetRule
+	if in.Rule == "custom" {
+		v.check(in.Custom != nil, "custom", "Please enter needs, wants and savings percentages")
+		if in.Custom != nil {
+			v.checkPercent("custom.needs", 0, "Needs", in.Custom.Needs, 0, 100)
+			v.checkPercent("custom.wants", 0, "Wants", in.Custom.Wants, 0, 100)
+			v.checkPercent("custom.savings", 0, "Savings", in.Custom.Savings, 0, 100)
+			v.check(math.Abs(in.Custom.Needs+in.Custom.Wants+in.Custom.Savings-100) < 0.01, "custom", "Needs, wants and savings must add up to 100%")
+			rule = calc.CustomBudgetRule(in.Custom.Needs, in.Custom.Wants, in.Custom.Savings)
+		}
+	} else if named := calc.GetBudgetRule(in.Rule); named != nil {
+		rule = *named
+	} else {
+		v.check(false, "rule", "Please choose a budget rule")
+	}
+
+	for i, line := range in.Expenses {
+		field := fmt.Sprintf("expenses[%d].", i)
+		v.check(strings.TrimSpace(line.Name) != "", field+"name", "Please name the expense")
+		v.checkMoney(field+"amount", "Amount", line.Amount, maxIncome/12)
+		switch line.Class {
+		case "", calc.ClassNeed, calc.ClassWant, calc.ClassSavings:
+		default:
+			v.check(false, field+"class", "Class must be need, want or savings")
+		}
+	}
+	if !validAPI(w, v) {
+		return
+	}
+
+	if len(in.Expenses) > 0 {
+		zb, err := calc.CalculateZeroBasedBudget(in.NetMonthly, in.Expenses, rule)
+		if err != nil {
+			writeCalcError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, zb)
+		return
+	}
+	b, err := calc.AllocateBudget(in.NetMonthly, rule)
+	if err != nil {
+		writeCalcError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, b)
+}
+
+// APIRentVsBuy compares the net cost of buying and renting.
+func (h *Handler) APIRentVsBuy(w http.ResponseWriter, r *http.Request) {
+	in := calc.RentVsBuyInput{InflationRate: calc.DefaultInflationRate}
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	v := &validator{}
+	v.checkMoney("home_price", "Home price", in.HomePrice, maxAmount)
+	v.check(in.HomePrice > 0, "home_price", "Please enter a home price")
+	v.checkPercent("down_payment_pct", 0, "Down payment", in.DownPaymentPct, 0, 99)
+	v.checkPercent("mortgage_rate", 0, "Mortgage rate", in.MortgageRate, 0, maxRate)
+	v.checkPercent("appreciation", 0, "Home appreciation", in.Appreciation, -maxRate, maxRate)
+	v.checkMoney("monthly_rent", "Monthly rent", in.MonthlyRent, maxAmount)
+	v.check(in.MonthlyRent > 0, "monthly_rent", "Please enter your monthly rent")
+	v.checkPercent("rent_increase", 0, "Rent increase", in.RentIncrease, -maxRate, maxRate)
+	v.checkInteger("years", 0, "Years", in.Years, 1, maxYears)
+	v.checkPercent("inflation_rate", 0, "Inflation", in.InflationRate, 0, maxRate)
+	if !validAPI(w, v) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, calc.CompareRentVsBuy(in))
+}
+
+// APICompound projects compound growth of a lump sum and monthly
+// contributions.
+func (h *Handler) APICompound(w http.ResponseWriter, r *http.Request) {
+	in := calc.CompoundInput{InflationRate: calc.DefaultInflationRate}
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	v := &validator{}
+	v.checkMoney("principal", "Starting amount", in.Principal, maxAmount)
+	v.check(in.Principal > 0, "principal", "Please enter a starting amount")
+	v.checkMoney("monthly_contribution", "Monthly contribution", in.MonthlyContribution, maxIncome/12)
+	v.checkPercent("return_rate", 0, "Return rate", in.ReturnRate, -maxRate, maxRate)
+	v.checkInteger("years", 0, "Years", in.Years, 1, maxYears)
+	v.checkPercent("inflation_rate", 0, "Inflation", in.InflationRate, 0, maxRate)
+	if !validAPI(w, v) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, calc.CalculateCompound(in))
+}
+
+// APIInflation converts an amount between two dates with the CPI-U series
+// and projects it forward.
+func (h *Handler) APIInflation(w http.ResponseWriter, r *http.Request) {
+	var in calc.InflationInput
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	firstYear, latest := calc.CPIRange()
+	v := &validator{}
+	v.checkMoney("amount", "Amount", in.Amount, maxAmount)
+	v.check(in.Amount > 0, "amount", "Please enter a valid amount")
+	v.checkInteger("from.year", 0, "Starting year", in.From.Year, firstYear, latest.Year)
+	v.checkInteger("from.month", 0, "Starting month", in.From.Month, 0, 12)
+	v.checkInteger("to.year", 0, "Ending year", in.To.Year, firstYear, latest.Year)
+	v.checkInteger("to.month", 0, "Ending month", in.To.Month, 0, 12)
+	v.checkInteger("projection_years", 0, "Projection years", in.ProjectionYears, 0, maxYears)
+	v.checkPercent("projection_rate", 0, "Projected inflation", in.ProjectionRate, -10, maxRate)
+	if !validAPI(w, v) {
+		return
+	}
+
+	result, err := calc.AdjustForInflation(in)
+	if err != nil {
+		writeCalcError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+type apiGigRequest struct {
+	YTDIncome     float64 `json:"ytd_income"` // across every gig
+	MilesDriven   float64 `json:"miles_driven"`
+	OtherExpenses float64 `json:"other_expenses"`
+	StartDate     string  `json:"start_date"` // YYYY-MM-DD
+	CheckDate     string  `json:"check_date"`
+}
+
+// APIGig projects gig income and what's left after expenses and
+// self-employment tax.
+func (h *Handler) APIGig(w http.ResponseWriter, r *http.Request) {
+	var in apiGigRequest
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	v := &validator{}
+	v.checkMoney("ytd_income", "Income", in.YTDIncome, maxIncome)
+	v.check(in.YTDIncome > 0, "ytd_income", "Please enter your gig income")
+	v.checkQuantity("miles_driven", 0, "Miles driven", "", in.MilesDriven, 1_000_000)
+	v.checkMoney("other_expenses", "Other expenses", in.OtherExpenses, maxIncome)
+	startDate := v.parseDate("start_date", "start date", in.StartDate)
+	checkDate := v.parseDate("check_date", "as-of date", in.CheckDate)
+	v.check(!checkDate.Before(startDate), "check_date", "As-of date can't be before the start date")
+	if !validAPI(w, v) {
+		return
+	}
+
+	result, err := calc.CalculateGig(calc.GigInput{
+		YTDIncome:     in.YTDIncome,
+		MilesDriven:   in.MilesDriven,
+		OtherExpenses: in.OtherExpenses,
+		StartDate:     startDate,
+		CheckDate:     checkDate,
+	})
+	if err != nil {
+		writeCalcError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// APIStreams attributes tax across several income streams.
+func (h *Handler) APIStreams(w http.ResponseWriter, r *http.Request) {
+	var in calc.StreamsInput
+	if !decodeAPI(w, r, &in) {
+		return
+	}
+
+	v := &validator{}
+	v.check(len(in.Streams) > 0, "streams", "Please enter at least one income stream")
+	for i := range in.Streams {
+		s := &in.Streams[i]
+		field := fmt.Sprintf("streams[%d].", i)
+		v.checkMoney(field+"annual", "Income", s.Annual, maxIncome)
+		v.check(s.Annual > 0, field+"annual", "Please enter the stream's income")
+		v.check(s.Type.Valid(), field+"type", "Please choose a type")
+		v.checkMoney(field+"expenses", "Expenses", s.Expenses, maxIncome)
+		v.checkMoney(field+"depreciation", "Depreciation", s.Depreciation, maxIncome)
+		if s.Name == "" {
+			s.Name = s.Type.Label()
+		}
+	}
+	v.checkPercent("state_tax_rate", 0, "State tax rate", in.StateTaxRate, 0, maxTaxRate)
+	if !validAPI(w, v) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, calc.CalculateStreams(in))
+}
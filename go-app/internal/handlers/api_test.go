@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// postAPI sends body to an API handler as JSON and decodes the response.
+func postAPI(t *testing.T, handler http.HandlerFunc, body string) (int, map[string]json.RawMessage) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler(w, r)
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected a JSON response, got %q", ct)
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", w.Body.String(), err)
+	}
+	return w.Code, resp
+}
+
+// apiErrorOf decodes an API error response.
+func apiErrorOf(t *testing.T, resp map[string]json.RawMessage) apiError {
+	t.Helper()
+	var e apiError
+	if err := json.Unmarshal(resp["error"], &e); err != nil {
+		t.Fatalf("expected an error body, got %v", resp)
+	}
+	return e
+}
+
+func TestAPITaxes(t *testing.T) {
+	h := &Handler{}
+
+	status, resp := postAPI(t, h.APITaxes, `{"gross_annual": 100000, "filing_status": "married_joint", "state_code": "CA", "state_tax_rate": 13.3}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", status, resp)
+	}
+	for _, field := range []string{"net_annual", "federal_tax", "state_tax", "steps"} {
+		if _, ok := resp[field]; !ok {
+			t.Errorf("expected %s in the result, got %v", field, resp)
+		}
+	}
+
+	status, resp = postAPI(t, h.APITaxes, `{"gross_annual": -5, "filing_status": "widowed"}`)
+	if e := apiErrorOf(t, resp); status != http.StatusUnprocessableEntity || e.Code != errInvalidInput {
+		t.Fatalf("expected 422 %s, got %d %+v", errInvalidInput, status, e)
+	} else {
+		fields := map[string]bool{}
+		for _, f := range e.Fields {
+			fields[f.Field] = true
+		}
+		if !fields["gross_annual"] || !fields["filing_status"] {
+			t.Errorf("expected gross_annual and filing_status errors, got %+v", e.Fields)
+		}
+	}
+
+	status, resp = postAPI(t, h.APITaxes, `{"gross_annual": 100000, "gross": 1}`)
+	if e := apiErrorOf(t, resp); status != http.StatusBadRequest || e.Code != errInvalidJSON || !strings.Contains(e.Message, `"gross"`) {
+		t.Errorf("expected 400 %s naming the unknown field, got %d %+v", errInvalidJSON, status, e)
+	}
+}
+
+func TestAPIRentVsBuy(t *testing.T) {
+	h := &Handler{}
+	body := func(years string) string {
+		return `{"home_price": 400000, "down_payment_pct": 20, "mortgage_rate": 6.5, "monthly_rent": 2000, "years": ` + years + `}`
+	}
+
+	if status, resp := postAPI(t, h.APIRentVsBuy, body("10")); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", status, resp)
+	}
+
+	status, resp := postAPI(t, h.APIRentVsBuy, body("0"))
+	e := apiErrorOf(t, resp)
+	if status != http.StatusUnprocessableEntity || len(e.Fields) != 1 || e.Fields[0].Field != "years" {
+		t.Errorf("expected a years error for a zero-year horizon, got %d %+v", status, e)
+	}
+
+	status, resp = postAPI(t, h.APIRentVsBuy, `{"home_price": 400000, "monthly_rent": 2000, "years": 10, "horizon": 10}`)
+	if e := apiErrorOf(t, resp); status != http.StatusBadRequest || e.Code != errInvalidJSON {
+		t.Errorf("expected 400 %s for an unknown field, got %d %+v", errInvalidJSON, status, e)
+	}
+}
+
+func TestAPIRejectsForms(t *testing.T) {
+	h := &Handler{}
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/taxes", strings.NewReader("gross_annual=100000"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.APITaxes(w, r)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected 415 for a form body, got %d", w.Code)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"math"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -243,7 +244,12 @@ func (h *Handler) Taxes(w http.ResponseWriter, r *http.Request) {
 		Title:       "Federal & State Tax Calculator - Estimate Take-Home Pay | Autolytiq",
 		Description: "Estimate your take-home pay after federal, state, Social Security, and Medicare taxes. Free tax calculator with 2026 brackets and deductions.",
 		Canonical:   baseURL + "/taxes",
-	}, "taxes-content", map[string]interface{}{"NoTaxStates": noTaxStates, "TaxStates": taxStates})
+	}, "taxes-content", map[string]interface{}{
+		"NoTaxStates":   noTaxStates,
+		"TaxStates":     taxStates,
+		"FilingStatus":  string(taxProfile(r).FilingStatus),
+		"FilingOptions": filingOptions(),
+	})
 }
 
 func (h *Handler) StateTax(w http.ResponseWriter, r *http.Request) {
@@ -255,12 +261,8 @@ func (h *Handler) StateTax(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Estimate taxes on average salary
-	avgSalary := float64(s.AverageSalary)
-	// Rough federal estimate: ~12% effective rate for median incomes
-	estFederal := int(avgSalary * 0.12)
-	estState := int(avgSalary * s.TopRate / 100)
-	estFICA := int(avgSalary * 0.0765)
-	estNet := s.AverageSalary - estFederal - estState - estFICA
+	profile := stateTaxProfile(r, slug)
+	est := profile.Taxes(s.AverageSalary)
 
 	// Related states: mix of no-tax states (if this is no-tax) or similar-rate states
 	type relatedState struct {
@@ -306,7 +308,7 @@ func (h *Handler) StateTax(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	topRateStr := fmt.Sprintf("%.1f", s.TopRate)
+	topRateStr := fmt.Sprintf("%g", s.TopRate)
 	if !s.HasStateTax {
 		topRateStr = "0"
 	}
@@ -325,11 +327,16 @@ func (h *Handler) StateTax(w http.ResponseWriter, r *http.Request) {
 		"Description":        s.Description,
 		"Highlights":         s.Highlights,
 		"Cities":             s.MajorCities,
-		"EstFederalFormatted": formatMoney(estFederal),
-		"EstStateFormatted":   formatMoney(estState),
-		"EstFICAFormatted":    formatMoney(estFICA),
-		"EstNetFormatted":     formatMoney(estNet),
-		"EstMonthlyFormatted": formatMoney(estNet / 12),
+		"EstFederalFormatted": formatMoney(est.FederalTax.Dollars()),
+		"EstStateFormatted":   formatMoney(est.StateTax.Dollars()),
+		"EstStateRate":        fmt.Sprintf("%g", profile.StateRate(s.AverageSalary)),
+		"EstFICAFormatted":    formatMoney(est.FICATax.Dollars()),
+		"EstNetFormatted":     formatMoney(est.NetAnnual.Dollars()),
+		"EstMonthlyFormatted": formatMoney(est.NetMonthly.Dollars()),
+		"Graduated":           calc.HasStateBrackets(profile.StateCode),
+		"FilingStatus":        string(profile.FilingStatus),
+		"FilingLabel":         profile.FilingStatus.Label(),
+		"FilingOptions":       filingOptions(),
 		"RelatedStates":       related,
 	}
 
@@ -404,6 +411,8 @@ func (h *Handler) Afford(w http.ResponseWriter, r *http.Request) {
 		http.NotFound(w, r)
 		return
 	}
+	profile := taxProfile(r)
+	*d = data.CalculateAffordabilityFor(d.Salary, profile)
 
 	type relatedView struct {
 		Slug               string
@@ -452,6 +461,7 @@ func (h *Handler) Afford(w http.ResponseWriter, r *http.Request) {
 		"MaxMortgageFormatted": formatMoney(d.MaxMortgage),
 		"MaxCarFormatted":      formatMoney(d.MaxCar),
 		"EmergencyFundFormatted": formatMoney(d.EmergencyFund),
+		"EmergencyMonths":        d.EmergencyMonths,
 		"HourlyFormatted":      formatMoney(d.HourlyRate),
 		"WeeklyFormatted":      formatMoney(d.WeeklyPay),
 		"Related":              related,
@@ -459,6 +469,10 @@ func (h *Handler) Afford(w http.ResponseWriter, r *http.Request) {
 		"PrevDisplay":          prevDisplay,
 		"NextSlug":             nextSlug,
 		"NextDisplay":          nextDisplay,
+		"FilingStatus":         string(profile.FilingStatus),
+		"FilingLabel":          profile.FilingStatus.Label(),
+		"FilingOptions":        filingOptions(),
+		"StateRate":            fmt.Sprintf("%g", profile.StateRate(d.Salary)),
 	}
 
 	h.renderPage(w, PageMeta{
@@ -541,6 +555,7 @@ func (h *Handler) Salary(w http.ResponseWriter, r *http.Request) {
 	var afford *affordView
 	ad := data.GetAffordBySlug(data.ClosestAffordSlug(s.Median))
 	if ad != nil {
+		*ad = data.CalculateAffordabilityFor(ad.Salary, taxProfile(r))
 		afford = &affordView{
 			Slug:             ad.Slug,
 			MaxRentFormatted: formatMoney(ad.MaxRent),
@@ -549,6 +564,18 @@ func (h *Handler) Salary(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Starting points for the offer comparison: a median and a top-quartile offer
+	type offerDefault struct {
+		Name     string
+		Salary   string
+		Location string
+	}
+	offerDefaults := []offerDefault{
+		{Name: "Offer A", Salary: formatMoney(s.Median), Location: "austin-tx"},
+		{Name: "Offer B", Salary: formatMoney(s.Percentile75), Location: "san-francisco-ca"},
+	}
+	cities, states := locationOptions()
+
 	growthLabels := map[string]string{
 		"declining": "Declining", "stable": "Stable",
 		"growing": "Above Average", "fast": "Much Faster Than Average",
@@ -578,6 +605,9 @@ func (h *Handler) Salary(w http.ResponseWriter, r *http.Request) {
 		"TopStates":       topStates,
 		"RelatedJobs":     relatedJobs,
 		"Afford":          afford,
+		"OfferDefaults":   offerDefaults,
+		"Cities":          cities,
+		"States":          states,
 	}
 
 	h.renderPage(w, PageMeta{
@@ -632,6 +662,8 @@ func (h *Handler) Hourly(w http.ResponseWriter, r *http.Request) {
 		http.NotFound(w, r)
 		return
 	}
+	profile := taxProfile(r)
+	*d = data.CalculateHourlyFor(d.Rate, profile)
 
 	monthlyTax := d.TotalTaxes / 12
 	monthlyNet := d.MonthlyNet
@@ -714,23 +746,7 @@ func (h *Handler) Hourly(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Tax bracket info
-	taxable := d.Annual - 14600 // standard deduction
-	switch {
-	case taxable <= 11600:
-		taxBracket = "10%"
-	case taxable <= 47150:
-		taxBracket = "12%"
-	case taxable <= 100525:
-		taxBracket = "22%"
-	case taxable <= 191950:
-		taxBracket = "24%"
-	case taxable <= 243725:
-		taxBracket = "32%"
-	case taxable <= 609350:
-		taxBracket = "35%"
-	default:
-		taxBracket = "37%"
-	}
+	taxBracket = fmt.Sprintf("%d%%", profile.MarginalRate(d.Annual))
 
 	// Comparison to median
 	if d.Annual > medianUS {
@@ -779,11 +795,36 @@ func (h *Handler) Hourly(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	// Part-time / overtime variants (unique per rate)
-	partTime20 := rate * 20 * 52
-	partTime30 := rate * 30 * 52
-	overtime5 := (rate*40 + int(float64(rate)*1.5)*5) * 52
-	overtime10 := (rate*40 + int(float64(rate)*1.5)*10) * 52
+	// Part-time / overtime variants (unique per rate), taxed under the same
+	// profile as the full-time breakdown
+	type scheduleView struct {
+		Label               string
+		AnnualFormatted     string
+		TakeHomeFormatted   string
+		MonthlyNetFormatted string
+	}
+	var schedules []scheduleView
+	for _, v := range []struct {
+		label    string
+		schedule calc.WorkSchedule
+	}{
+		{"20 hours/week (part-time)", calc.WorkSchedule{HoursPerWeek: 20}},
+		{"30 hours/week", calc.WorkSchedule{HoursPerWeek: 30}},
+		{"40 hours/week with 2 weeks unpaid leave", calc.WorkSchedule{UnpaidTimeOffDays: 10}},
+		{"45 hours/week (5 hrs OT at 1.5x)", calc.WorkSchedule{HoursPerWeek: 45}},
+		{"50 hours/week (10 hrs OT at 1.5x)", calc.WorkSchedule{HoursPerWeek: 50}},
+	} {
+		p, err := calc.HourlyToSalary(float64(rate), v.schedule, profile.Input(0))
+		if err != nil {
+			continue
+		}
+		schedules = append(schedules, scheduleView{
+			Label:               v.label,
+			AnnualFormatted:     formatMoney(p.Gross.Annual.Dollars()),
+			TakeHomeFormatted:   formatMoney(p.Net.Annual.Dollars()),
+			MonthlyNetFormatted: formatMoney(p.Net.Monthly.Dollars()),
+		})
+	}
 
 	pageData := map[string]interface{}{
 		"Rate":                d.Rate,
@@ -814,10 +855,11 @@ func (h *Handler) Hourly(w http.ResponseWriter, r *http.Request) {
 		"VsMedian":            vsMedian,
 		"RaiseNote":           raiseNote,
 		"Comparisons":         comparisons,
-		"PartTime20":          formatMoney(partTime20),
-		"PartTime30":          formatMoney(partTime30),
-		"Overtime5":           formatMoney(overtime5),
-		"Overtime10":          formatMoney(overtime10),
+		"Schedules":           schedules,
+		"FilingStatus":        string(profile.FilingStatus),
+		"FilingLabel":         profile.FilingStatus.Label(),
+		"FilingOptions":       filingOptions(),
+		"StateRate":           fmt.Sprintf("%g", profile.StateRate(d.Annual)),
 	}
 
 	h.renderPage(w, PageMeta{
@@ -1035,93 +1077,59 @@ func (h *Handler) CalculateRentVsBuy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	homePrice, _ := strconv.ParseFloat(cleanMoney(r.FormValue("home_price")), 64)
-	downPct, _ := strconv.ParseFloat(r.FormValue("down_payment_pct"), 64)
-	mortgageRate, _ := strconv.ParseFloat(r.FormValue("mortgage_rate"), 64)
-	appreciation, _ := strconv.ParseFloat(r.FormValue("home_appreciation"), 64)
-	monthlyRent, _ := strconv.ParseFloat(cleanMoney(r.FormValue("monthly_rent")), 64)
-	rentIncrease, _ := strconv.ParseFloat(r.FormValue("rent_increase"), 64)
-	years, _ := strconv.Atoi(r.FormValue("years"))
-
-	if homePrice <= 0 || monthlyRent <= 0 {
-		h.renderError(w, "Please enter a valid home price and rent", http.StatusBadRequest)
-		return
-	}
-	if years <= 0 {
-		years = 5
-	}
-
-	// Buy calculations
-	downPayment := homePrice * downPct / 100
-	loanAmount := homePrice - downPayment
-	monthlyMortgage := calc.CalculateMonthlyPayment(loanAmount, mortgageRate, 360)
-	propertyTax := int(homePrice * 0.011 / 12)      // 1.1% annually
-	insurance := 100                                  // ~$1200/yr
-	maintenance := int(homePrice * 0.01 / 12)         // 1% annually
-	pmi := 0
-	if downPct < 20 {
-		pmi = int(loanAmount * 0.005 / 12) // ~0.5% PMI
-	}
-	buyMonthly := monthlyMortgage + propertyTax + insurance + maintenance + pmi
-	buyTotalPaid := buyMonthly * years * 12 + int(downPayment)
-
-	// Home value after appreciation
-	futureHomeValue := homePrice
-	for i := 0; i < years; i++ {
-		futureHomeValue *= (1 + appreciation/100)
-	}
-
-	// Simplified equity: down payment + appreciation gain + ~30% of mortgage payments go to principal in early years
-	principalPaid := int(float64(monthlyMortgage*years*12) * 0.30) // rough estimate
-	equity := int(downPayment) + int(futureHomeValue-homePrice) + principalPaid
-	buyNetCost := buyTotalPaid - equity
-
-	// Rent calculations
-	totalRent := 0
-	currentRent := monthlyRent
-	finalRent := monthlyRent
-	for y := 0; y < years; y++ {
-		totalRent += int(currentRent * 12)
-		finalRent = currentRent
-		currentRent *= (1 + rentIncrease/100)
-	}
-
-	// Investment returns: down payment invested + monthly savings invested
-	investReturn := 0.07 // 7% annual stock market return
-	savedDP := downPayment
-	for y := 0; y < years; y++ {
-		savedDP *= (1 + investReturn)
-	}
-	investmentReturns := int(savedDP - downPayment)
-	rentNetCost := totalRent - investmentReturns
+	v := newFormValidator(r)
+	homePrice := v.money("home_price", "Home price", maxAmount)
+	downPct := v.percent("down_payment_pct", "Down payment", 0, 99)
+	mortgageRate := v.percent("mortgage_rate", "Mortgage rate", 0, maxRate)
+	appreciation := v.percent("home_appreciation", "Home appreciation", -maxRate, maxRate)
+	monthlyRent := v.money("monthly_rent", "Monthly rent", maxAmount)
+	rentIncrease := v.percent("rent_increase", "Rent increase", -maxRate, maxRate)
+	years := v.requiredInteger("years", "Years", 1, maxYears)
+	inflation := inflationAssumption(v)
+
+	v.check(homePrice > 0, "home_price", "Please enter a home price")
+	v.check(monthlyRent > 0, "monthly_rent", "Please enter your monthly rent")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
+		return
+	}
+	rvb := calc.CompareRentVsBuy(calc.RentVsBuyInput{
+		HomePrice:      homePrice,
+		DownPaymentPct: downPct,
+		MortgageRate:   mortgageRate,
+		Appreciation:   appreciation,
+		MonthlyRent:    monthlyRent,
+		RentIncrease:   rentIncrease,
+		Years:          years,
+		InflationRate:  inflation,
+	})
 
-	buyWins := buyNetCost < rentNetCost
-	var savings int
-	if buyWins {
-		savings = rentNetCost - buyNetCost
-	} else {
-		savings = buyNetCost - rentNetCost
+	// The winner can differ in today's dollars, so report both
+	abs := func(n int) int {
+		if n < 0 {
+			return -n
+		}
+		return n
 	}
-
-	priceToRent := homePrice / (monthlyRent * 12)
-
 	result := map[string]interface{}{
-		"BuyWins":                    buyWins,
-		"SavingsFormatted":           formatMoney(savings),
-		"Years":                      years,
-		"BuyMonthlyFormatted":        formatMoney(buyMonthly),
-		"DownPaymentFormatted":       formatMoney(int(downPayment)),
-		"BuyTotalPaidFormatted":      formatMoney(buyTotalPaid),
-		"HomeValueFormatted":         formatMoney(int(futureHomeValue)),
-		"EquityFormatted":            formatMoney(equity),
-		"BuyNetCostFormatted":        formatMoney(buyNetCost),
-		"RentStartFormatted":         formatMoney(int(monthlyRent)),
-		"RentEndFormatted":           formatMoney(int(finalRent)),
-		"RentTotalFormatted":         formatMoney(totalRent),
-		"InvestmentReturnsFormatted": formatMoney(investmentReturns),
-		"RentNetCostFormatted":       formatMoney(rentNetCost),
-		"PriceToRent":                priceToRent,
-		"PriceToRentStr":             fmt.Sprintf("%.1f", priceToRent),
+		"BuyWins":              rvb.BuyAdvantage.Nominal > 0,
+		"BuyWinsReal":          rvb.BuyAdvantage.Real > 0,
+		"Savings":              dollarsView{Nominal: formatMoney(abs(rvb.BuyAdvantage.Nominal.Dollars())), Real: formatMoney(abs(rvb.BuyAdvantage.Real.Dollars()))},
+		"Years":                rvb.Years,
+		"InflationRate":        inflation,
+		"BuyMonthlyFormatted":  formatMoney(rvb.BuyMonthly.Dollars()),
+		"DownPaymentFormatted": formatMoney(rvb.DownPayment.Dollars()),
+		"BuyTotalPaid":         formatDollars(rvb.BuyTotalPaid),
+		"HomeValue":            formatDollars(rvb.HomeValue),
+		"Equity":               formatDollars(rvb.Equity),
+		"BuyNetCost":           formatDollars(rvb.BuyNetCost),
+		"RentStartFormatted":   formatMoney(rvb.RentStart.Dollars()),
+		"RentEnd":              formatDollars(rvb.RentEnd),
+		"RentTotal":            formatDollars(rvb.RentTotal),
+		"InvestmentReturns":    formatDollars(rvb.InvestmentReturns),
+		"RentNetCost":          formatDollars(rvb.RentNetCost),
+		"PriceToRent":          rvb.PriceToRent,
+		"PriceToRentStr":       fmt.Sprintf("%.1f", rvb.PriceToRent),
 	}
 	h.renderPartial(w, "rent-vs-buy-results", result)
 }
@@ -1371,7 +1379,7 @@ func (h *Handler) Sitemap(w http.ResponseWriter, r *http.Request) {
 	corePages := []string{
 		"/", "/calculator", "/smart-money", "/housing", "/auto",
 		"/gig-calculator", "/income-streams", "/taxes", "/free-tools",
-		"/quiz", "/rent-vs-buy", "/inflation", "/income-calculator",
+		"/quiz", "/rent-vs-buy", "/inflation", "/traditional-vs-roth", "/fire", "/capital-gains-tax", "/compare-cities", "/income-calculator",
 		"/desk", "/pricing", "/blog",
 		"/afford", "/salary", "/hourly", "/best", "/compare",
 	}
@@ -1409,6 +1417,10 @@ func (h *Handler) Sitemap(w http.ResponseWriter, r *http.Request) {
 	for _, slug := range data.AllVersusSlugs() {
 		writeURL("/compare/"+slug, "0.6", "monthly")
 	}
+	// Cost-of-living comparison pages
+	for _, p := range data.PopularCityPairs {
+		writeURL("/compare-cities/"+data.CityPairSlug(p[0], p[1]), "0.7", "monthly")
+	}
 	// Legal pages
 	writeURL("/privacy", "0.1", "yearly")
 	writeURL("/terms", "0.1", "yearly")
@@ -1952,67 +1964,364 @@ func formatMoney(n int) string {
 	return result.String()
 }
 
+// dollarsView is a projected amount formatted in both future and today's
+// dollars, rendered by the "dollars" template behind the real/nominal toggle.
+type dollarsView struct {
+	Nominal string
+	Real    string
+}
+
+func formatDollars(d calc.Dollars) dollarsView {
+	return dollarsView{Nominal: formatMoney(d.Nominal.Dollars()), Real: formatMoney(d.Real.Dollars())}
+}
+
+// mathStep is a calc.Step formatted for the "show-math" partial.
+type mathStep struct {
+	Label  string
+	Detail string
+	Amount string // with its sign and dollar sign, e.g. "-$15,000"
+}
+
+func showMath(steps []calc.Step) []mathStep {
+	var view []mathStep
+	for _, s := range steps {
+		amount := "$" + formatMoney(s.Amount.Dollars())
+		if s.Amount < 0 {
+			amount = "-$" + formatMoney(-s.Amount.Dollars())
+		}
+		view = append(view, mathStep{Label: s.Label, Detail: s.Detail, Amount: amount})
+	}
+	return view
+}
+
+// inflationAssumption reads the shared "inflation" form field used to show
+// projections in today's dollars, defaulting to calc.DefaultInflationRate.
+func inflationAssumption(v *validator) float64 {
+	if v.value("inflation", 0) == "" {
+		return calc.DefaultInflationRate
+	}
+	return v.percent("inflation", "Inflation", 0, maxRate)
+}
+
 func (h *Handler) CalculateIncome(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		h.renderError(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
 
-	// Parse YTD income
-	ytdIncome, err := strconv.ParseFloat(cleanMoney(r.FormValue("ytd_income")), 64)
-	if err != nil || ytdIncome <= 0 {
-		h.renderError(w, "Please enter a valid YTD income", http.StatusBadRequest)
+	v := newFormValidator(r)
+	ytdIncome := v.money("ytd_income", "YTD income", maxIncome)
+	startDate := v.date("start_date", "start date")
+	checkDate := v.date("check_date", "paystub date")
+
+	v.check(ytdIncome > 0, "ytd_income", "Please enter a valid YTD income")
+	v.check(!checkDate.Before(startDate), "check_date", "Paystub date can't be before the start date")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
 		return
 	}
 
-	// Parse dates
-	startDate, err := time.Parse("2006-01-02", r.FormValue("start_date"))
+	// Calculate
+	result, err := calc.ExplainIncome(ytdIncome, startDate, checkDate)
 	if err != nil {
-		h.renderError(w, "Please enter a valid start date", http.StatusBadRequest)
+		h.renderError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Render results
+	h.renderPartial(w, "income-results", map[string]interface{}{
+		"GrossAnnual":    result.GrossAnnual.Dollars(),
+		"GrossMonthly":   result.GrossMonthly.Dollars(),
+		"GrossWeekly":    result.GrossWeekly.Dollars(),
+		"GrossDaily":     result.GrossDaily.Dollars(),
+		"DaysWorked":     result.DaysWorked,
+		"MaxAutoPayment": result.MaxAutoPayment.Dollars(),
+		"MaxRent":        result.MaxRent.Dollars(),
+		"Math":           showMath(result.Steps),
+	})
+}
+
+func (h *Handler) CalculateBudget(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	v := newFormValidator(r)
+	netMonthly := v.money("monthly_income", "Monthly income", maxIncome/12)
+	v.check(netMonthly > 0, "monthly_income", "Please enter a valid monthly income")
+
+	var rule calc.BudgetRule
+	switch key := r.FormValue("rule"); key {
+	case "custom":
+		needs := v.percent("custom_needs", "Needs", 0, 100)
+		wants := v.percent("custom_wants", "Wants", 0, 100)
+		savings := v.percent("custom_savings", "Savings", 0, 100)
+		v.check(math.Abs(needs+wants+savings-100) < 0.01, "custom_savings", "Needs, wants and savings must add up to 100%")
+		rule = calc.CustomBudgetRule(needs, wants, savings)
+	case "":
+		rule = *calc.GetBudgetRule(calc.DefaultBudgetRule)
+	default:
+		named := calc.GetBudgetRule(key)
+		v.check(named != nil, "rule", "Please choose a budget rule")
+		if named != nil {
+			rule = *named
+		}
+	}
+
+	// User-defined subcategories replace the rule's for their category
+	subNames := r.Form["subcategory_name"]
+	subGroups := r.Form["subcategory_group"]
+	subPercents := r.Form["subcategory_percent"]
+	custom := map[string][]calc.BudgetSubcategoryRule{}
+	for i, name := range subNames {
+		name = strings.TrimSpace(name)
+		if name == "" || i >= len(subGroups) || i >= len(subPercents) {
+			continue
+		}
+		pct := v.percentAt("subcategory_percent", i, "Subcategory share", 0, 100)
+		custom[subGroups[i]] = append(custom[subGroups[i]], calc.BudgetSubcategoryRule{Name: name, Percent: pct})
+	}
+	if subs, ok := custom["needs"]; ok {
+		rule.Needs.Subcategories = subs
+	}
+	if subs, ok := custom["wants"]; ok {
+		rule.Wants.Subcategories = subs
+	}
+	if subs, ok := custom["savings"]; ok {
+		rule.Savings.Subcategories = subs
+	}
+
+	// Zero-based mode: line-item expenses get a variance report against the rule
+	expenseNames := r.Form["expense_name"]
+	expenseAmounts := r.Form["expense_amount"]
+	expenseClasses := r.Form["expense_class"]
+	var lines []calc.BudgetLine
+	for i, name := range expenseNames {
+		name = strings.TrimSpace(name)
+		if name == "" || i >= len(expenseAmounts) {
+			continue
+		}
+		amount := v.moneyAt("expense_amount", i, "Amount", maxIncome/12)
+		if amount <= 0 {
+			continue
+		}
+		line := calc.BudgetLine{Name: name, Amount: amount}
+		if i < len(expenseClasses) {
+			switch class := calc.ExpenseClass(expenseClasses[i]); class {
+			case calc.ClassNeed, calc.ClassWant, calc.ClassSavings:
+				line.Class = class
+			}
+		}
+		lines = append(lines, line)
+	}
+	zeroBased := r.FormValue("mode") == "zero_based" || len(lines) > 0
+	if zeroBased {
+		v.check(len(lines) > 0, "expense_amount", "Please enter at least one expense")
+	}
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
+		return
+	}
+	if zeroBased {
+		h.renderBudgetVariance(w, netMonthly, lines, rule)
 		return
 	}
 
-	checkDate, err := time.Parse("2006-01-02", r.FormValue("check_date"))
+	b, err := calc.AllocateBudget(netMonthly, rule)
 	if err != nil {
-		h.renderError(w, "Please enter a valid paystub date", http.StatusBadRequest)
+		h.renderError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	annualSavings := b.Savings.Monthly * 12
 
-	// Calculate
-	result, err := calc.CalculateIncome(ytdIncome, startDate, checkDate)
+	type subcategoryView struct {
+		Name             string
+		Percent          int
+		MonthlyFormatted string
+	}
+	type categoryView struct {
+		Name             string
+		Percent          int
+		MonthlyFormatted string
+		Subcategories    []subcategoryView
+	}
+	categoryViewOf := func(c calc.BudgetCategory) categoryView {
+		v := categoryView{Name: c.Name, Percent: c.Percent, MonthlyFormatted: formatMoney(c.Monthly.Dollars())}
+		for _, sub := range c.Subcategories {
+			v.Subcategories = append(v.Subcategories, subcategoryView{
+				Name:             sub.Name,
+				Percent:          sub.Percent,
+				MonthlyFormatted: formatMoney(sub.Monthly.Dollars()),
+			})
+		}
+		return v
+	}
+
+	result := map[string]interface{}{
+		"RuleName":                 rule.Name,
+		"MonthlyIncomeFormatted":   formatMoney(b.NetMonthly.Dollars()),
+		"Needs":                    categoryViewOf(b.Needs),
+		"Wants":                    categoryViewOf(b.Wants),
+		"Savings":                  categoryViewOf(b.Savings),
+		"NeedsFormatted":           formatMoney(b.Needs.Monthly.Dollars()),
+		"WantsFormatted":           formatMoney(b.Wants.Monthly.Dollars()),
+		"SavingsFormatted":         formatMoney(b.Savings.Monthly.Dollars()),
+		"AnnualSavingsFormatted":   formatMoney(annualSavings.Dollars()),
+		"FiveYearSavingsFormatted": formatMoney((annualSavings * 5).Dollars()),
+	}
+	h.renderPartial(w, "budget-results", result)
+}
+
+// renderBudgetVariance renders a zero-based budget's variance report.
+func (h *Handler) renderBudgetVariance(w http.ResponseWriter, netMonthly float64, lines []calc.BudgetLine, rule calc.BudgetRule) {
+	zb, err := calc.CalculateZeroBasedBudget(netMonthly, lines, rule)
 	if err != nil {
 		h.renderError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Render results
-	h.renderPartial(w, "income-results", result)
+	classLabels := map[calc.ExpenseClass]string{
+		calc.ClassNeed:    rule.Needs.Name,
+		calc.ClassWant:    rule.Wants.Name,
+		calc.ClassSavings: rule.Savings.Name,
+	}
+	if rule.Wants.Percent == 0 {
+		classLabels[calc.ClassWant] = rule.Needs.Name
+	}
+
+	type lineView struct {
+		Name            string
+		Class           string
+		ClassLabel      string
+		AmountFormatted string
+		AutoClassified  bool
+	}
+	type varianceView struct {
+		Name              string
+		Class             string
+		PlannedPercent    int
+		ActualPercent     float64
+		BarWidth          float64
+		PlannedFormatted  string
+		ActualFormatted   string
+		VarianceFormatted string
+		Under             bool
+		OverAllocated     bool
+	}
+
+	var lineViews []lineView
+	for _, l := range zb.Lines {
+		lineViews = append(lineViews, lineView{
+			Name:            l.Name,
+			Class:           string(l.Class),
+			ClassLabel:      classLabels[l.Class],
+			AmountFormatted: formatMoney(l.Amount.Dollars()),
+			AutoClassified:  l.AutoClassified,
+		})
+	}
+	var categoryViews []varianceView
+	for _, c := range zb.Categories {
+		variance := c.Variance
+		if variance < 0 {
+			variance = -variance
+		}
+		categoryViews = append(categoryViews, varianceView{
+			Name:              c.Name,
+			Class:             string(c.Class),
+			PlannedPercent:    c.PlannedPercent,
+			ActualPercent:     c.ActualPercent,
+			BarWidth:          math.Min(100, c.ActualPercent),
+			PlannedFormatted:  formatMoney(c.Planned.Dollars()),
+			ActualFormatted:   formatMoney(c.Actual.Dollars()),
+			VarianceFormatted: formatMoney(variance.Dollars()),
+			Under:             c.Variance >= 0,
+			OverAllocated:     c.OverAllocated,
+		})
+	}
+	unassigned := zb.Unassigned
+	if unassigned < 0 {
+		unassigned = -unassigned
+	}
+
+	h.renderPartial(w, "budget-variance-results", map[string]interface{}{
+		"RuleName":               rule.Name,
+		"MonthlyIncomeFormatted": formatMoney(zb.NetMonthly.Dollars()),
+		"TotalAssignedFormatted": formatMoney(zb.TotalAssigned.Dollars()),
+		"UnassignedFormatted":    formatMoney(unassigned.Dollars()),
+		"OverAssigned":           zb.Unassigned < 0,
+		"Balanced":               zb.Balanced,
+		"Lines":                  lineViews,
+		"Categories":             categoryViews,
+		"OverAllocated":          strings.Join(zb.OverAllocated, " and "),
+	})
 }
 
-func (h *Handler) CalculateBudget(w http.ResponseWriter, r *http.Request) {
+// CalculateEmergencyFund sizes an emergency fund from essential expenses and
+// household risk, and projects how long it takes to save.
+func (h *Handler) CalculateEmergencyFund(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		h.renderError(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
 
-	netMonthly, _ := strconv.ParseFloat(cleanMoney(r.FormValue("monthly_income")), 64)
-	if netMonthly <= 0 {
-		h.renderError(w, "Please enter a valid monthly income", http.StatusBadRequest)
+	v := newFormValidator(r)
+	essential := v.money("essential_expenses", "Essential expenses", maxIncome/12)
+	current := v.money("current_savings", "Current savings", maxAmount)
+	monthlySavings := v.money("monthly_savings", "Monthly savings", maxIncome/12)
+	apy := v.percent("apy", "APY", 0, maxRate)
+	dependents := v.integer("dependents", "Dependents", 0, 20)
+
+	v.check(essential > 0, "essential_expenses", "Please enter your essential monthly expenses")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
 		return
 	}
 
-	b := calc.CalculateBudgetAllocation(netMonthly)
-	annualSavings := b.Savings.Monthly * 12
+	stability := calc.IncomeStability(r.FormValue("income_stability"))
+	var stabilityLabel string
+	switch stability {
+	case calc.IncomeCommission:
+		stabilityLabel = "commission or bonus-heavy income"
+	case calc.IncomeSelfEmploy:
+		stabilityLabel = "self-employed (1099) income"
+	default:
+		stability = calc.IncomeSalaried
+		stabilityLabel = "steady W-2 salary"
+	}
+	secondEarner := r.FormValue("second_earner") == "on"
+
+	e, err := calc.CalculateEmergencyFund(calc.EmergencyFundInput{
+		EssentialMonthly: essential,
+		Stability:        stability,
+		Dependents:       dependents,
+		SecondEarner:     secondEarner,
+		CurrentSavings:   current,
+		MonthlySavings:   monthlySavings,
+		APY:              apy,
+	})
+	if err != nil {
+		h.renderError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	result := map[string]interface{}{
-		"MonthlyIncomeFormatted":   formatMoney(b.NetMonthly),
-		"NeedsFormatted":           formatMoney(b.Needs.Monthly),
-		"WantsFormatted":           formatMoney(b.Wants.Monthly),
-		"SavingsFormatted":         formatMoney(b.Savings.Monthly),
-		"AnnualSavingsFormatted":   formatMoney(annualSavings),
-		"FiveYearSavingsFormatted": formatMoney(annualSavings * 5),
-	}
-	h.renderPartial(w, "budget-results", result)
+		"TargetMonths":            e.TargetMonths,
+		"TargetFormatted":         formatMoney(e.Target.Dollars()),
+		"RemainingFormatted":      formatMoney(e.Remaining.Dollars()),
+		"PercentFunded":           e.PercentFunded,
+		"MonthsToTarget":          e.MonthsToTarget,
+		"YearsToTarget":           math.Round(float64(e.MonthsToTarget)/12*10) / 10,
+		"MonthsToOne":             e.MonthsToOne,
+		"Reachable":               e.Reachable,
+		"Funded":                  e.Remaining == 0,
+		"InterestEarnedFormatted": formatMoney(e.InterestEarned.Dollars()),
+		"DepositsFormatted":       formatMoney(e.TotalDeposits.Dollars()),
+		"StabilityLabel":          stabilityLabel,
+		"Dependents":              dependents,
+		"SecondEarner":            secondEarner,
+	}
+	h.renderPartial(w, "emergency-fund-results", result)
 }
 
 func (h *Handler) CalculateMortgage(w http.ResponseWriter, r *http.Request) {
@@ -2021,16 +2330,19 @@ func (h *Handler) CalculateMortgage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	homePrice, _ := strconv.ParseFloat(cleanMoney(r.FormValue("home_price")), 64)
-	downPaymentDollars, _ := strconv.ParseFloat(cleanMoney(r.FormValue("down_payment")), 64)
-	interestRate, _ := strconv.ParseFloat(r.FormValue("interest_rate"), 64)
-	termYears, _ := strconv.Atoi(r.FormValue("loan_term"))
-	propertyTaxRate, _ := strconv.ParseFloat(r.FormValue("property_tax_rate"), 64)
-	annualInsurance, _ := strconv.ParseFloat(cleanMoney(r.FormValue("annual_insurance")), 64)
-	annualIncome, _ := strconv.ParseFloat(cleanMoney(r.FormValue("annual_income")), 64)
+	v := newFormValidator(r)
+	homePrice := v.money("home_price", "Home price", maxAmount)
+	downPaymentDollars := v.money("down_payment", "Down payment", maxAmount)
+	interestRate := v.percent("interest_rate", "Interest rate", 0, maxRate)
+	termYears := v.requiredInteger("loan_term", "Loan term", 1, 50)
+	propertyTaxRate := v.percent("property_tax_rate", "Property tax rate", 0, 10)
+	annualInsurance := v.money("annual_insurance", "Insurance", maxAmount)
+	annualIncome := v.money("annual_income", "Annual income", maxIncome)
 
-	if homePrice <= 0 {
-		h.renderError(w, "Please enter a valid home price", http.StatusBadRequest)
+	v.check(homePrice > 0, "home_price", "Please enter a valid home price")
+	v.check(downPaymentDollars < homePrice || homePrice <= 0, "down_payment", "Down payment must be less than the home price")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
 		return
 	}
 
@@ -2041,9 +2353,6 @@ func (h *Handler) CalculateMortgage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Defaults
-	if termYears == 0 {
-		termYears = 30
-	}
 	if propertyTaxRate == 0 {
 		propertyTaxRate = 1.1
 	}
@@ -2051,35 +2360,36 @@ func (h *Handler) CalculateMortgage(w http.ResponseWriter, r *http.Request) {
 		annualInsurance = 1200
 	}
 
-	m := calc.CalculateMortgage(homePrice, downPaymentPct, interestRate, termYears, propertyTaxRate, annualInsurance)
+	m := calc.ExplainMortgage(homePrice, downPaymentPct, interestRate, termYears, propertyTaxRate, annualInsurance)
 
 	// Affordability ratios (use annual income if provided, else assume not affordable)
 	var housingRatio, dtiRatio float64
 	affordable := false
 	if annualIncome > 0 {
 		monthlyIncome := annualIncome / 12
-		housingRatio = float64(m.PITI.TotalMonthly) / monthlyIncome * 100
+		housingRatio = m.PITI.TotalMonthly.Float() / monthlyIncome * 100
 		dtiRatio = housingRatio // simplified: only housing debt
 		affordable = housingRatio <= 28
 	}
 
 	result := map[string]interface{}{
 		"Affordable":                affordable,
-		"MonthlyPaymentFormatted":   formatMoney(m.PITI.TotalMonthly),
-		"PrincipalInterestFormatted": formatMoney(m.PITI.PrincipalInterest),
-		"TaxesFormatted":            formatMoney(m.PITI.PropertyTax),
-		"InsuranceFormatted":        formatMoney(m.PITI.Insurance),
+		"MonthlyPaymentFormatted":   formatMoney(m.PITI.TotalMonthly.Dollars()),
+		"PrincipalInterestFormatted": formatMoney(m.PITI.PrincipalInterest.Dollars()),
+		"TaxesFormatted":            formatMoney(m.PITI.PropertyTax.Dollars()),
+		"InsuranceFormatted":        formatMoney(m.PITI.Insurance.Dollars()),
 		"HasPMI":                    m.PITI.PMI > 0,
-		"PMIFormatted":              formatMoney(m.PITI.PMI),
+		"PMIFormatted":              formatMoney(m.PITI.PMI.Dollars()),
 		"HomePriceFormatted":        formatMoney(int(homePrice)),
-		"DownPaymentFormatted":      formatMoney(m.DownPayment),
+		"DownPaymentFormatted":      formatMoney(m.DownPayment.Dollars()),
 		"DownPaymentPercent":        fmt.Sprintf("%.0f", downPaymentPct),
-		"LoanAmountFormatted":       formatMoney(m.LoanAmount),
-		"TotalPaymentsFormatted":    formatMoney(m.TotalPayments),
-		"TotalInterestFormatted":    formatMoney(m.TotalInterest),
+		"LoanAmountFormatted":       formatMoney(m.LoanAmount.Dollars()),
+		"TotalPaymentsFormatted":    formatMoney(m.TotalPayments.Dollars()),
+		"TotalInterestFormatted":    formatMoney(m.TotalInterest.Dollars()),
 		"InterestRate":              interestRate,
 		"HousingRatio":              math.Round(housingRatio*10) / 10,
 		"DTIRatio":                  math.Round(dtiRatio*10) / 10,
+		"Math":                      showMath(m.Steps),
 	}
 	h.renderPartial(w, "mortgage-results", result)
 }
@@ -2090,62 +2400,50 @@ func (h *Handler) CalculateAuto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	vehiclePrice, _ := strconv.ParseFloat(cleanMoney(r.FormValue("vehicle_price")), 64)
-	downPayment, _ := strconv.ParseFloat(cleanMoney(r.FormValue("down_payment")), 64)
-	tradeIn, _ := strconv.ParseFloat(cleanMoney(r.FormValue("trade_in")), 64)
-	interestRate, _ := strconv.ParseFloat(r.FormValue("interest_rate"), 64)
-	termMonths, _ := strconv.Atoi(r.FormValue("loan_term"))
-	monthlyIncome, _ := strconv.ParseFloat(cleanMoney(r.FormValue("monthly_income")), 64)
-	annualIncome := monthlyIncome * 12
-
-	if vehiclePrice <= 0 {
-		h.renderError(w, "Please enter a valid vehicle price", http.StatusBadRequest)
-		return
-	}
-
-	// Defaults
-	if termMonths == 0 {
-		termMonths = 60
-	}
+	v := newFormValidator(r)
+	vehiclePrice := v.money("vehicle_price", "Vehicle price", maxAmount)
+	downPayment := v.money("down_payment", "Down payment", maxAmount)
+	tradeIn := v.money("trade_in", "Trade-in value", maxAmount)
+	interestRate := v.percent("interest_rate", "Interest rate", 0, maxRate)
+	termMonths := v.requiredInteger("loan_term", "Loan term", 1, maxLoanMonths)
+	monthlyIncome := v.money("monthly_income", "Monthly income", maxIncome/12)
 
 	loanAmount := vehiclePrice - downPayment - tradeIn
-	if loanAmount <= 0 {
-		h.renderError(w, "Loan amount must be positive", http.StatusBadRequest)
+	v.check(vehiclePrice > 0, "vehicle_price", "Please enter a valid vehicle price")
+	v.check(loanAmount > 0 || vehiclePrice <= 0, "down_payment", "Down payment and trade-in must be less than the vehicle price")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
 		return
 	}
 
-	monthlyPayment := calc.CalculateMonthlyPayment(loanAmount, interestRate, termMonths)
-	totalPayments := monthlyPayment * termMonths
-	totalInterest := totalPayments - int(loanAmount)
-	trueCost := int(vehiclePrice) + totalInterest
-
-	// 12% rule: max auto payment is 12% of gross monthly income
-	var maxPayment int
-	var paymentPercent float64
-	affordable := false
-	if annualIncome > 0 {
-		maxPayment = int(annualIncome / 12 * 0.12)
-		if maxPayment > 0 {
-			paymentPercent = float64(monthlyPayment) / float64(maxPayment) * 100
-		}
-		affordable = monthlyPayment <= maxPayment
+	a, err := calc.CalculateAutoLoan(calc.AutoLoanInput{
+		VehiclePrice:  vehiclePrice,
+		DownPayment:   downPayment,
+		TradeIn:       tradeIn,
+		InterestRate:  interestRate,
+		TermMonths:    termMonths,
+		MonthlyIncome: monthlyIncome,
+	})
+	if err != nil {
+		h.renderError(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	result := map[string]interface{}{
-		"Affordable":              affordable,
-		"MonthlyPaymentFormatted": formatMoney(monthlyPayment),
-		"MaxPaymentFormatted":     formatMoney(maxPayment),
-		"PaymentPercent":          math.Round(paymentPercent*10) / 10,
-		"LoanAmountFormatted":     formatMoney(int(loanAmount)),
+		"Affordable":              a.Affordable,
+		"MonthlyPaymentFormatted": formatMoney(a.MonthlyPayment.Dollars()),
+		"MaxPaymentFormatted":     formatMoney(a.MaxPayment.Dollars()),
+		"PaymentPercent":          a.PaymentPercent,
+		"LoanAmountFormatted":     formatMoney(a.LoanAmount.Dollars()),
 		"InterestRate":            interestRate,
-		"LoanTermMonths":          termMonths,
+		"LoanTermMonths":          a.TermMonths,
 		"VehiclePriceFormatted":   formatMoney(int(vehiclePrice)),
 		"DownPaymentFormatted":    formatMoney(int(downPayment)),
 		"TradeInValue":            int(tradeIn),
 		"TradeInFormatted":        formatMoney(int(tradeIn)),
-		"TotalPaymentsFormatted":  formatMoney(totalPayments),
-		"TotalInterestFormatted":  formatMoney(totalInterest),
-		"TrueCostFormatted":       formatMoney(trueCost),
+		"TotalPaymentsFormatted":  formatMoney(a.TotalPayments.Dollars()),
+		"TotalInterestFormatted":  formatMoney(a.TotalInterest.Dollars()),
+		"TrueCostFormatted":       formatMoney(a.TrueCost.Dollars()),
 	}
 	h.renderPartial(w, "auto-results", result)
 }
@@ -2156,21 +2454,23 @@ func (h *Handler) CalculateTaxes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	grossAnnual, _ := strconv.ParseFloat(cleanMoney(r.FormValue("gross_annual")), 64)
-	retirement401kPct, _ := strconv.ParseFloat(r.FormValue("retirement_pct"), 64)
-	healthInsurance, _ := strconv.ParseFloat(cleanMoney(r.FormValue("health_insurance")), 64)
-	stateTaxRate, _ := strconv.ParseFloat(r.FormValue("state_tax_rate"), 64)
-
-	if grossAnnual <= 0 {
-		h.renderError(w, "Please enter a valid gross income", http.StatusBadRequest)
+	v := newFormValidator(r)
+	in := taxFormInput(r, v)
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
 		return
 	}
 
-	t := calc.CalculateTaxes(grossAnnual, retirement401kPct, healthInsurance, stateTaxRate)
+	t := calc.ExplainTaxes(in)
+
+	stateLabel := fmt.Sprintf("%.1f%%", in.StateTaxRate)
+	if calc.HasStateBrackets(in.StateCode) {
+		stateLabel = in.StateCode + " brackets"
+	}
 
 	totalTaxes := t.FederalTax + t.StateTax + t.SocialSecurity + t.Medicare
-	biweeklyNet := t.NetAnnual / 26
-	weeklyNet := t.NetAnnual / 52
+	biweeklyNet := t.NetAnnual.Div(26)
+	weeklyNet := t.NetAnnual.Div(52)
 	takeHomeRate := 100.0 - t.EffectiveTaxRate
 
 	// Calculate tax percentages relative to gross for progress bars
@@ -2183,33 +2483,94 @@ func (h *Handler) CalculateTaxes(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result := map[string]interface{}{
-		"NetIncomeFormatted":       formatMoney(t.NetAnnual),
-		"MonthlyNetFormatted":      formatMoney(t.NetMonthly),
-		"BiweeklyNetFormatted":     formatMoney(biweeklyNet),
-		"WeeklyNetFormatted":       formatMoney(weeklyNet),
-		"GrossIncomeFormatted":     formatMoney(t.GrossAnnual),
-		"TotalTaxesFormatted":      formatMoney(totalTaxes),
-		"FederalTaxFormatted":      formatMoney(t.FederalTax),
+		"NetIncomeFormatted":       formatMoney(t.NetAnnual.Dollars()),
+		"MonthlyNetFormatted":      formatMoney(t.NetMonthly.Dollars()),
+		"BiweeklyNetFormatted":     formatMoney(biweeklyNet.Dollars()),
+		"WeeklyNetFormatted":       formatMoney(weeklyNet.Dollars()),
+		"GrossIncomeFormatted":     formatMoney(t.GrossAnnual.Dollars()),
+		"TotalTaxesFormatted":      formatMoney(totalTaxes.Dollars()),
+		"FederalTaxFormatted":      formatMoney(t.FederalTax.Dollars()),
 		"FederalTaxPercent":        math.Round(fedPct*10) / 10,
-		"State":                    fmt.Sprintf("%.1f%%", stateTaxRate),
-		"StateTaxFormatted":        formatMoney(t.StateTax),
+		"State":                    stateLabel,
+		"FilingLabel":              in.FilingStatus.Label(),
+		"StateTaxFormatted":        formatMoney(t.StateTax.Dollars()),
 		"StateTaxPercent":          math.Round(statePct*10) / 10,
-		"SocialSecurityFormatted":  formatMoney(t.SocialSecurity),
+		"SocialSecurityFormatted":  formatMoney(t.SocialSecurity.Dollars()),
 		"SocialSecurityPercent":    math.Round(ssPct*10) / 10,
-		"MedicareFormatted":        formatMoney(t.Medicare),
+		"MedicareFormatted":        formatMoney(t.Medicare.Dollars()),
 		"MedicarePercent":          math.Round(medPct*10) / 10,
 		"EffectiveRate":            t.EffectiveTaxRate,
 		"TakeHomeRate":             math.Round(takeHomeRate*10) / 10,
+		"HasBenefits":              t.HSA+t.HealthFSA+t.DependentCareFSA > 0,
+		"HSAFormatted":             formatMoney(t.HSA.Dollars()),
+		"HealthFSAFormatted":       formatMoney(t.HealthFSA.Dollars()),
+		"DependentCareFormatted":   formatMoney(t.DependentCareFSA.Dollars()),
+		"TaxSavedFormatted":        formatMoney(t.BenefitsTaxSaved.Dollars()),
+		"PaycheckChangeFormatted":  formatMoney(-t.BenefitsPaycheckChange.Dollars()),
+		"Math":                     showMath(t.Steps),
 	}
 	h.renderPartial(w, "tax-results", result)
 }
 
+// taxFormInput parses the tax calculator form. state_code is optional and,
+// for a state with graduated brackets, taxes income on those brackets
+// instead of state_tax_rate, the way the state pages compute their figures.
+func taxFormInput(r *http.Request, v *validator) calc.TaxInput {
+	grossAnnual := v.money("gross_annual", "Gross income", maxIncome)
+	retirement401kPct := v.percent("retirement_pct", "401(k) contribution", 0, 100)
+	healthInsurance := v.money("health_insurance", "Health insurance", maxIncome)
+	stateTaxRate := v.percent("state_tax_rate", "State tax rate", 0, maxTaxRate)
+	stateCode := strings.ToUpper(v.value("state_code", 0))
+	hsa := v.money("hsa", "HSA contribution", maxIncome)
+	healthFSA := v.money("health_fsa", "Health FSA contribution", maxIncome)
+	dependentCareFSA := v.money("dependent_care_fsa", "Dependent care FSA contribution", maxIncome)
+	age := v.integer("age", "Age", 0, 120)
+	filingStatus := calc.FilingStatus(r.FormValue("filing_status"))
+
+	v.check(grossAnnual > 0, "gross_annual", "Please enter a valid gross income")
+	v.check(filingStatus == "" || filingStatus.Valid(), "filing_status", "Please choose a valid filing status")
+	v.check(stateCode == "" || len(stateCode) == 2, "state_code", "Please choose a valid state")
+	pretax := grossAnnual*retirement401kPct/100 + healthInsurance + hsa + healthFSA + dependentCareFSA
+	v.check(pretax <= grossAnnual || grossAnnual <= 0, "health_insurance", "Pre-tax deductions can't be more than your gross income")
+
+	return calc.TaxInput{
+		GrossAnnual:           grossAnnual,
+		Retirement401kPercent: retirement401kPct,
+		HealthInsuranceAnnual: healthInsurance,
+		StateTaxRate:          stateTaxRate,
+		StateCode:             stateCode,
+		FilingStatus:          filingStatus,
+		HSA:                   hsa,
+		HSAFamily:             r.FormValue("hsa_family") != "",
+		HSAOutsidePayroll:     r.FormValue("hsa_outside_payroll") != "",
+		HealthFSA:             healthFSA,
+		DependentCareFSA:      dependentCareFSA,
+		Age:                   age,
+	}
+}
+
 func (h *Handler) Inflation(w http.ResponseWriter, r *http.Request) {
 	h.renderPage(w, PageMeta{
 		Title:       "Inflation & Compound Interest Calculator | Autolytiq",
-		Description: "Calculate how inflation erodes your purchasing power and how compound interest grows your wealth over time. Free financial calculator.",
+		Description: "See what a dollar from any year since 1913 is worth today using official CPI data, project future inflation, and calculate how compound interest grows your wealth.",
 		Canonical:   baseURL + "/inflation",
-	}, "inflation-content", nil)
+	}, "inflation-content", inflationPageData())
+}
+
+// inflationPageData lists the CPI years, newest first, for the historical
+// inflation form.
+func inflationPageData() map[string]interface{} {
+	years := calc.CPIYears()
+	for i, j := 0, len(years)-1; i < j; i, j = i+1, j-1 {
+		years[i], years[j] = years[j], years[i]
+	}
+	first, latest := calc.CPIRange()
+	return map[string]interface{}{
+		"CPIYears":    years,
+		"FirstYear":   first,
+		"LatestYear":  years[0],
+		"LatestMonth": latest.String(),
+	}
 }
 
 func (h *Handler) CalculateInflation(w http.ResponseWriter, r *http.Request) {
@@ -2217,11 +2578,17 @@ func (h *Handler) CalculateInflation(w http.ResponseWriter, r *http.Request) {
 		h.renderError(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
-	amount, _ := strconv.ParseFloat(cleanMoney(r.FormValue("amount")), 64)
-	rate, _ := strconv.ParseFloat(r.FormValue("rate"), 64)
-	years, _ := strconv.Atoi(r.FormValue("years"))
-	if amount <= 0 || years <= 0 {
-		h.renderError(w, "Please enter valid values", http.StatusBadRequest)
+	v := newFormValidator(r)
+	amount := v.money("amount", "Amount", maxAmount)
+	v.check(amount > 0, "amount", "Please enter a valid amount")
+	if r.FormValue("mode") == "historical" {
+		h.renderHistoricalInflation(w, v, amount)
+		return
+	}
+	rate := v.percent("rate", "Inflation rate", -10, maxRate)
+	years := v.requiredInteger("years", "Years", 1, maxYears)
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
 		return
 	}
 
@@ -2242,82 +2609,834 @@ func (h *Handler) CalculateInflation(w http.ResponseWriter, r *http.Request) {
 	h.renderPartial(w, "inflation-results", result)
 }
 
+// renderHistoricalInflation converts an amount between two dates with the
+// embedded CPI-U series.
+func (h *Handler) renderHistoricalInflation(w http.ResponseWriter, v *validator, amount float64) {
+	firstYear, latest := calc.CPIRange()
+	fromYear := v.requiredInteger("from_year", "Starting year", firstYear, latest.Year)
+	fromMonth := v.integer("from_month", "Starting month", 0, 12)
+	toYear := v.requiredInteger("to_year", "Ending year", firstYear, latest.Year)
+	toMonth := v.integer("to_month", "Ending month", 0, 12)
+	projectionRate := v.percent("projection_rate", "Projected inflation", -10, maxRate)
+	projectionYears := v.integer("projection_years", "Projection years", 0, maxYears)
+
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
+		return
+	}
+
+	inf, err := calc.AdjustForInflation(calc.InflationInput{
+		Amount:          amount,
+		From:            calc.CPIDate{Year: fromYear, Month: fromMonth},
+		To:              calc.CPIDate{Year: toYear, Month: toMonth},
+		ProjectionYears: projectionYears,
+		ProjectionRate:  projectionRate,
+	})
+	if err != nil {
+		h.renderError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	type projectionView struct {
+		Year                     int
+		CostFormatted            string
+		PurchasingPowerFormatted string
+	}
+	var projection []projectionView
+	for _, p := range inf.Projection {
+		projection = append(projection, projectionView{
+			Year:                     p.Year,
+			CostFormatted:            formatMoney(p.Cost.Dollars()),
+			PurchasingPowerFormatted: formatMoney(p.PurchasingPower.Dollars()),
+		})
+	}
+
+	result := map[string]interface{}{
+		"OriginalFormatted": formatMoney(inf.Amount.Dollars()),
+		"ValueFormatted":    formatMoney(inf.Value.Dollars()),
+		"From":              inf.From.String(),
+		"To":                inf.To.String(),
+		"FromCPI":           inf.FromCPI,
+		"ToCPI":             inf.ToCPI,
+		"Years":             inf.Years,
+		"CumulativePct":     inf.CumulativePct,
+		"AnnualizedPct":     inf.AnnualizedPct,
+		"ProjectionRate":    inf.ProjectionRate,
+		"Projection":        projection,
+	}
+	h.renderPartial(w, "inflation-history-results", result)
+}
+
 func (h *Handler) CalculateCompound(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		h.renderError(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
-	principal, _ := strconv.ParseFloat(cleanMoney(r.FormValue("principal")), 64)
-	monthly, _ := strconv.ParseFloat(cleanMoney(r.FormValue("monthly")), 64)
-	rate, _ := strconv.ParseFloat(r.FormValue("rate"), 64)
-	years, _ := strconv.Atoi(r.FormValue("years"))
-	if principal <= 0 || years <= 0 {
-		h.renderError(w, "Please enter valid values", http.StatusBadRequest)
+	v := newFormValidator(r)
+	principal := v.money("principal", "Starting amount", maxAmount)
+	monthly := v.money("monthly", "Monthly contribution", maxIncome/12)
+	rate := v.percent("rate", "Return rate", -maxRate, maxRate)
+	years := v.requiredInteger("years", "Years", 1, maxYears)
+	volatility := v.percent("volatility", "Volatility", 0, 100)
+	goal := v.money("goal", "Goal", maxAmount)
+	withdrawal := v.money("withdrawal", "Monthly withdrawal", maxIncome/12)
+	inflation := inflationAssumption(v)
+
+	v.check(principal > 0, "principal", "Please enter a starting amount")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
 		return
 	}
 
-	monthlyRate := rate / 100 / 12
-	months := years * 12
-	balance := principal
-	for m := 0; m < months; m++ {
-		balance = balance*(1+monthlyRate) + monthly
-	}
-	totalInvested := principal + monthly*float64(months)
-	interestEarned := balance - totalInvested
-	growthMult := balance / totalInvested
+	c := calc.CalculateCompound(calc.CompoundInput{
+		Principal:           principal,
+		MonthlyContribution: monthly,
+		ReturnRate:          rate,
+		Years:               years,
+		InflationRate:       inflation,
+	})
 
 	result := map[string]interface{}{
-		"FutureValueFormatted":    formatMoney(int(balance)),
-		"TotalInvestedFormatted":  formatMoney(int(totalInvested)),
-		"InterestEarnedFormatted": formatMoney(int(interestEarned)),
-		"GrowthMultiple":          fmt.Sprintf("%.1f", growthMult),
-		"Years":                   years,
+		"FutureValue":        formatDollars(c.FutureValue),
+		"TotalInvested":      formatDollars(c.TotalInvested),
+		"InterestEarned":     formatDollars(c.InterestEarned),
+		"GrowthMultiple":     fmt.Sprintf("%.1f", c.GrowthMultiple),
+		"RealGrowthMultiple": fmt.Sprintf("%.1f", c.RealGrowthMultiple),
+		"InflationRate":      inflation,
+		"Years":              years,
+	}
+
+	// Market returns vary year to year; show the range of likely outcomes
+	if volatility > 0 {
+		mc := calc.RunMonteCarlo(calc.MonteCarloInput{
+			InitialBalance:      principal,
+			MonthlyContribution: monthly,
+			MonthlyWithdrawal:   withdrawal,
+			Years:               years,
+			MeanReturn:          rate,
+			Volatility:          volatility,
+			InflationRate:       inflation,
+			Goal:                goal,
+		})
+
+		type bandView struct {
+			Year         int
+			P10Formatted string
+			P50Formatted string
+			P90Formatted string
+			P10Pct       float64
+			P50Pct       float64
+			SpreadPct    float64
+		}
+		var bands []bandView
+		for _, b := range mc.Bands {
+			if b.Year%5 != 0 && b.Year != years {
+				continue
+			}
+			bands = append(bands, bandView{
+				Year:         b.Year,
+				P10Formatted: formatMoney(b.P10.Dollars()),
+				P50Formatted: formatMoney(b.P50.Dollars()),
+				P90Formatted: formatMoney(b.P90.Dollars()),
+				P10Pct:       b.P10Pct,
+				P50Pct:       b.P50Pct,
+				SpreadPct:    b.P90Pct - b.P10Pct,
+			})
+		}
+
+		result["MonteCarlo"] = true
+		result["Simulations"] = formatMoney(mc.Simulations)
+		result["P10"] = dollarsView{Nominal: formatMoney(mc.P10.Dollars()), Real: formatMoney(mc.RealP10.Dollars())}
+		result["P50"] = dollarsView{Nominal: formatMoney(mc.P50.Dollars()), Real: formatMoney(mc.RealP50.Dollars())}
+		result["P90"] = dollarsView{Nominal: formatMoney(mc.P90.Dollars()), Real: formatMoney(mc.RealP90.Dollars())}
+		result["HasGoal"] = goal > 0
+		result["GoalFormatted"] = formatMoney(int(goal))
+		result["ProbabilityGoal"] = mc.ProbabilityGoal
+		result["HasWithdrawal"] = withdrawal > 0
+		result["WithdrawalFormatted"] = formatMoney(int(withdrawal))
+		result["ProbabilitySuccess"] = mc.ProbabilitySuccess
+		result["Bands"] = bands
 	}
 	h.renderPartial(w, "compound-results", result)
 }
 
-func (h *Handler) CalculateGig(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) TraditionalVsRoth(w http.ResponseWriter, r *http.Request) {
+	h.renderPage(w, PageMeta{
+		Title:       "Traditional vs Roth Calculator - Which Should You Choose? | Autolytiq",
+		Description: "Compare Traditional, Roth, and split retirement contributions. See your tax bracket now vs in retirement, today's take-home pay, and after-tax wealth.",
+		Canonical:   baseURL + "/traditional-vs-roth",
+	}, "traditional-vs-roth-content", map[string]interface{}{
+		"FilingStatus":  string(calc.FilingSingle),
+		"FilingOptions": filingOptions(),
+	})
+}
+
+func (h *Handler) CalculateRoth(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		h.renderError(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
 
-	gig1Income, _ := strconv.ParseFloat(cleanMoney(r.FormValue("gig1_income")), 64)
-	gig2Income, _ := strconv.ParseFloat(cleanMoney(r.FormValue("gig2_income")), 64)
-	milesDriven, _ := strconv.ParseFloat(cleanMoney(r.FormValue("miles_driven")), 64)
-	otherExpenses, _ := strconv.ParseFloat(cleanMoney(r.FormValue("other_expenses")), 64)
+	v := newFormValidator(r)
+	grossAnnual := v.money("gross_annual", "Gross income", maxIncome)
+	contribution := v.money("contribution", "Annual contribution", maxIncome)
+	stateTaxRate := v.percent("state_tax_rate", "State tax rate", 0, maxTaxRate)
+	years := v.requiredInteger("years", "Years", 1, maxYears)
+	returnRate := v.percent("return_rate", "Return rate", -maxRate, maxRate)
+	retirementIncome := v.money("retirement_income", "Retirement income", maxIncome)
+	retirementStateRate := v.percent("retirement_state_rate", "Retirement state tax rate", 0, maxTaxRate)
+	rothShare := v.percent("roth_share", "Roth share", 0, 100)
+	filingStatus := calc.FilingStatus(r.FormValue("filing_status"))
+
+	v.check(grossAnnual > 0, "gross_annual", "Please enter your income")
+	v.check(filingStatus == "" || filingStatus.Valid(), "filing_status", "Please choose a valid filing status")
+	v.check(contribution > 0, "contribution", "Please enter your annual contribution")
+	v.check(contribution <= grossAnnual || grossAnnual <= 0, "contribution", "Contribution can't be more than your income")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
+		return
+	}
+	if returnRate == 0 {
+		returnRate = 7
+	}
+
+	c := calc.CompareTraditionalRoth(calc.RothInput{
+		GrossAnnual:         grossAnnual,
+		StateTaxRate:        stateTaxRate,
+		Contribution:        contribution,
+		Years:               years,
+		ReturnRate:          returnRate,
+		RetirementIncome:    retirementIncome,
+		RetirementStateRate: retirementStateRate,
+		RothSharePct:        rothShare,
+		FilingStatus:        filingStatus,
+	})
 
-	totalYTD := gig1Income + gig2Income
-	if totalYTD <= 0 {
-		h.renderError(w, "Please enter at least one gig income source", http.StatusBadRequest)
+	type optionView struct {
+		Name                   string
+		IsBest                 bool
+		TaxSavingsFormatted    string
+		TakeHomeFormatted      string
+		AccountFormatted       string
+		SideAccountFormatted   string
+		TaxOnWithdrawFormatted string
+		WithdrawalTaxRate      float64
+		WealthFormatted        string
+	}
+	var options []optionView
+	for _, o := range c.Options {
+		options = append(options, optionView{
+			Name:                   o.Name,
+			IsBest:                 o.Name == c.Best,
+			TaxSavingsFormatted:    formatMoney(o.TaxSavingsToday.Dollars()),
+			TakeHomeFormatted:      formatMoney(o.TakeHomeAnnual.Dollars()),
+			AccountFormatted:       formatMoney((o.TraditionalValue + o.RothValue).Dollars()),
+			SideAccountFormatted:   formatMoney(o.SideAccountValue.Dollars()),
+			TaxOnWithdrawFormatted: formatMoney(o.TaxOnWithdrawals.Dollars()),
+			WithdrawalTaxRate:      o.WithdrawalTaxRate,
+			WealthFormatted:        formatMoney(o.AfterTaxWealth.Dollars()),
+		})
+	}
+
+	result := map[string]interface{}{
+		"Best":                  c.Best,
+		"AdvantageFormatted":    formatMoney(c.AdvantageOverNextBest.Dollars()),
+		"MarginalRateNow":       c.MarginalRateNow,
+		"RetirementRate":        c.RetirementRate,
+		"MonthlyDiffFormatted":  formatMoney(c.MonthlyTakeHomeDiff.Dollars()),
+		"ContributionFormatted": formatMoney(c.Contribution.Dollars()),
+		"ContributionCapped":    c.ContributionCapped,
+		"Years":                 years,
+		"Options":               options,
+	}
+	h.renderPartial(w, "roth-results", result)
+}
+
+func (h *Handler) FIRE(w http.ResponseWriter, r *http.Request) {
+	h.renderPage(w, PageMeta{
+		Title:       "FIRE Calculator - When Can You Retire Early? | Autolytiq",
+		Description: "Find your FI number and financial independence age. Compare Lean, Fat, Coast, and Barista FIRE and see how your savings rate changes your timeline.",
+		Canonical:   baseURL + "/fire",
+	}, "fire-content", nil)
+}
+
+func (h *Handler) CalculateFIRE(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	v := newFormValidator(r)
+	grossAnnual := v.money("gross_annual", "Annual income", maxIncome)
+	stateTaxRate := v.percent("state_tax_rate", "State tax rate", 0, maxTaxRate)
+	currentAge := v.integer("current_age", "Age", 1, 120)
+	netWorth := v.signedMoney("net_worth", "Net worth", maxAmount)
+	spending := v.money("annual_spending", "Annual spending", maxIncome)
+	savingsRate := v.percent("savings_rate", "Savings rate", 0, 100)
+	returnRate := v.percent("return_rate", "Return rate", -maxRate, maxRate)
+	inflation := v.percent("inflation", "Inflation", 0, maxRate)
+	withdrawalRate := v.percent("withdrawal_rate", "Withdrawal rate", 0, maxWithdrawalRate)
+
+	v.check(grossAnnual > 0, "gross_annual", "Please enter your annual income")
+	v.check(spending > 0 || savingsRate > 0, "annual_spending", "Please enter your annual spending or savings rate")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
+		return
+	}
+	if currentAge <= 0 {
+		currentAge = 30
+	}
+
+	tax := calc.CalculateTaxes(grossAnnual, 0, 0, stateTaxRate)
+	f := calc.CalculateFIRE(calc.FIREInput{
+		CurrentAge:     currentAge,
+		NetWorth:       netWorth,
+		AnnualSpending: spending,
+		SavingsRatePct: savingsRate,
+		ReturnRate:     returnRate,
+		InflationRate:  inflation,
+		WithdrawalRate: withdrawalRate,
+	}, tax)
+
+	type variantView struct {
+		Name            string
+		Description     string
+		TargetFormatted string
+		YearsToFI       int
+		FIAge           int
+		Reachable       bool
+	}
+	var variants []variantView
+	for _, v := range f.Variants {
+		variants = append(variants, variantView{
+			Name:            v.Name,
+			Description:     v.Description,
+			TargetFormatted: formatMoney(v.Target.Dollars()),
+			YearsToFI:       v.YearsToFI,
+			FIAge:           v.FIAge,
+			Reachable:       v.Reachable,
+		})
+	}
+
+	type sensitivityView struct {
+		SavingsRatePct    float64
+		SpendingFormatted string
+		FINumberFormatted string
+		YearsToFI         int
+		FIAge             int
+		Reachable         bool
+		Current           bool
+	}
+	var sensitivity []sensitivityView
+	for _, row := range f.Sensitivity {
+		sensitivity = append(sensitivity, sensitivityView{
+			SavingsRatePct:    row.SavingsRatePct,
+			SpendingFormatted: formatMoney(row.AnnualSpending.Dollars()),
+			FINumberFormatted: formatMoney(row.FINumber.Dollars()),
+			YearsToFI:         row.YearsToFI,
+			FIAge:             row.FIAge,
+			Reachable:         row.Reachable,
+			Current:           row.Current,
+		})
+	}
+
+	result := map[string]interface{}{
+		"FINumberFormatted": formatMoney(f.FINumber.Dollars()),
+		"YearsToFI":         f.YearsToFI,
+		"FIAge":             f.FIAge,
+		"Reachable":         f.Reachable,
+		"TakeHomeFormatted": formatMoney(f.TakeHomeAnnual.Dollars()),
+		"SpendingFormatted": formatMoney(f.AnnualSpending.Dollars()),
+		"SavingsFormatted":  formatMoney(f.AnnualSavings.Dollars()),
+		"SavingsRatePct":    f.SavingsRatePct,
+		"RealReturn":        f.RealReturn,
+		"WithdrawalRate":    f.WithdrawalRate,
+		"ProgressPercent":   f.ProgressPercent,
+		"CoastFormatted":    formatMoney(f.CoastNumber.Dollars()),
+		"Variants":          variants,
+		"Sensitivity":       sensitivity,
+	}
+	h.renderPartial(w, "fire-results", result)
+}
+
+func (h *Handler) CapitalGains(w http.ResponseWriter, r *http.Request) {
+	h.renderPage(w, PageMeta{
+		Title:       "Capital Gains Tax Calculator - Tax on Selling Stock | Autolytiq",
+		Description: "Estimate the tax on selling shares. See short- vs long-term rates stacked on your income, the 3.8% NIIT, state tax, and what waiting for long-term treatment saves.",
+		Canonical:   baseURL + "/capital-gains-tax",
+	}, "capital-gains-content", nil)
+}
+
+func (h *Handler) CalculateCapitalGains(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	v := newFormValidator(r)
+	shares := v.quantity("shares", "Shares", maxAmount)
+	salePrice := v.money("sale_price", "Sale price", maxAmount)
+	costBasis := v.money("cost_basis", "Cost basis", maxAmount)
+	monthsHeld := v.integer("months_held", "Months held", 0, maxYears*12)
+	grossAnnual := v.money("gross_annual", "Gross income", maxIncome)
+	stateTaxRate := v.percent("state_tax_rate", "State tax rate", 0, maxTaxRate)
+	stateExclusion := v.percent("state_exclusion", "State exclusion", 0, 100)
+
+	v.check(shares > 0, "shares", "Please enter the number of shares")
+	v.check(salePrice > 0, "sale_price", "Please enter the sale price")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
+		return
+	}
+
+	s := calc.CalculateSaleTax(calc.SaleInput{
+		Shares:     shares,
+		SalePrice:  salePrice,
+		CostBasis:  costBasis,
+		MonthsHeld: monthsHeld,
+		Other: calc.TaxInput{
+			GrossAnnual:           grossAnnual,
+			StateTaxRate:          stateTaxRate,
+			StateLTCGExclusionPct: stateExclusion,
+		},
+	})
+
+	result := map[string]interface{}{
+		"ProceedsFormatted":     formatMoney(s.Proceeds.Dollars()),
+		"BasisFormatted":        formatMoney(s.Basis.Dollars()),
+		"GainFormatted":         formatMoney(s.Gain.Dollars()),
+		"IsLoss":                s.Gain < 0,
+		"LossFormatted":         formatMoney((-s.Gain).Dollars()),
+		"LossSavesFormatted":    formatMoney((-s.TotalTax).Dollars()),
+		"LongTerm":              s.LongTerm,
+		"FederalFormatted":      formatMoney(s.FederalTax.Dollars()),
+		"NIITFormatted":         formatMoney(s.NIIT.Dollars()),
+		"HasNIIT":               s.NIIT > 0,
+		"StateFormatted":        formatMoney(s.StateTax.Dollars()),
+		"TotalFormatted":        formatMoney(s.TotalTax.Dollars()),
+		"EffectiveRate":         s.EffectiveRate,
+		"NetProceedsFormatted":  formatMoney(s.NetProceeds.Dollars()),
+		"MonthsUntilLongTerm":   s.MonthsUntilLongTerm,
+		"LongTermTaxFormatted":  formatMoney(s.LongTermTotalTax.Dollars()),
+		"WaitingSaves":          s.WaitingSaves > 0,
+		"WaitingSavesFormatted": formatMoney(s.WaitingSaves.Dollars()),
+	}
+	h.renderPartial(w, "capital-gains-results", result)
+}
+
+// taxProfile reads the optional ?filing= status and ?state= slug that
+// programmatic pages accept, defaulting to data.DefaultTaxProfile.
+func taxProfile(r *http.Request) data.TaxProfile {
+	return stateTaxProfile(r, r.URL.Query().Get("state"))
+}
+
+// stateTaxProfile is taxProfile for the state with the given slug, for pages
+// whose path already names the state.
+func stateTaxProfile(r *http.Request, stateSlug string) data.TaxProfile {
+	p := data.DefaultTaxProfile
+	if f := calc.FilingStatus(r.URL.Query().Get("filing")); f.Valid() {
+		p.FilingStatus = f
+	}
+	if s := data.GetState(stateSlug); s != nil {
+		p.StateTaxRate, p.StateCode = 0, ""
+		if s.HasStateTax {
+			p.StateTaxRate, p.StateCode = s.TopRate, s.Code
+		}
+	}
+	return p
+}
+
+type filingOption struct {
+	Value string
+	Label string
+}
+
+// filingOptions lists the supported filing statuses for pickers.
+func filingOptions() []filingOption {
+	opts := make([]filingOption, len(calc.FilingStatuses))
+	for i, f := range calc.FilingStatuses {
+		opts[i] = filingOption{Value: string(f), Label: f.Label()}
+	}
+	return opts
+}
+
+// cityLocation resolves a city or state slug into a cost-of-living location.
+// Cities pay their state's income tax plus any local wage tax.
+func cityLocation(slug string) (calc.Location, bool) {
+	if c := data.GetCity(slug); c != nil {
+		loc := calc.Location{
+			Name:         c.Name + ", " + c.StateCode,
+			CostOfLiving: float64(c.CostOfLiving),
+			LocalTaxRate: c.LocalTaxRate,
+		}
+		if s := data.GetState(c.StateSlug); s != nil && s.HasStateTax {
+			loc.StateTaxRate, loc.StateCode = s.TopRate, s.Code
+		}
+		return loc, true
+	}
+	if s := data.GetState(slug); s != nil {
+		loc := calc.Location{Name: s.Name, CostOfLiving: float64(s.CostOfLiving)}
+		if s.HasStateTax {
+			loc.StateTaxRate, loc.StateCode = s.TopRate, s.Code
+		}
+		return loc, true
+	}
+	return calc.Location{}, false
+}
+
+type locationOption struct {
+	Slug string
+	Name string
+}
+
+// locationOptions lists the cities (alphabetically) and states that
+// cityLocation can resolve, for location pickers.
+func locationOptions() (cities, states []locationOption) {
+	for _, c := range data.Cities {
+		cities = append(cities, locationOption{Slug: c.Slug, Name: c.Name + ", " + c.StateCode})
+	}
+	sort.Slice(cities, func(i, j int) bool { return cities[i].Name < cities[j].Name })
+	for _, slug := range data.AllStateSlugs() {
+		if s := data.GetState(slug); s != nil {
+			states = append(states, locationOption{Slug: s.Slug, Name: s.Name})
+		}
+	}
+	return cities, states
+}
+
+// compareCitiesPageData builds the shared data for the cost-of-living pages.
+func compareCitiesPageData(fromSlug, toSlug string, salary float64, status calc.FilingStatus, result map[string]interface{}) map[string]interface{} {
+	cities, states := locationOptions()
+
+	type pairLink struct {
+		Slug  string
+		Title string
+	}
+	var popular []pairLink
+	for _, p := range data.PopularCityPairs {
+		a, okA := cityLocation(p[0])
+		b, okB := cityLocation(p[1])
+		if okA && okB && !(p[0] == fromSlug && p[1] == toSlug) {
+			popular = append(popular, pairLink{Slug: data.CityPairSlug(p[0], p[1]), Title: a.Name + " vs " + b.Name})
+		}
+	}
+
+	return map[string]interface{}{
+		"Cities":          cities,
+		"States":          states,
+		"FromSlug":        fromSlug,
+		"ToSlug":          toSlug,
+		"SalaryFormatted": formatMoney(int(salary)),
+		"FilingOptions":   filingOptions(),
+		"FilingStatus":    string(status),
+		"Result":          result,
+		"Popular":         popular,
+	}
+}
+
+// compareCitiesResult formats a comparison for the compare-cities-results partial.
+func compareCitiesResult(c *calc.CostOfLivingComparison) map[string]interface{} {
+	type sideView struct {
+		Name             string
+		CostOfLiving     float64
+		Gross            string
+		FederalTax       string
+		StateTax         string
+		LocalTax         string
+		HasLocalTax      bool
+		FICATax          string
+		TotalTax         string
+		NetAnnual        string
+		NetMonthly       string
+		EffectiveTaxRate float64
+	}
+	side := func(t calc.LocationTaxes) sideView {
+		return sideView{
+			Name:             t.Name,
+			CostOfLiving:     t.CostOfLiving,
+			Gross:            formatMoney(t.Gross.Dollars()),
+			FederalTax:       formatMoney(t.FederalTax.Dollars()),
+			StateTax:         formatMoney(t.StateTax.Dollars()),
+			LocalTax:         formatMoney(t.LocalTax.Dollars()),
+			HasLocalTax:      t.LocalTax > 0,
+			FICATax:          formatMoney(t.FICATax.Dollars()),
+			TotalTax:         formatMoney(t.TotalTax.Dollars()),
+			NetAnnual:        formatMoney(t.NetAnnual.Dollars()),
+			NetMonthly:       formatMoney(t.NetMonthly.Dollars()),
+			EffectiveTaxRate: t.EffectiveTaxRate,
+		}
+	}
+
+	taxEffect := c.TaxEffect
+	if taxEffect < 0 {
+		taxEffect = -taxEffect
+	}
+	change := c.SalaryChange
+	if change < 0 {
+		change = -change
+	}
+	return map[string]interface{}{
+		"From":                      side(c.From),
+		"To":                        side(c.To),
+		"EquivalentFormatted":       formatMoney(c.EquivalentSalary.Dollars()),
+		"PreTaxEquivalentFormatted": formatMoney(c.PreTaxEquivalent.Dollars()),
+		"Raise":                     c.SalaryChange > 0,
+		"ChangeFormatted":           formatMoney(change.Dollars()),
+		"PercentChange":             math.Abs(c.PercentChange),
+		"Pricier":                   c.CostDifference >= 0,
+		"CostDifference":            math.Abs(c.CostDifference),
+		"TaxesCostMore":             c.TaxEffect > 0,
+		"TaxEffectFormatted":        formatMoney(taxEffect.Dollars()),
+	}
+}
+
+func (h *Handler) CompareCitiesIndex(w http.ResponseWriter, r *http.Request) {
+	h.renderPage(w, PageMeta{
+		Title:       "Cost of Living Salary Comparison - City vs City After Tax | Autolytiq",
+		Description: "What salary do you need to move? Compare cost of living between cities and states, including federal, state, and local income tax on both sides.",
+		Canonical:   baseURL + "/compare-cities",
+	}, "compare-cities-content", compareCitiesPageData("san-francisco-ca", "austin-tx", 100000, calc.FilingSingle, nil))
+}
+
+func (h *Handler) CompareCities(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("pair")
+	fromSlug, toSlug, ok := data.SplitCityPair(slug)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	from, okFrom := cityLocation(fromSlug)
+	to, okTo := cityLocation(toSlug)
+	if !okFrom || !okTo {
+		http.NotFound(w, r)
 		return
 	}
 
-	startDate, err := time.Parse("2006-01-02", r.FormValue("start_date"))
+	salary := 100000.0
+	if raw := cleanMoney(r.URL.Query().Get("salary")); raw != "" {
+		s, err := strconv.ParseFloat(raw, 64)
+		v := &validator{}
+		v.check(err == nil && s > 0, "salary", "Please enter a valid salary")
+		v.checkMoney("salary", "Salary", s, maxIncome)
+		if !v.valid() {
+			http.Error(w, v.errors[0].Message, http.StatusBadRequest)
+			return
+		}
+		salary = s
+	}
+	status := taxProfile(r).FilingStatus
+	c, err := calc.CompareCostOfLiving(salary, status, from, to)
 	if err != nil {
-		h.renderError(w, "Please enter a valid start date", http.StatusBadRequest)
+		http.NotFound(w, r)
+		return
+	}
+
+	pageData := compareCitiesPageData(fromSlug, toSlug, salary, status, compareCitiesResult(c))
+	pageData["FromName"] = from.Name
+	pageData["ToName"] = to.Name
+	pageData["EquivalentFormatted"] = formatMoney(c.EquivalentSalary.Dollars())
+
+	h.renderPage(w, PageMeta{
+		Title:       fmt.Sprintf("%s vs %s Cost of Living - Salary Comparison | Autolytiq", from.Name, to.Name),
+		Description: fmt.Sprintf("$%s in %s is worth about $%s in %s after cost of living and federal, state, and local taxes.", formatMoney(int(salary)), from.Name, formatMoney(c.EquivalentSalary.Dollars()), to.Name),
+		Canonical:   baseURL + "/compare-cities/" + slug,
+	}, "compare-cities-content", pageData)
+}
+
+func (h *Handler) CalculateCompareCities(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
 
-	checkDate, err := time.Parse("2006-01-02", r.FormValue("check_date"))
+	v := newFormValidator(r)
+	salary := v.money("salary", "Salary", maxIncome)
+	from, okFrom := cityLocation(r.FormValue("from"))
+	to, okTo := cityLocation(r.FormValue("to"))
+	filingStatus := calc.FilingStatus(r.FormValue("filing_status"))
+
+	v.check(salary > 0, "salary", "Please enter your current salary")
+	v.check(filingStatus == "" || filingStatus.Valid(), "filing_status", "Please choose a valid filing status")
+	v.check(okFrom, "from", "Please choose where you live now")
+	v.check(okTo, "to", "Please choose where you're moving")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
+		return
+	}
+
+	c, err := calc.CompareCostOfLiving(salary, filingStatus, from, to)
 	if err != nil {
-		h.renderError(w, "Please enter a valid as-of date", http.StatusBadRequest)
+		h.renderError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result := compareCitiesResult(c)
+	if r.FormValue("from") != r.FormValue("to") {
+		result["PairSlug"] = data.CityPairSlug(r.FormValue("from"), r.FormValue("to"))
+		if filingStatus.Valid() && filingStatus != calc.FilingSingle {
+			result["PairQuery"] = "?filing=" + string(filingStatus)
+		}
+	}
+	h.renderPartial(w, "compare-cities-results", result)
+}
+
+func (h *Handler) CalculateOffers(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
 
-	// Project annual income
-	incomeResult, err := calc.CalculateIncome(totalYTD, startDate, checkDate)
+	// Each offer is a column of parallel form values
+	v := newFormValidator(r)
+	var offers []calc.JobOffer
+	for i := range r.Form["base_salary"] {
+		// Default names follow the form column, even when a blank column is skipped
+		name := v.value("offer_name", i)
+		if name == "" {
+			name = fmt.Sprintf("Offer %c", 'A'+i)
+		}
+		base := v.moneyAt("base_salary", i, "Base salary", maxIncome)
+		if base <= 0 {
+			continue
+		}
+		loc, ok := cityLocation(v.value("location", i))
+		v.checkAt(ok, "location", i, "Please choose a location")
+		offer := calc.JobOffer{
+			Name:            name,
+			BaseSalary:      base,
+			Bonus:           v.moneyAt("bonus", i, "Bonus", maxIncome),
+			EquityGrant:     v.moneyAt("equity_grant", i, "Equity grant", maxAmount),
+			ContributionPct: v.percentAt("contribution_pct", i, "401(k) contribution", 0, 100),
+			HealthPremium:   v.moneyAt("health_premium", i, "Health premium", maxIncome/12) * 12,
+			CommuteCost:     v.moneyAt("commute_cost", i, "Commute cost", maxIncome/12) * 12,
+			PTODays:         v.integerAt("pto_days", i, "PTO days", 0, 365),
+			Location:        loc,
+		}
+		var vested float64
+		for _, pct := range strings.Split(v.value("vesting", i), ",") {
+			if pct = strings.TrimSpace(pct); pct == "" {
+				continue
+			}
+			f, err := strconv.ParseFloat(pct, 64)
+			v.checkAt(err == nil && f >= 0, "vesting", i, "Please choose a vesting schedule")
+			offer.EquityVesting = append(offer.EquityVesting, f)
+			vested += f
+		}
+		v.checkAt(vested <= 100, "vesting", i, "Vesting can't add up to more than 100%")
+		match := v.percentAt("match_percent", i, "Employer match", 0, 200)
+		upTo := v.percentAt("match_up_to", i, "Match limit", 0, 100)
+		if match > 0 && upTo > 0 {
+			offer.EmployerMatch = []calc.MatchTier{{MatchPercent: match, UpToPercent: upTo}}
+		}
+		offers = append(offers, offer)
+	}
+
+	v.check(len(offers) >= 2, "base_salary", "Please enter a base salary for at least two offers")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
+		return
+	}
+	c, err := calc.CompareOffers(offers)
 	if err != nil {
 		h.renderError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Calculate deductions
-	mileageDeduction := milesDriven * 0.67
-	totalExpenses := mileageDeduction + otherExpenses
-	selfEmploymentTax := float64(incomeResult.GrossAnnual) * 0.153
-	netAfterExpenses := float64(incomeResult.GrossAnnual) - totalExpenses
-	netAfterTax := netAfterExpenses - selfEmploymentTax
+	type offerView struct {
+		Name             string
+		Location         string
+		CostOfLiving     float64
+		Best             bool
+		BaseSalary       string
+		Bonus            string
+		Equity           string
+		EquityFirstYear  string
+		VestingYears     int
+		EmployerMatch    string
+		Contribution401k string
+		TotalComp        string
+		FederalTax       string
+		StateTax         string
+		LocalTax         string
+		HasLocalTax      bool
+		FICATax          string
+		HealthPremium    string
+		CommuteCost      string
+		TakeHome         string
+		PTODays          int
+		PTOValue         string
+		AfterTaxComp     string
+		AdjustedComp     string
+	}
+	var views []offerView
+	for i, o := range c.Offers {
+		views = append(views, offerView{
+			Name:             o.Name,
+			Location:         offers[i].Location.Name,
+			CostOfLiving:     offers[i].Location.CostOfLiving,
+			Best:             o.Best,
+			BaseSalary:       formatMoney(o.BaseSalary.Dollars()),
+			Bonus:            formatMoney(o.Bonus.Dollars()),
+			Equity:           formatMoney(o.Equity.Dollars()),
+			EquityFirstYear:  formatMoney(o.EquityFirstYear.Dollars()),
+			VestingYears:     o.VestingYears,
+			EmployerMatch:    formatMoney(o.EmployerMatch.Dollars()),
+			Contribution401k: formatMoney(o.Contribution401k.Dollars()),
+			TotalComp:        formatMoney(o.TotalComp.Dollars()),
+			FederalTax:       formatMoney(o.Taxes.FederalTax.Dollars()),
+			StateTax:         formatMoney(o.Taxes.StateTax.Dollars()),
+			LocalTax:         formatMoney(o.Taxes.LocalTax.Dollars()),
+			HasLocalTax:      o.Taxes.LocalTax > 0,
+			FICATax:          formatMoney(o.Taxes.FICATax.Dollars()),
+			HealthPremium:    formatMoney(o.HealthPremium.Dollars()),
+			CommuteCost:      formatMoney(o.CommuteCost.Dollars()),
+			TakeHome:         formatMoney(o.Taxes.NetAnnual.Dollars()),
+			PTODays:          o.PTODays,
+			PTOValue:         formatMoney(o.PTOValue.Dollars()),
+			AfterTaxComp:     formatMoney(o.AfterTaxComp.Dollars()),
+			AdjustedComp:     formatMoney(o.AdjustedComp.Dollars()),
+		})
+	}
+
+	h.renderPartial(w, "offer-comparison-results", map[string]interface{}{
+		"Offers":          views,
+		"BestName":        c.Offers[c.Best].Name,
+		"MarginFormatted": formatMoney(c.Margin.Dollars()),
+	})
+}
+
+func (h *Handler) CalculateGig(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	v := newFormValidator(r)
+	gig1Income := v.money("gig1_income", "Income", maxIncome)
+	gig2Income := v.money("gig2_income", "Income", maxIncome)
+	milesDriven := v.quantity("miles_driven", "Miles driven", 1_000_000)
+	otherExpenses := v.money("other_expenses", "Other expenses", maxIncome)
+	startDate := v.date("start_date", "start date")
+	checkDate := v.date("check_date", "as-of date")
+
+	totalYTD := gig1Income + gig2Income
+	v.check(totalYTD > 0, "gig1_income", "Please enter at least one gig income source")
+	v.check(!checkDate.Before(startDate), "check_date", "As-of date can't be before the start date")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
+		return
+	}
+
+	g, err := calc.CalculateGig(calc.GigInput{
+		YTDIncome:     totalYTD,
+		MilesDriven:   milesDriven,
+		OtherExpenses: otherExpenses,
+		StartDate:     startDate,
+		CheckDate:     checkDate,
+	})
+	if err != nil {
+		h.renderError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	result := map[string]interface{}{
 		"Gig1Name":          r.FormValue("gig1_name"),
@@ -2325,18 +3444,18 @@ func (h *Handler) CalculateGig(w http.ResponseWriter, r *http.Request) {
 		"Gig2Name":          r.FormValue("gig2_name"),
 		"Gig2Income":        int(gig2Income),
 		"TotalYTD":          int(totalYTD),
-		"GrossAnnual":       incomeResult.GrossAnnual,
-		"GrossMonthly":      incomeResult.GrossMonthly,
-		"DaysWorked":        incomeResult.DaysWorked,
+		"GrossAnnual":       g.GrossAnnual.Dollars(),
+		"GrossMonthly":      g.GrossMonthly.Dollars(),
+		"DaysWorked":        g.DaysWorked,
 		"MilesDriven":       int(milesDriven),
-		"MileageDeduction":  int(mileageDeduction),
+		"MileageDeduction":  g.MileageDeduction.Dollars(),
 		"OtherExpenses":     int(otherExpenses),
-		"TotalExpenses":     int(totalExpenses),
-		"SelfEmploymentTax": int(selfEmploymentTax),
-		"NetAfterExpenses":  int(netAfterExpenses),
-		"NetAfterTax":       int(netAfterTax),
-		"NetMonthly":        int(netAfterTax / 12),
-		"EffectiveHourly":   int(netAfterTax / 2080),
+		"TotalExpenses":     g.TotalExpenses.Dollars(),
+		"SelfEmploymentTax": g.SelfEmploymentTax.Dollars(),
+		"NetAfterExpenses":  g.NetAfterExpenses.Dollars(),
+		"NetAfterTax":       g.NetAfterTax.Dollars(),
+		"NetMonthly":        g.NetMonthly.Dollars(),
+		"EffectiveHourly":   g.EffectiveHourly.Dollars(),
 	}
 	h.renderPartial(w, "gig-results", result)
 }
@@ -2347,55 +3466,83 @@ func (h *Handler) CalculateStreams(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	type Stream struct {
-		Name   string
-		Annual int
-	}
-
-	var streams []Stream
-	var totalAnnual float64
-
-	for i := 1; i <= 4; i++ {
-		name := r.FormValue(fmt.Sprintf("stream%d_name", i))
-		incomeStr := cleanMoney(r.FormValue(fmt.Sprintf("stream%d_income", i)))
-		income, _ := strconv.ParseFloat(incomeStr, 64)
-		if income > 0 {
-			streams = append(streams, Stream{Name: name, Annual: int(income)})
-			totalAnnual += income
+	// Each stream is a row of parallel form values
+	v := newFormValidator(r)
+	var streams []calc.IncomeStream
+	for i := range r.Form["stream_income"] {
+		income := v.moneyAt("stream_income", i, "Income", maxIncome)
+		if income <= 0 {
+			continue
+		}
+		stream := calc.IncomeStream{
+			Name:   v.value("stream_name", i),
+			Type:   calc.StreamType(v.value("stream_type", i)),
+			Annual: income,
+		}
+		if stream.Type == "capital_gains_short" {
+			stream.Type, stream.ShortTerm = calc.StreamCapitalGains, true
 		}
+		v.checkAt(stream.Type.Valid(), "stream_type", i, "Please choose a type")
+		if stream.Name == "" {
+			stream.Name = stream.Type.Label()
+		}
+		stream.Expenses = v.moneyAt("stream_expenses", i, "Expenses", maxIncome)
+		stream.Depreciation = v.moneyAt("stream_depreciation", i, "Depreciation", maxIncome)
+		streams = append(streams, stream)
 	}
+	stateTaxRate := v.percent("state_tax_rate", "State tax rate", 0, maxTaxRate)
 
-	if totalAnnual <= 0 {
-		h.renderError(w, "Please enter at least one income stream", http.StatusBadRequest)
+	v.check(len(streams) > 0, "stream_income", "Please enter at least one income stream")
+	if !v.valid() {
+		h.renderFieldErrors(w, v)
 		return
 	}
 
-	monthlyTotal := int(totalAnnual / 12)
+	s := calc.CalculateStreams(calc.StreamsInput{Streams: streams, StateTaxRate: stateTaxRate})
 
-	// Add percentage of total to each stream
 	type StreamResult struct {
-		Name    string
-		Annual  int
-		Monthly int
-		Percent int
+		Name          string
+		TypeLabel     string
+		Annual        int
+		Monthly       int
+		Percent       int
+		Tax           int
+		AfterTax      int
+		EffectiveRate float64
+		SuspendedLoss int
 	}
 	var streamResults []StreamResult
-	for _, s := range streams {
-		pct := int((float64(s.Annual) / totalAnnual) * 100)
+	for i, sr := range s.Streams {
+		label := sr.Type.Label()
+		if streams[i].ShortTerm {
+			label += " (short-term)"
+		}
 		streamResults = append(streamResults, StreamResult{
-			Name:    s.Name,
-			Annual:  s.Annual,
-			Monthly: s.Annual / 12,
-			Percent: pct,
+			Name:          sr.Name,
+			TypeLabel:     label,
+			Annual:        sr.Annual.Dollars(),
+			Monthly:       sr.AfterTax.Div(12).Dollars(),
+			Percent:       int(sr.Percent),
+			Tax:           sr.Tax.Dollars(),
+			AfterTax:      sr.AfterTax.Dollars(),
+			EffectiveRate: sr.EffectiveRate,
+			SuspendedLoss: sr.SuspendedLoss.Dollars(),
 		})
 	}
 
 	result := map[string]interface{}{
-		"Streams":      streamResults,
-		"TotalAnnual":  int(totalAnnual),
-		"TotalMonthly": monthlyTotal,
-		"TotalWeekly":  int(totalAnnual / 52),
-		"StreamCount":  len(streams),
+		"Streams":         streamResults,
+		"TotalAnnual":     s.TotalAnnual.Dollars(),
+		"TotalMonthly":    s.TotalAnnual.Div(12).Dollars(),
+		"TotalWeekly":     s.TotalAnnual.Div(52).Dollars(),
+		"StreamCount":     len(streams),
+		"TotalTax":        s.TotalTax.Dollars(),
+		"TotalAfterTax":   s.TotalAfterTax.Dollars(),
+		"MonthlyAfterTax": s.MonthlyAfterTax.Dollars(),
+		"EffectiveRate":   s.EffectiveRate,
+		"SETax":           s.Taxes.SelfEmploymentTax.Dollars(),
+		"NIIT":            s.Taxes.NIIT.Dollars(),
+		"SuspendedLoss":   s.SuspendedLoss.Dollars(),
 	}
 	h.renderPartial(w, "streams-results", result)
 }
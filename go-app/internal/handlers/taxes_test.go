@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/autolytiq/income-calculator/internal/calc"
+	"github.com/autolytiq/income-calculator/internal/data"
+)
+
+// pageProfiles are the ?filing= and ?state= queries the programmatic pages
+// are checked under, with the state code taxProfile should resolve.
+var pageProfiles = []struct {
+	filing calc.FilingStatus
+	state  string
+	code   string
+}{
+	{"", "", ""},
+	{calc.FilingMarriedJoint, "california", "CA"},
+	{calc.FilingHeadHousehold, "new-york", "NY"},
+	{calc.FilingSingle, "illinois", "IL"},
+	{calc.FilingMarriedJoint, "texas", ""},
+}
+
+// pageProfile returns the profile a programmatic page builds for its query,
+// failing if the state didn't resolve so the checks can't pass vacuously.
+func pageProfile(t *testing.T, filing calc.FilingStatus, state, code string) data.TaxProfile {
+	t.Helper()
+	q := url.Values{"filing": {string(filing)}, "state": {state}}
+	p := taxProfile(httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil))
+	if p.StateCode != code || (filing != "" && p.FilingStatus != filing) {
+		t.Fatalf("?%s: expected %s filing %s, got %+v", q.Encode(), code, filing, p)
+	}
+	return p
+}
+
+// calculator returns what /api/calculate-taxes computes when a page's salary
+// and profile are posted to it, the way the state page's form does.
+func calculator(t *testing.T, salary int, p data.TaxProfile) *calc.TaxBreakdown {
+	t.Helper()
+	form := url.Values{
+		"gross_annual":   {strconv.Itoa(salary)},
+		"filing_status":  {string(p.FilingStatus)},
+		"state_tax_rate": {strconv.FormatFloat(p.StateTaxRate, 'f', -1, 64)},
+	}
+	if calc.HasStateBrackets(p.StateCode) {
+		form.Set("state_code", p.StateCode)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/api/calculate-taxes", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := r.ParseForm(); err != nil {
+		t.Fatal(err)
+	}
+	v := newFormValidator(r)
+	in := taxFormInput(r, v)
+	if !v.valid() {
+		t.Fatalf("calculator rejected %v: %+v", form, v.errors)
+	}
+	return calc.ExplainTaxes(in)
+}
+
+func TestAffordMatchesTaxCalculator(t *testing.T) {
+	for _, pp := range pageProfiles {
+		p := pageProfile(t, pp.filing, pp.state, pp.code)
+		for _, salary := range data.SalaryLevels {
+			d := data.CalculateAffordabilityFor(salary, p)
+			want := calculator(t, salary, p)
+			if d.TakeHome != want.NetAnnual.Dollars() {
+				t.Errorf("%+v: afford page for %d shows take-home %d, calculator %v", p, salary, d.TakeHome, want.NetAnnual)
+			}
+		}
+	}
+}
+
+func TestSalaryMatchesTaxCalculator(t *testing.T) {
+	for _, pp := range pageProfiles {
+		p := pageProfile(t, pp.filing, pp.state, pp.code)
+		for _, slug := range data.AllSalarySlugs() {
+			ad := data.GetAffordBySlug(data.ClosestAffordSlug(data.GetSalary(slug).Median))
+			if ad == nil {
+				t.Fatalf("%s: no afford page for its median", slug)
+			}
+			d := data.CalculateAffordabilityFor(ad.Salary, p)
+			want := calculator(t, ad.Salary, p)
+			if d.TakeHome != want.NetAnnual.Dollars() {
+				t.Errorf("%+v: %s sidebar for %d shows take-home %d, calculator %v", p, slug, ad.Salary, d.TakeHome, want.NetAnnual)
+			}
+		}
+	}
+}
+
+func TestHourlyMatchesTaxCalculator(t *testing.T) {
+	for _, pp := range pageProfiles {
+		p := pageProfile(t, pp.filing, pp.state, pp.code)
+		for _, rate := range data.HourlyRates {
+			d := data.CalculateHourlyFor(rate, p)
+			want := calculator(t, rate*2080, p)
+			if d.FederalTax != want.FederalTax.Dollars() || d.StateTax != want.StateTax.Dollars() || d.FICA != want.FICATax.Dollars() {
+				t.Errorf("%+v: hourly page for $%d shows taxes %d/%d/%d, calculator %v/%v/%v", p, rate,
+					d.FederalTax, d.StateTax, d.FICA, want.FederalTax, want.StateTax, want.FICATax)
+			}
+			if d.TakeHome != want.NetAnnual.Dollars() {
+				t.Errorf("%+v: hourly page for $%d shows take-home %d, calculator %v", p, rate, d.TakeHome, want.NetAnnual)
+			}
+
+			// Salary levels that are exact full-time hourly wages appear on both
+			if a := data.CalculateAffordabilityFor(d.Annual, p); a.TakeHome != d.TakeHome {
+				t.Errorf("%+v: afford page for %d shows take-home %d, hourly page %d", p, d.Annual, a.TakeHome, d.TakeHome)
+			}
+		}
+	}
+}
+
+func TestStateTaxMatchesTaxCalculator(t *testing.T) {
+	graduated := 0
+	for _, filing := range calc.FilingStatuses {
+		r := httptest.NewRequest(http.MethodGet, "/taxes/x?filing="+string(filing), nil)
+		for _, slug := range data.AllStateSlugs() {
+			s := data.GetState(slug)
+			p := stateTaxProfile(r, slug)
+			if calc.HasStateBrackets(p.StateCode) {
+				graduated++
+			}
+			est := p.Taxes(s.AverageSalary)
+			want := calculator(t, s.AverageSalary, p)
+			if est.StateTax != want.StateTax || est.NetAnnual != want.NetAnnual {
+				t.Errorf("%s, %s: state page shows %v state tax and %v take-home, calculator %v and %v",
+					s.Name, filing, est.StateTax, est.NetAnnual, want.StateTax, want.NetAnnual)
+			}
+		}
+	}
+	if graduated == 0 {
+		t.Error("expected some state pages on graduated brackets")
+	}
+}
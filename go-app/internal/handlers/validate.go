@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sane upper bounds for calculator inputs. Anything larger is almost
+// certainly a typo (an extra zero, a pasted account number).
+const (
+	maxAmount         = 1_000_000_000 // any single dollar amount
+	maxIncome         = 100_000_000   // annual income
+	maxRate           = 30            // loan and investment rates, percent
+	maxTaxRate        = 20            // state and local income tax rates, percent
+	maxWithdrawalRate = 10            // safe withdrawal rates, percent
+	maxYears          = 100
+	maxLoanMonths     = 120
+)
+
+// fieldError is a problem with one input. Field is the form input's name,
+// or the JSON field's path for the API; Index picks out one of several form
+// inputs sharing that name, such as the rows of the offer or income stream
+// forms.
+type fieldError struct {
+	Field   string `json:"field"`
+	Index   int    `json:"-"`
+	Message string `json:"message"`
+}
+
+// validator collects a message for every calculator input that is
+// malformed, out of range or missing, instead of silently treating bad input
+// as zero. Form validators parse form values, where blank optional inputs
+// parse as zero so handlers can keep applying their defaults; the API
+// decodes JSON itself and uses the check methods on the decoded values.
+type validator struct {
+	form   url.Values
+	errors []fieldError
+}
+
+func newFormValidator(r *http.Request) *validator {
+	return &validator{form: r.Form}
+}
+
+// value returns the i'th value submitted for name, trimmed.
+func (v *validator) value(name string, i int) string {
+	values := v.form[name]
+	if i >= len(values) {
+		return ""
+	}
+	return strings.TrimSpace(values[i])
+}
+
+// has reports whether name already has an error; only the first problem
+// with an input is shown.
+func (v *validator) has(name string, i int) bool {
+	for _, e := range v.errors {
+		if e.Field == name && e.Index == i {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAt records message against the i'th name input unless ok.
+func (v *validator) checkAt(ok bool, name string, i int, message string) {
+	if !ok && !v.has(name, i) {
+		v.errors = append(v.errors, fieldError{Field: name, Index: i, Message: message})
+	}
+}
+
+// check records message against the name input unless ok.
+func (v *validator) check(ok bool, name, message string) {
+	v.checkAt(ok, name, 0, message)
+}
+
+func (v *validator) float(name string, i int, raw, label string) (float64, bool) {
+	if raw == "" {
+		return 0, true
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		v.checkAt(false, name, i, label+" must be a number")
+		return 0, false
+	}
+	return f, true
+}
+
+// quantityAt parses the i'th name input as an amount between 0 and max,
+// ignoring thousands separators. unit prefixes max in the message.
+func (v *validator) quantityAt(name string, i int, label, unit string, max float64) float64 {
+	f, ok := v.float(name, i, cleanMoney(v.value(name, i)), label)
+	if ok {
+		v.checkQuantity(name, i, label, unit, f, max)
+	}
+	return f
+}
+
+// checkQuantity checks that the i'th name value is between 0 and max.
+func (v *validator) checkQuantity(name string, i int, label, unit string, f, max float64) {
+	v.checkAt(f >= 0, name, i, label+" can't be negative")
+	v.checkAt(f <= max, name, i, fmt.Sprintf("%s can't be more than %s%s", label, unit, formatMoney(int(max))))
+}
+
+// checkMoney checks that the name value is a dollar amount between 0 and max.
+func (v *validator) checkMoney(name, label string, f, max float64) {
+	v.checkQuantity(name, 0, label, "$", f, max)
+}
+
+// moneyAt parses the i'th name input as a dollar amount between 0 and max.
+func (v *validator) moneyAt(name string, i int, label string, max float64) float64 {
+	return v.quantityAt(name, i, label, "$", max)
+}
+
+// money parses the name input as a dollar amount between 0 and max.
+func (v *validator) money(name, label string, max float64) float64 {
+	return v.moneyAt(name, 0, label, max)
+}
+
+// quantity parses the name input as a non-dollar amount, such as a share
+// count or miles driven, between 0 and max.
+func (v *validator) quantity(name, label string, max float64) float64 {
+	return v.quantityAt(name, 0, label, "", max)
+}
+
+// signedMoney parses the name input as a dollar amount that may be negative,
+// such as a net worth, between -max and max.
+func (v *validator) signedMoney(name, label string, max float64) float64 {
+	f, ok := v.float(name, 0, cleanMoney(v.value(name, 0)), label)
+	if ok {
+		v.checkSignedMoney(name, label, f, max)
+	}
+	return f
+}
+
+// checkSignedMoney checks that the name value is between -max and max.
+func (v *validator) checkSignedMoney(name, label string, f, max float64) {
+	v.check(math.Abs(f) <= max, name, fmt.Sprintf("%s must be between -$%s and $%s", label, formatMoney(int(max)), formatMoney(int(max))))
+}
+
+// percentAt parses the i'th name input as a percentage between min and max.
+func (v *validator) percentAt(name string, i int, label string, min, max float64) float64 {
+	f, ok := v.float(name, i, strings.TrimSuffix(v.value(name, i), "%"), label)
+	if ok {
+		v.checkPercent(name, i, label, f, min, max)
+	}
+	return f
+}
+
+// checkPercent checks that the i'th name value is a percentage between min
+// and max.
+func (v *validator) checkPercent(name string, i int, label string, f, min, max float64) {
+	v.checkAt(f >= min && f <= max, name, i, fmt.Sprintf("%s must be between %g%% and %g%%", label, min, max))
+}
+
+// percent parses the name input as a percentage between min and max.
+func (v *validator) percent(name, label string, min, max float64) float64 {
+	return v.percentAt(name, 0, label, min, max)
+}
+
+// integerAt parses the i'th name input as a whole number between min and max.
+func (v *validator) integerAt(name string, i int, label string, min, max int) int {
+	raw := v.value(name, i)
+	if raw == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		v.checkAt(false, name, i, label+" must be a whole number")
+		return 0
+	}
+	v.checkInteger(name, i, label, n, min, max)
+	return n
+}
+
+// checkInteger checks that the i'th name value is between min and max.
+func (v *validator) checkInteger(name string, i int, label string, n, min, max int) {
+	v.checkAt(n >= min && n <= max, name, i, fmt.Sprintf("%s must be between %d and %d", label, min, max))
+}
+
+// integer parses the name input as a whole number between min and max.
+func (v *validator) integer(name, label string, min, max int) int {
+	return v.integerAt(name, 0, label, min, max)
+}
+
+// requiredInteger is integer for inputs that must be filled in, such as loan
+// terms and projection lengths, rather than falling back to a default.
+func (v *validator) requiredInteger(name, label string, min, max int) int {
+	v.check(v.value(name, 0) != "", name, label+" is required")
+	return v.integer(name, label, min, max)
+}
+
+// date parses the name input as a YYYY-MM-DD date. Dates are always
+// required.
+func (v *validator) date(name, label string) time.Time {
+	return v.parseDate(name, label, v.value(name, 0))
+}
+
+// parseDate parses raw as the name input's YYYY-MM-DD date.
+func (v *validator) parseDate(name, label, raw string) time.Time {
+	d, err := time.Parse("2006-01-02", raw)
+	v.check(err == nil, name, "Please enter a valid "+label)
+	return d
+}
+
+// valid reports whether every input passed.
+func (v *validator) valid() bool {
+	return len(v.errors) == 0
+}
+
+// renderFieldErrors renders the validator's messages. The partial lists them
+// in the results area and the page script moves each one next to its input.
+func (h *Handler) renderFieldErrors(w http.ResponseWriter, v *validator) {
+	var buf bytes.Buffer
+	if err := h.tmpl.ExecuteTemplate(&buf, "field-errors", v.errors); err != nil {
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	buf.WriteTo(w)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"io"
 	"log"
+	"mime"
 	"net"
 	"net/http"
 	"runtime/debug"
@@ -224,7 +225,9 @@ func extractIP(r *http.Request) string {
 // It sets a csrf_token cookie on GET requests and validates the token on POST
 // requests by comparing the cookie value with the X-CSRF-Token header or
 // _csrf form field. Webhook endpoints are exempted since they use their own
-// signature verification.
+// signature verification, and JSON requests to the API are exempted since it
+// is stateless and a cross-site form can't send application/json. Anything
+// else posted to the API still needs a token.
 func CSRFToken(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Skip CSRF for webhook endpoints (they verify signatures independently)
@@ -232,6 +235,11 @@ func CSRFToken(next http.Handler) http.Handler {
 			next.ServeHTTP(w, r)
 			return
 		}
+		// Skip CSRF for the JSON API (no cookies)
+		if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); strings.HasPrefix(r.URL.Path, "/api/v1/") && mt == "application/json" {
+			next.ServeHTTP(w, r)
+			return
+		}
 
 		if r.Method == http.MethodGet || r.Method == http.MethodHead {
 			// Set CSRF cookie if not present
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCSRFTokenAPI(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := CSRFToken(ok)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{"JSON needs no token", "application/json", `{"gross_annual": 100000}`, http.StatusOK},
+		{"JSON with a charset", "application/json; charset=utf-8", `{}`, http.StatusOK},
+		{"form post is still checked", "application/x-www-form-urlencoded", "gross_annual=100000", http.StatusForbidden},
+		{"multipart post is still checked", "multipart/form-data; boundary=x", "--x--", http.StatusForbidden},
+		{"no content type is still checked", "", `{}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/taxes", strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			r.Header.Set("Content-Type", tt.contentType)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, w.Code)
+		}
+	}
+}
@@ -82,6 +82,11 @@ func main() {
 	mux.HandleFunc("GET /income-streams", h.IncomeStreams)
 	mux.HandleFunc("GET /rent-vs-buy", h.RentVsBuy)
 	mux.HandleFunc("GET /inflation", h.Inflation)
+	mux.HandleFunc("GET /traditional-vs-roth", h.TraditionalVsRoth)
+	mux.HandleFunc("GET /fire", h.FIRE)
+	mux.HandleFunc("GET /capital-gains-tax", h.CapitalGains)
+	mux.HandleFunc("GET /compare-cities", h.CompareCitiesIndex)
+	mux.HandleFunc("GET /compare-cities/{pair}", h.CompareCities)
 	mux.HandleFunc("GET /share", h.Share)
 	mux.HandleFunc("GET /income-calculator", h.CalcVariantIndex)
 	mux.HandleFunc("GET /income-calculator/{variant}", h.CalcVariant)
@@ -106,6 +111,7 @@ func main() {
 	// HTMX API endpoints (partials)
 	mux.HandleFunc("POST /api/calculate-income", h.CalculateIncome)
 	mux.HandleFunc("POST /api/calculate-budget", h.CalculateBudget)
+	mux.HandleFunc("POST /api/calculate-emergency-fund", h.CalculateEmergencyFund)
 	mux.HandleFunc("POST /api/calculate-mortgage", h.CalculateMortgage)
 	mux.HandleFunc("POST /api/calculate-auto", h.CalculateAuto)
 	mux.HandleFunc("POST /api/calculate-taxes", h.CalculateTaxes)
@@ -114,12 +120,29 @@ func main() {
 	mux.HandleFunc("POST /api/calculate-rent-vs-buy", h.CalculateRentVsBuy)
 	mux.HandleFunc("POST /api/calculate-inflation", h.CalculateInflation)
 	mux.HandleFunc("POST /api/calculate-compound", h.CalculateCompound)
+	mux.HandleFunc("POST /api/calculate-roth", h.CalculateRoth)
+	mux.HandleFunc("POST /api/calculate-fire", h.CalculateFIRE)
+	mux.HandleFunc("POST /api/calculate-capital-gains", h.CalculateCapitalGains)
+	mux.HandleFunc("POST /api/compare-cities", h.CalculateCompareCities)
+	mux.HandleFunc("POST /api/compare-offers", h.CalculateOffers)
 	mux.HandleFunc("POST /api/quiz-answer", h.QuizAnswer)
 	mux.HandleFunc("POST /api/subscribe", h.Subscribe)
 	mux.HandleFunc("POST /api/create-checkout", h.CreateCheckout)
 	mux.HandleFunc("POST /api/webhooks/stripe", h.StripeWebhook)
 	mux.HandleFunc("GET /unsubscribe/{token}", h.Unsubscribe)
 
+	// Versioned JSON API
+	mux.HandleFunc("POST /api/v1/income", h.APIIncome)
+	mux.HandleFunc("POST /api/v1/taxes", h.APITaxes)
+	mux.HandleFunc("POST /api/v1/mortgage", h.APIMortgage)
+	mux.HandleFunc("POST /api/v1/auto", h.APIAuto)
+	mux.HandleFunc("POST /api/v1/budget", h.APIBudget)
+	mux.HandleFunc("POST /api/v1/rent-vs-buy", h.APIRentVsBuy)
+	mux.HandleFunc("POST /api/v1/compound", h.APICompound)
+	mux.HandleFunc("POST /api/v1/inflation", h.APIInflation)
+	mux.HandleFunc("POST /api/v1/gig", h.APIGig)
+	mux.HandleFunc("POST /api/v1/streams", h.APIStreams)
+
 	// Admin
 	mux.HandleFunc("GET /admin", h.AdminDashboard)
 	mux.HandleFunc("GET /admin/login", h.AdminLogin)